@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// APIKeyHeader is the header API keys are sent in, see the APIKeyAuth
+// security scheme.
+const APIKeyHeader = "X-API-KEY"
+
+// Browsers can't set headers on WebSocket handshakes, so WebSocket clients may
+// send their API key as a subprotocol, `apikey.<key>`, in the
+// Sec-WebSocket-Protocol header instead, or in the apiKey query parameter.
+const (
+	APIKeyProtocolPrefix = "apikey."
+	APIKeyQueryParam     = "apiKey"
+)
+
+type apiKeyContextKey struct{}
+
+// APIKeyAuth returns a middleware that requires a valid API key, see
+// requestAPIKey, for every operation secured by the APIKeyAuth security
+// scheme, with the scopes the operation requires. It's meant to be passed to
+// NewRouter, whose middlewares run after the generated code has marked the
+// request as secured.
+func APIKeyAuth(keys *KeyStore) MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scopes, secured := r.Context().Value(APIKeyAuthScopes).([]string)
+			if !secured {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := requestAPIKey(r)
+			if len(key) == 0 {
+				writeError(w, r, ErrMissingAPIKey)
+				return
+			}
+
+			apiKey, err := keys.Verify(key)
+			if err != nil {
+				writeError(w, r, err)
+				return
+			}
+
+			for _, scope := range scopes {
+				if !apiKey.HasScope(scope) {
+					err := fmt.Errorf("%w `%s`", ErrMissingScope, scope)
+					writeError(w, r, withDetails(err, map[string]interface{}{"scope": scope}))
+					return
+				}
+			}
+
+			ctx := context.WithValue(r.Context(), apiKeyContextKey{}, apiKey)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// requestAPIKey returns the API key sent in the X-API-KEY header of r or, if r
+// is a WebSocket handshake without one, the one sent as a subprotocol or in
+// the query.
+func requestAPIKey(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); len(key) > 0 || !websocket.IsWebSocketUpgrade(r) {
+		return key
+	}
+
+	for _, protocol := range websocket.Subprotocols(r) {
+		if key, ok := strings.CutPrefix(protocol, APIKeyProtocolPrefix); ok {
+			return key
+		}
+	}
+
+	return r.URL.Query().Get(APIKeyQueryParam)
+}
+
+// APIKeyFromContext returns the API key a request was authenticated with, or
+// nil if it wasn't.
+func APIKeyFromContext(ctx context.Context) *APIKey {
+	apiKey, _ := ctx.Value(apiKeyContextKey{}).(*APIKey)
+	return apiKey
+}
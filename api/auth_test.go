@@ -0,0 +1,208 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestAPIKeyAuth(t *testing.T) {
+	t.Parallel()
+
+	store := NewKeyStore(path.Join(t.TempDir(), "api-keys.json"))
+	newKey := func(scopes ...string) string {
+		key, _, err := store.Create("test", scopes)
+		if err != nil {
+			t.Fatalf("expected no error, got `%v`", err)
+		}
+		return key
+	}
+	readKey := newKey(ScopeRead)
+	moderationKey := newKey(ScopeRead, ScopeModeration)
+	adminKey := newKey(ScopeAdmin)
+
+	registry := newFakeRegistry()
+	registry.servers["survival"] = nil
+	handler := NewRouter(NewServerController(registry), APIKeyAuth(store))
+
+	testCases := []struct {
+		name        string
+		method      string
+		path        string
+		body        string
+		key         string
+		wantStatus  int
+		wantCode    ErrorCode
+		wantMessage string
+	}{
+		{
+			name:        "missing key",
+			method:      http.MethodGet,
+			path:        "/servers",
+			wantStatus:  http.StatusUnauthorized,
+			wantCode:    Unauthorized,
+			wantMessage: "missing API key",
+		},
+		{
+			name:        "invalid key",
+			method:      http.MethodGet,
+			path:        "/servers",
+			key:         "mcsc_0000000000000000_secret",
+			wantStatus:  http.StatusUnauthorized,
+			wantCode:    Unauthorized,
+			wantMessage: "invalid API key",
+		},
+		{name: "read scope", method: http.MethodGet, path: "/servers", key: readKey, wantStatus: http.StatusOK},
+		{
+			name:        "missing admin scope",
+			method:      http.MethodPost,
+			path:        "/servers",
+			body:        `{"id": "lobby"}`,
+			key:         moderationKey,
+			wantStatus:  http.StatusForbidden,
+			wantCode:    Forbidden,
+			wantMessage: "API key lacks the scope `admin`",
+		},
+		{
+			name:        "missing lifecycle scope",
+			method:      http.MethodPost,
+			path:        "/servers/survival/start",
+			key:         readKey,
+			wantStatus:  http.StatusForbidden,
+			wantCode:    Forbidden,
+			wantMessage: "API key lacks the scope `lifecycle`",
+		},
+		{
+			name:        "missing console scope",
+			method:      http.MethodGet,
+			path:        "/servers/survival/console/history",
+			key:         moderationKey,
+			wantStatus:  http.StatusForbidden,
+			wantCode:    Forbidden,
+			wantMessage: "API key lacks the scope `console`",
+		},
+		{
+			name:        "missing config scope",
+			method:      http.MethodPut,
+			path:        "/servers/survival/args",
+			key:         moderationKey,
+			wantStatus:  http.StatusForbidden,
+			wantCode:    Forbidden,
+			wantMessage: "API key lacks the scope `config`",
+		},
+		{name: "admin scope", method: http.MethodPost, path: "/servers", body: `{"id": "lobby"}`, key: adminKey, wantStatus: http.StatusCreated},
+		{name: "admin grants every scope", method: http.MethodGet, path: "/servers/survival", key: adminKey, wantStatus: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			if len(tc.key) > 0 {
+				r.Header.Set("X-API-KEY", tc.key)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d", tc.wantStatus, w.Code)
+			}
+			if len(tc.wantMessage) == 0 {
+				return
+			}
+
+			var e Error
+			if err := json.NewDecoder(w.Body).Decode(&e); err != nil {
+				t.Fatalf("expected no error, got `%v`", err)
+			}
+			if e.Code != tc.wantCode || e.Message != tc.wantMessage {
+				t.Fatalf("expected error `%s` with message `%s`, got `%s` with `%s`", tc.wantCode, tc.wantMessage, e.Code, e.Message)
+			}
+			if len(e.RequestId) == 0 {
+				t.Fatal("expected the error to have a request ID")
+			}
+		})
+	}
+}
+
+func TestWebSocketAPIKey(t *testing.T) {
+	t.Parallel()
+
+	store := NewKeyStore(path.Join(t.TempDir(), "api-keys.json"))
+	key, _, err := store.Create("test", []string{ScopeRead})
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+
+	registry := newFakeRegistry()
+	registry.servers["survival"] = &fakeEventsServer{events: make(chan ConfigEvent)}
+	ts := httptest.NewServer(NewRouter(NewServerController(registry), APIKeyAuth(store)))
+	t.Cleanup(ts.Close)
+	eventsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/servers/survival/events"
+
+	testCases := []struct {
+		name         string
+		query        string
+		header       http.Header
+		wantStatus   int
+		wantProtocol string
+	}{
+		{name: "header", header: http.Header{"X-API-KEY": {key}}, wantStatus: http.StatusSwitchingProtocols},
+		{
+			name:         "subprotocol",
+			header:       http.Header{"Sec-WebSocket-Protocol": {WebSocketProtocol + ", " + APIKeyProtocolPrefix + key}},
+			wantStatus:   http.StatusSwitchingProtocols,
+			wantProtocol: WebSocketProtocol,
+		},
+		{name: "query parameter", query: "?apiKey=" + key, wantStatus: http.StatusSwitchingProtocols},
+		{name: "missing key", wantStatus: http.StatusUnauthorized},
+		{name: "invalid subprotocol key", header: http.Header{"Sec-WebSocket-Protocol": {APIKeyProtocolPrefix + "mcsc_0000000000000000_secret"}}, wantStatus: http.StatusUnauthorized},
+		{name: "same origin", query: "?apiKey=" + key, header: http.Header{"Origin": {ts.URL}}, wantStatus: http.StatusSwitchingProtocols},
+		{name: "other origin", query: "?apiKey=" + key, header: http.Header{"Origin": {"https://example.com"}}, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			conn, resp, err := websocket.DefaultDialer.Dial(eventsURL+tc.query, tc.header)
+			if conn != nil {
+				defer conn.Close()
+			}
+			if resp == nil {
+				t.Fatalf("expected a response, got error `%v`", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d", tc.wantStatus, resp.StatusCode)
+			}
+			if got := resp.Header.Get("Sec-WebSocket-Protocol"); got != tc.wantProtocol {
+				t.Fatalf("expected subprotocol `%s`, got `%s`", tc.wantProtocol, got)
+			}
+		})
+	}
+}
+
+func TestAPIKeyQueryParamOnlyForWebSockets(t *testing.T) {
+	t.Parallel()
+
+	store := NewKeyStore(path.Join(t.TempDir(), "api-keys.json"))
+	key, _, err := store.Create("test", []string{ScopeRead})
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	handler := NewRouter(NewServerController(newFakeRegistry()), APIKeyAuth(store))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/servers?apiKey="+key, nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
@@ -0,0 +1,81 @@
+package api
+
+import "net/http"
+
+// ListBackups implements ServerInterface.
+func (s *ServerController) ListBackups(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	backups, err := msi.Backups()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, backups)
+}
+
+// CreateBackup implements ServerInterface.
+func (s *ServerController) CreateBackup(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	backup, err := msi.CreateBackup()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, backup)
+}
+
+// DeleteBackup implements ServerInterface.
+func (s *ServerController) DeleteBackup(w http.ResponseWriter, r *http.Request, id string, backupID string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	if err := msi.DeleteBackup(backupID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetBackupSchedule implements ServerInterface.
+func (s *ServerController) GetBackupSchedule(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	schedule := msi.BackupSchedule()
+	if schedule == nil {
+		writeError(w, r, ErrNilConfig)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, schedule)
+}
+
+// PutBackupSchedule implements ServerInterface.
+func (s *ServerController) PutBackupSchedule(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	var schedule BackupSchedule
+	if !decodeJSON(w, r, &schedule) {
+		return
+	}
+
+	update(w, r, msi, func() error { return msi.SetBackupSchedule(&schedule) })
+}
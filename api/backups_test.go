@@ -0,0 +1,187 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeBackupServer struct {
+	MinecraftServerInterface
+
+	backups   BackupList
+	createErr error
+	schedule  *BackupSchedule
+	saved     bool
+}
+
+func (f *fakeBackupServer) Backups() (*BackupList, error) { return &f.backups, nil }
+
+func (f *fakeBackupServer) CreateBackup() (*Backup, error) {
+	if f.createErr != nil {
+		return nil, f.createErr
+	}
+
+	backup := Backup{Id: "20261018T120000Z", Size: 1024, Time: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC), Worlds: []string{"world"}}
+	f.backups = append(f.backups, backup)
+
+	return &backup, nil
+}
+
+func (f *fakeBackupServer) DeleteBackup(id string) error {
+	for i, backup := range f.backups {
+		if backup.Id == id {
+			f.backups = append(f.backups[:i], f.backups[i+1:]...)
+			return nil
+		}
+	}
+
+	return ErrBackupNotFound
+}
+
+func (f *fakeBackupServer) BackupSchedule() *BackupSchedule { return f.schedule }
+
+func (f *fakeBackupServer) SetBackupSchedule(schedule *BackupSchedule) error {
+	if schedule.Enabled && schedule.Cron == nil && schedule.IntervalMinutes == nil {
+		return ValidationError{{Err: ErrInvalidSchedule, Field: "cron", Reason: "or intervalMinutes must be set while the schedule is enabled"}}
+	}
+	f.schedule = schedule
+
+	return nil
+}
+
+func (f *fakeBackupServer) SaveConfigs() error {
+	f.saved = true
+	return nil
+}
+
+func TestBackups(t *testing.T) {
+	t.Parallel()
+
+	msi := &fakeBackupServer{}
+	handler := newTestHandler(msi)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/servers/test/backups", nil))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	var created Backup
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/servers/test/backups", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var list BackupList
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if want := (BackupList{created}); !reflect.DeepEqual(want, list) {
+		t.Fatalf("expected backups `%+v`, got `%+v`", want, list)
+	}
+
+	for _, wantStatus := range []int{http.StatusNoContent, http.StatusNotFound} {
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/servers/test/backups/"+created.Id, nil))
+		if w.Code != wantStatus {
+			t.Fatalf("expected status %d, got %d", wantStatus, w.Code)
+		}
+	}
+}
+
+func TestCreateBackupErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		err        error
+		wantStatus int
+		wantCode   ErrorCode
+	}{
+		{err: ErrBackupInProgress, wantStatus: http.StatusConflict, wantCode: BackupInProgress},
+		{err: ErrNoWorlds, wantStatus: http.StatusConflict, wantCode: NoWorlds},
+		{err: ErrCommandTimeout, wantStatus: http.StatusGatewayTimeout, wantCode: CommandTimeout},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(string(tc.wantCode), func(t *testing.T) {
+			t.Parallel()
+
+			handler := newTestHandler(&fakeBackupServer{createErr: tc.err})
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/servers/test/backups", nil))
+
+			if w.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d", tc.wantStatus, w.Code)
+			}
+			var got Error
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("expected no error, got `%v`", err)
+			}
+			if got.Code != tc.wantCode {
+				t.Fatalf("expected code `%s`, got `%s`", tc.wantCode, got.Code)
+			}
+		})
+	}
+}
+
+func TestBackupSchedule(t *testing.T) {
+	t.Parallel()
+
+	msi := &fakeBackupServer{schedule: &BackupSchedule{Enabled: false}}
+	handler := newTestHandler(msi)
+
+	testCases := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCode   ErrorCode
+	}{
+		{name: "malformed", body: `{"enabled":`, wantStatus: http.StatusBadRequest, wantCode: InvalidRequest},
+		{name: "invalid", body: `{"enabled": true}`, wantStatus: http.StatusBadRequest, wantCode: InvalidBackupSchedule},
+		{name: "valid", body: `{"enabled": true, "cron": "@daily", "retention": {"hourly": 0, "daily": 7, "weekly": 4}}`, wantStatus: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/servers/test/backups/schedule", strings.NewReader(tc.body)))
+		if w.Code != tc.wantStatus {
+			t.Fatalf("%s: expected status %d, got %d", tc.name, tc.wantStatus, w.Code)
+		}
+		if tc.wantStatus == http.StatusOK {
+			continue
+		}
+
+		var got Error
+		if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+			t.Fatalf("expected no error, got `%v`", err)
+		}
+		if got.Code != tc.wantCode {
+			t.Fatalf("%s: expected code `%s`, got `%s`", tc.name, tc.wantCode, got.Code)
+		}
+	}
+	if !msi.saved {
+		t.Fatal("expected the backup schedule to be saved")
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/servers/test/backups/schedule", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var got BackupSchedule
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if !reflect.DeepEqual(*msi.schedule, got) {
+		t.Fatalf("expected backup schedule `%+v`, got `%+v`", *msi.schedule, got)
+	}
+}
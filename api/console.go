@@ -0,0 +1,171 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// time allowed to write a message to a console client
+	consoleWriteWait = 10 * time.Second
+	// time allowed to read the next pong message from a console client
+	consolePongWait = 60 * time.Second
+	// how often console clients are pinged, must be less than consolePongWait
+	consolePingPeriod = (consolePongWait * 9) / 10
+	// number of lines returned from the console history if no limit is given
+	defaultConsoleHistoryLimit = 100
+)
+
+// WebSocketProtocol is the subprotocol of the console and events WebSockets.
+// Clients that send their API key as a subprotocol offer it along with the
+// key, so that the handshake selects it instead of echoing the key back.
+const WebSocketProtocol = "go-mcsc"
+
+var consoleUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	Subprotocols:    []string{WebSocketProtocol},
+	CheckOrigin:     sameOrigin,
+	Error: func(w http.ResponseWriter, r *http.Request, status int, reason error) {
+		if status == http.StatusForbidden {
+			writeError(w, r, ErrOriginForbidden)
+			return
+		}
+		writeError(w, r, invalidRequest(reason.Error()))
+	},
+}
+
+// sameOrigin allows WebSocket handshakes without an Origin header, which only
+// browsers send, and those from pages served by the host the handshake was
+// sent to, like a dashboard served alongside the API. Pages on other sites
+// can't open the WebSockets from a browser.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if len(origin) == 0 {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// GetConsole implements ServerInterface.
+func (s *ServerController) GetConsole(w http.ResponseWriter, r *http.Request, id string, params GetConsoleParams) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	conn, err := consoleUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader has already replied with an HTTP error
+		log.Println("error upgrading console connection:", err)
+		return
+	}
+	defer conn.Close()
+
+	// subscribe before reading the history so no lines are missed in between
+	lines, cancel := msi.SubscribeConsole()
+	defer cancel()
+
+	var lastSeq int64
+	if params.Since != nil {
+		for _, line := range msi.ConsoleHistory(*params.Since, 0) {
+			_ = conn.SetWriteDeadline(time.Now().Add(consoleWriteWait))
+			if err := conn.WriteJSON(line); err != nil {
+				return
+			}
+			lastSeq = line.Seq
+		}
+	}
+
+	go readConsoleCommands(msi, conn, cancel)
+
+	ticker := time.NewTicker(consolePingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				_ = conn.WriteControl(
+					websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+					time.Now().Add(consoleWriteWait),
+				)
+				return
+			}
+			if line.Seq <= lastSeq {
+				// already replayed from the console history
+				continue
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(consoleWriteWait))
+			if err := conn.WriteJSON(line); err != nil {
+				return
+			}
+		case <-ticker.C:
+			_ = conn.SetWriteDeadline(time.Now().Add(consoleWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// GetConsoleHistory implements ServerInterface.
+func (s *ServerController) GetConsoleHistory(w http.ResponseWriter, r *http.Request, id string, params GetConsoleHistoryParams) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	var since int64
+	if params.Since != nil {
+		since = *params.Since
+	}
+	limit := defaultConsoleHistoryLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if since < 0 || limit < 1 {
+		writeError(w, r, invalidRequest("`since` must not be negative and `limit` must be positive"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, msi.ConsoleHistory(since, limit))
+}
+
+// readConsoleCommands passes every text message sent by a console client to
+// the Minecraft server console until the client disconnects, at which point
+// the client's console subscription is cancelled.
+func readConsoleCommands(msi MinecraftServerInterface, conn *websocket.Conn, cancel func()) {
+	defer cancel()
+
+	_ = conn.SetReadDeadline(time.Now().Add(consolePongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(consolePongWait))
+	})
+
+	for {
+		messageType, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if messageType != websocket.TextMessage {
+			continue
+		}
+
+		if err := msi.SendCommand(string(message)); err != nil {
+			log.Println("error sending console command:", err)
+		}
+	}
+}
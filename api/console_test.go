@@ -0,0 +1,177 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type fakeConsoleServer struct {
+	MinecraftServerInterface
+
+	history  []ConsoleLine
+	lines    chan ConsoleLine
+	commands chan string
+}
+
+func (f *fakeConsoleServer) ConsoleHistory(since int64, limit int) []ConsoleLine {
+	lines := make([]ConsoleLine, 0)
+	for _, line := range f.history {
+		if line.Seq > since {
+			lines = append(lines, line)
+		}
+	}
+	if limit > 0 && len(lines) > limit {
+		lines = lines[len(lines)-limit:]
+	}
+
+	return lines
+}
+
+func (f *fakeConsoleServer) SendCommand(cmd string) error {
+	f.commands <- cmd
+	return nil
+}
+
+func (f *fakeConsoleServer) SubscribeConsole() (<-chan ConsoleLine, func()) {
+	var once sync.Once
+	return f.lines, func() {
+		once.Do(func() { close(f.lines) })
+	}
+}
+
+func TestGetConsole(t *testing.T) {
+	t.Parallel()
+
+	msi := &fakeConsoleServer{
+		history: []ConsoleLine{
+			{Seq: 1, Stream: Stdout, Text: "[12:00:00] [Server thread/INFO]: Starting minecraft server version 1.20.6"},
+			{Seq: 2, Stream: Stdout, Text: "[12:00:01] [Server thread/INFO]: Preparing level \"world\""},
+		},
+		lines:    make(chan ConsoleLine, 2),
+		commands: make(chan string, 1),
+	}
+	ts := httptest.NewServer(newTestHandler(msi))
+	defer ts.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial(
+		"ws"+strings.TrimPrefix(ts.URL, "http")+"/servers/test/console?since=1", nil,
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	defer conn.Close()
+
+	// the second line was already replayed from the history
+	msi.lines <- msi.history[1]
+	msi.lines <- ConsoleLine{Seq: 3, Stream: Stdout, Text: "[12:00:02] [Server thread/INFO]: Done (1.0s)!"}
+
+	for _, wantSeq := range []int64{2, 3} {
+		var got ConsoleLine
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		if err := conn.ReadJSON(&got); err != nil {
+			t.Fatalf("expected no error, got `%v`", err)
+		}
+		if got.Seq != wantSeq {
+			t.Fatalf("expected line with sequence number %d, got `%v`", wantSeq, got)
+		}
+	}
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte("list")); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	select {
+	case cmd := <-msi.commands:
+		if cmd != "list" {
+			t.Fatalf("expected command `list`, got `%s`", cmd)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for command")
+	}
+}
+
+func TestGetConsoleHistory(t *testing.T) {
+	t.Parallel()
+
+	msi := &fakeConsoleServer{
+		history: []ConsoleLine{
+			{Seq: 1, Stream: Stdout, Text: "one"},
+			{Seq: 2, Stream: Stderr, Text: "two"},
+			{Seq: 3, Stream: Stdout, Text: "three"},
+		},
+	}
+	handler := newTestHandler(msi)
+
+	testCases := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantSeqs   []int64
+	}{
+		{
+			name:       "default limit",
+			query:      "",
+			wantStatus: http.StatusOK,
+			wantSeqs:   []int64{1, 2, 3},
+		},
+		{
+			name:       "since",
+			query:      "?since=1",
+			wantStatus: http.StatusOK,
+			wantSeqs:   []int64{2, 3},
+		},
+		{
+			name:       "limit",
+			query:      "?limit=1",
+			wantStatus: http.StatusOK,
+			wantSeqs:   []int64{3},
+		},
+		{
+			name:       "invalid limit",
+			query:      "?limit=0",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "malformed since",
+			query:      "?since=abc",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/servers/test/console/history"+tc.query, nil))
+
+			if w.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d", tc.wantStatus, w.Code)
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+
+			var lines []ConsoleLine
+			if err := json.NewDecoder(w.Body).Decode(&lines); err != nil {
+				t.Fatalf("expected no error, got `%v`", err)
+			}
+			gotSeqs := make([]int64, 0, len(lines))
+			for _, line := range lines {
+				gotSeqs = append(gotSeqs, line.Seq)
+			}
+			if !reflect.DeepEqual(tc.wantSeqs, gotSeqs) {
+				t.Fatalf("expected lines `%v`, got `%v`", tc.wantSeqs, gotSeqs)
+			}
+		})
+	}
+}
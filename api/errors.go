@@ -0,0 +1,236 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Errors returned by a MinecraftServerInterface. The minecraft package
+// exports them under the same names.
+var (
+	ErrNilConfig            = errors.New("config object was not initialized")
+	ErrFilepathsNotProvided = errors.New("filepaths for configuration files not provided")
+
+	ErrAlreadyOp            = errors.New("player is already a server operator")
+	ErrNotInOps             = errors.New("player not in server operator list")
+	ErrPlayerAlreadyAllowed = errors.New("player is already in server allowlist")
+	ErrPlayerNotInAllowlist = errors.New("player is not in server allowlist")
+	ErrInvalidIP            = errors.New("invalid IP address")
+	ErrIPAlreadyBanned      = errors.New("IP is already banned")
+	ErrNotInBannedIPs       = errors.New("IP was not in ban list")
+	ErrNotInBannedPlayers   = errors.New("player not in banned players list")
+	ErrPlayerAlreadyBanned  = errors.New("player is already banned")
+	ErrPlayerNotFound       = errors.New("player does not exist")
+	ErrCommandTimeout       = errors.New("timed out waiting for the minecraft server to respond to command")
+	ErrConsoleNotAttached   = errors.New("console is not attached to a minecraft server process")
+
+	ErrVersionUnsupported = errors.New("unsupported server version passed")
+	ErrCatalogRefresh     = errors.New("failed to refresh version catalog")
+	ErrInvalidMemory      = errors.New("invalid memory arguments")
+	ErrInvalidArgument    = errors.New("invalid server argument")
+	ErrUnknownPreset      = errors.New("unknown JVM preset")
+	ErrInvalidProperty    = errors.New("invalid server property")
+
+	ErrServerRunning    = errors.New("minecraft server process is already running")
+	ErrServerNotRunning = errors.New("minecraft server process is not running")
+	ErrDownloadFailed   = errors.New("failed to download server jar")
+	ErrChecksumMismatch = errors.New("checksum of downloaded server jar does not match")
+	ErrNoJavaRuntime    = errors.New("no compatible java runtime found")
+
+	ErrBackupNotFound   = errors.New("backup not found")
+	ErrBackupInProgress = errors.New("a backup of the minecraft server is already in progress")
+	ErrNoWorlds         = errors.New("minecraft server has no worlds to back up")
+	ErrInvalidSchedule  = errors.New("invalid backup schedule")
+)
+
+// Errors of the API itself.
+var (
+	ErrInvalidRequest   = errors.New("invalid request")
+	ErrRouteNotFound    = errors.New("route not found")
+	ErrMissingAPIKey    = errors.New("missing API key")
+	ErrMissingScope     = errors.New("API key lacks the scope")
+	ErrOriginForbidden  = errors.New("WebSocket connections from other origins are not allowed")
+	ErrQueryUnavailable = errors.New("query is unavailable")
+	ErrInternal         = errors.New("internal server error")
+)
+
+// errorTranslations maps errors to the status code and ErrorCode they're
+// replied with, see the Error schema. The first match wins, so errors that
+// wrap other errors to give them context come first. Errors that aren't in it
+// are replied to as internal errors.
+var errorTranslations = []struct {
+	err    error
+	status int
+	code   ErrorCode
+}{
+	{ErrInvalidRequest, http.StatusBadRequest, InvalidRequest},
+	{ErrRouteNotFound, http.StatusNotFound, NotFound},
+	{ErrMissingAPIKey, http.StatusUnauthorized, Unauthorized},
+	{ErrInvalidAPIKey, http.StatusUnauthorized, Unauthorized},
+	{ErrMissingScope, http.StatusForbidden, Forbidden},
+	{ErrOriginForbidden, http.StatusForbidden, Forbidden},
+	{ErrQueryUnavailable, http.StatusServiceUnavailable, QueryUnavailable},
+	{ErrCatalogRefresh, http.StatusBadGateway, VersionCatalogUnavailable},
+
+	{ErrServerNotFound, http.StatusNotFound, ServerNotFound},
+	{ErrServerExists, http.StatusConflict, ServerExists},
+	{ErrInvalidServerID, http.StatusBadRequest, InvalidServerId},
+	{ErrInvalidServer, http.StatusBadRequest, InvalidServer},
+	{ErrServerInUse, http.StatusConflict, ServerInUse},
+	{ErrPortInUse, http.StatusConflict, PortInUse},
+
+	{ErrPlayerNotFound, http.StatusNotFound, PlayerNotFound},
+	{ErrNotInOps, http.StatusNotFound, NotAnOperator},
+	{ErrAlreadyOp, http.StatusConflict, AlreadyAnOperator},
+	{ErrPlayerNotInAllowlist, http.StatusNotFound, NotAllowed},
+	{ErrPlayerAlreadyAllowed, http.StatusConflict, AlreadyAllowed},
+	{ErrNotInBannedPlayers, http.StatusNotFound, PlayerNotBanned},
+	{ErrPlayerAlreadyBanned, http.StatusConflict, PlayerAlreadyBanned},
+	{ErrNotInBannedIPs, http.StatusNotFound, IpNotBanned},
+	{ErrIPAlreadyBanned, http.StatusConflict, IpAlreadyBanned},
+	{ErrInvalidIP, http.StatusBadRequest, InvalidIp},
+	{ErrCommandTimeout, http.StatusGatewayTimeout, CommandTimeout},
+
+	{ErrVersionUnsupported, http.StatusBadRequest, UnsupportedVersion},
+	{ErrInvalidMemory, http.StatusBadRequest, InvalidArguments},
+	{ErrInvalidArgument, http.StatusBadRequest, InvalidArguments},
+	{ErrUnknownPreset, http.StatusBadRequest, InvalidArguments},
+	{ErrInvalidProperty, http.StatusBadRequest, InvalidProperties},
+
+	{ErrServerRunning, http.StatusConflict, ServerRunning},
+	{ErrServerNotRunning, http.StatusConflict, ServerNotRunning},
+	{ErrConsoleNotAttached, http.StatusConflict, ServerNotRunning},
+	{ErrDownloadFailed, http.StatusBadGateway, DownloadFailed},
+	{ErrChecksumMismatch, http.StatusBadGateway, DownloadFailed},
+	{ErrNoJavaRuntime, http.StatusInternalServerError, NoJavaRuntime},
+
+	{ErrBackupNotFound, http.StatusNotFound, BackupNotFound},
+	{ErrBackupInProgress, http.StatusConflict, BackupInProgress},
+	{ErrNoWorlds, http.StatusConflict, NoWorlds},
+	{ErrInvalidSchedule, http.StatusBadRequest, InvalidBackupSchedule},
+
+	{ErrNilConfig, http.StatusInternalServerError, ConfigNotLoaded},
+	{ErrFilepathsNotProvided, http.StatusInternalServerError, ConfigNotLoaded},
+}
+
+// detailedError adds details to the Error an error is replied with.
+type detailedError struct {
+	err     error
+	details map[string]interface{}
+}
+
+func (e *detailedError) Error() string { return e.err.Error() }
+func (e *detailedError) Unwrap() error { return e.err }
+
+// withDetails returns err with details to reply with.
+func withDetails(err error, details map[string]interface{}) error {
+	return &detailedError{err: err, details: details}
+}
+
+// FieldError is the reason a field of a configuration, like the server
+// arguments or properties, is invalid.
+type FieldError struct {
+	// Err is the error the field is invalid with, like ErrInvalidProperty, and
+	// Field the name of the field in JSON
+	Err    error
+	Field  string
+	Reason string
+}
+
+func (e FieldError) Error() string { return fmt.Sprintf("%v: %s %s", e.Err, e.Field, e.Reason) }
+func (e FieldError) Unwrap() error { return e.Err }
+
+// ValidationError lists the invalid fields of a configuration. It's replied
+// with the reasons in the `fields` of the details of the Error.
+type ValidationError []FieldError
+
+func (e ValidationError) Error() string {
+	messages := make([]string, len(e))
+	for i, field := range e {
+		messages[i] = field.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func (e ValidationError) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, field := range e {
+		errs[i] = field
+	}
+
+	return errs
+}
+
+// fields returns the reasons the fields are invalid, keyed by field.
+func (e ValidationError) fields() map[string]interface{} {
+	fields := make(map[string]interface{})
+	for _, field := range e {
+		if reason, ok := fields[field.Field]; ok {
+			fields[field.Field] = reason.(string) + "; " + field.Reason
+		} else {
+			fields[field.Field] = field.Reason
+		}
+	}
+
+	return fields
+}
+
+// translateError returns the status code and Error that err is replied with.
+// Errors that aren't translated are replied to as internal errors, without
+// their message.
+func translateError(err error) (int, Error) {
+	for _, t := range errorTranslations {
+		if errors.Is(err, t.err) {
+			e := Error{Code: t.code, Message: err.Error()}
+
+			var detailed *detailedError
+			var validation ValidationError
+			if errors.As(err, &detailed) {
+				e.Details = &detailed.details
+			} else if errors.As(err, &validation) {
+				e.Details = &map[string]interface{}{"fields": validation.fields()}
+			}
+
+			return t.status, e
+		}
+	}
+
+	return http.StatusInternalServerError, Error{Code: InternalError, Message: ErrInternal.Error()}
+}
+
+// writeError replies with the Error err translates to.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status, e := translateError(err)
+	if e.Code == InternalError {
+		log.Printf("unexpected error handling %s %s: %v", r.Method, r.URL.Path, err)
+	}
+	e.RequestId = RequestIDFromContext(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(e)
+}
+
+// invalidRequest returns an ErrInvalidRequest with reason.
+func invalidRequest(reason string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidRequest, reason)
+}
+
+// WriteRequestError replies to a request that couldn't be bound to an
+// operation, like one with a missing or malformed parameter. It's meant to be
+// passed to HandlerWithOptions as the ErrorHandlerFunc.
+func WriteRequestError(w http.ResponseWriter, r *http.Request, err error) {
+	writeError(w, r, fmt.Errorf("%w: %w", ErrInvalidRequest, err))
+}
+
+// writeMessage replies with status and a MessageResponse.
+func writeMessage(w http.ResponseWriter, status int, message string) {
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(message)
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestTranslateError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantCode    ErrorCode
+		wantMessage string
+	}{
+		{name: "sentinel", err: ErrNotInOps, wantStatus: http.StatusNotFound, wantCode: NotAnOperator, wantMessage: ErrNotInOps.Error()},
+		{name: "wrapped", err: fmt.Errorf("%w: 1.99", ErrVersionUnsupported), wantStatus: http.StatusBadRequest, wantCode: UnsupportedVersion, wantMessage: "unsupported server version passed: 1.99"},
+		{name: "context comes first", err: fmt.Errorf("%w: %w", ErrCatalogRefresh, ErrDownloadFailed), wantStatus: http.StatusBadGateway, wantCode: VersionCatalogUnavailable},
+		{name: "validation", err: ValidationError{{Err: ErrInvalidProperty, Field: "serverPort", Reason: "must be between 1 and 65535"}}, wantStatus: http.StatusBadRequest, wantCode: InvalidProperties, wantMessage: "invalid server property: serverPort must be between 1 and 65535"},
+		{name: "untranslated", err: errors.New("disk full"), wantStatus: http.StatusInternalServerError, wantCode: InternalError, wantMessage: "internal server error"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			status, e := translateError(tc.err)
+			if status != tc.wantStatus || e.Code != tc.wantCode {
+				t.Fatalf("expected %d `%s`, got %d `%s`", tc.wantStatus, tc.wantCode, status, e.Code)
+			}
+			if len(tc.wantMessage) > 0 && e.Message != tc.wantMessage {
+				t.Fatalf("expected message `%s`, got `%s`", tc.wantMessage, e.Message)
+			}
+		})
+	}
+
+	details := map[string]interface{}{"scope": ScopeAdmin}
+	if _, e := translateError(withDetails(ErrMissingScope, details)); e.Details == nil || (*e.Details)["scope"] != ScopeAdmin {
+		t.Fatalf("expected details `%v`, got `%v`", details, e.Details)
+	}
+
+	err := ValidationError{
+		{Err: ErrInvalidMemory, Field: "memoryMaxMB", Reason: "must be positive"},
+		{Err: ErrInvalidArgument, Field: "jvmFlags", Reason: "`Main` must be a flag other than -jar"},
+		{Err: ErrInvalidArgument, Field: "jvmFlags", Reason: "`-jar` must be a flag other than -jar"},
+	}
+	fields := map[string]interface{}{
+		"memoryMaxMB": "must be positive",
+		"jvmFlags":    "`Main` must be a flag other than -jar; `-jar` must be a flag other than -jar",
+	}
+	if _, e := translateError(fmt.Errorf("%w", err)); e.Details == nil || !reflect.DeepEqual((*e.Details)["fields"], fields) {
+		t.Fatalf("expected fields `%v`, got `%v`", fields, e.Details)
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// GetEvents implements ServerInterface.
+func (s *ServerController) GetEvents(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	conn, err := consoleUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader has already replied with an HTTP error
+		log.Println("error upgrading events connection:", err)
+		return
+	}
+	defer conn.Close()
+
+	events, cancel := msi.SubscribeConfigEvents()
+	defer cancel()
+
+	go readUntilClosed(conn, cancel)
+
+	ticker := time.NewTicker(consolePingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				_ = conn.WriteControl(
+					websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+					time.Now().Add(consoleWriteWait),
+				)
+				return
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(consoleWriteWait))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			_ = conn.SetWriteDeadline(time.Now().Add(consoleWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readUntilClosed discards the messages sent by a client of a stream that
+// only goes one way until the client disconnects, at which point its
+// subscription is cancelled.
+func readUntilClosed(conn *websocket.Conn, cancel func()) {
+	defer cancel()
+
+	_ = conn.SetReadDeadline(time.Now().Add(consolePongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(consolePongWait))
+	})
+
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type fakeEventsServer struct {
+	MinecraftServerInterface
+
+	events chan ConfigEvent
+}
+
+func (f *fakeEventsServer) SubscribeConfigEvents() (<-chan ConfigEvent, func()) {
+	var once sync.Once
+	return f.events, func() {
+		once.Do(func() { close(f.events) })
+	}
+}
+
+func TestGetEvents(t *testing.T) {
+	t.Parallel()
+
+	msi := &fakeEventsServer{events: make(chan ConfigEvent, 2)}
+	ts := httptest.NewServer(newTestHandler(msi))
+	defer ts.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial(
+		"ws"+strings.TrimPrefix(ts.URL, "http")+"/servers/test/events", nil,
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	defer conn.Close()
+
+	name, ip := "steve", "192.0.2.1"
+	want := []ConfigEvent{
+		{File: Ops, Change: Added, Player: &PlayerInfo{Name: &name}},
+		{File: BannedIps, Change: Removed, Ip: &ip},
+	}
+	for _, event := range want {
+		msi.events <- event
+	}
+
+	for _, wantEvent := range want {
+		var got ConfigEvent
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		if err := conn.ReadJSON(&got); err != nil {
+			t.Fatalf("expected no error, got `%v`", err)
+		}
+		if got.File != wantEvent.File || got.Change != wantEvent.Change {
+			t.Fatalf("expected event `%+v`, got `%+v`", wantEvent, got)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"runtime"
+)
+
+// WriteFileAtomic replaces the file at filepath with data. data is written to
+// a temporary file next to it that's synced to disk and renamed over the file,
+// so a crash leaves either the old or the new file behind, never part of one.
+func WriteFileAtomic(filepath string, data []byte, perm fs.FileMode) error {
+	return WriteAtomic(filepath, perm, func(file io.Writer) error {
+		_, err := file.Write(data)
+		return err
+	})
+}
+
+// WriteAtomic replaces the file at filepath with what writeFn writes, the same
+// way WriteFileAtomic does, for files too large to hold in memory.
+func WriteAtomic(filepath string, perm fs.FileMode, writeFn func(file io.Writer) error) error {
+	dir := path.Dir(filepath)
+	tmp, err := os.CreateTemp(dir, path.Base(filepath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// does nothing once the file has been renamed
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if err := writeFn(tmp); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), filepath); err != nil {
+		return err
+	}
+
+	return SyncDir(dir)
+}
+
+// SyncDir syncs the directory dir to disk, so that files renamed into it
+// survive a crash.
+func SyncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		// directories can't be opened to be synced on Windows
+		return nil
+	}
+
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/oapi-codegen/runtime"
@@ -17,6 +18,81 @@ const (
 	APIKeyAuthScopes = "APIKeyAuth.Scopes"
 )
 
+// Defines values for ConfigEventChange.
+const (
+	Added   ConfigEventChange = "added"
+	Changed ConfigEventChange = "changed"
+	Removed ConfigEventChange = "removed"
+)
+
+// Defines values for ConfigEventFile.
+const (
+	BannedIps     ConfigEventFile = "banned_ips"
+	BannedPlayers ConfigEventFile = "banned_players"
+	Ops           ConfigEventFile = "ops"
+	Whitelist     ConfigEventFile = "whitelist"
+)
+
+// Defines values for ConsoleLineStream.
+const (
+	Rcon   ConsoleLineStream = "rcon"
+	Stderr ConsoleLineStream = "stderr"
+	Stdout ConsoleLineStream = "stdout"
+)
+
+// Defines values for ErrorCode.
+const (
+	AlreadyAllowed            ErrorCode = "already_allowed"
+	AlreadyAnOperator         ErrorCode = "already_an_operator"
+	BackupInProgress          ErrorCode = "backup_in_progress"
+	BackupNotFound            ErrorCode = "backup_not_found"
+	CommandTimeout            ErrorCode = "command_timeout"
+	ConfigNotLoaded           ErrorCode = "config_not_loaded"
+	DownloadFailed            ErrorCode = "download_failed"
+	Forbidden                 ErrorCode = "forbidden"
+	InternalError             ErrorCode = "internal_error"
+	InvalidArguments          ErrorCode = "invalid_arguments"
+	InvalidBackupSchedule     ErrorCode = "invalid_backup_schedule"
+	InvalidIp                 ErrorCode = "invalid_ip"
+	InvalidProperties         ErrorCode = "invalid_properties"
+	InvalidRequest            ErrorCode = "invalid_request"
+	InvalidServer             ErrorCode = "invalid_server"
+	InvalidServerId           ErrorCode = "invalid_server_id"
+	IpAlreadyBanned           ErrorCode = "ip_already_banned"
+	IpNotBanned               ErrorCode = "ip_not_banned"
+	NoJavaRuntime             ErrorCode = "no_java_runtime"
+	NoWorlds                  ErrorCode = "no_worlds"
+	NotAllowed                ErrorCode = "not_allowed"
+	NotAnOperator             ErrorCode = "not_an_operator"
+	NotFound                  ErrorCode = "not_found"
+	PlayerAlreadyBanned       ErrorCode = "player_already_banned"
+	PlayerNotBanned           ErrorCode = "player_not_banned"
+	PlayerNotFound            ErrorCode = "player_not_found"
+	PortInUse                 ErrorCode = "port_in_use"
+	QueryUnavailable          ErrorCode = "query_unavailable"
+	ServerExists              ErrorCode = "server_exists"
+	ServerInUse               ErrorCode = "server_in_use"
+	ServerNotFound            ErrorCode = "server_not_found"
+	ServerNotRunning          ErrorCode = "server_not_running"
+	ServerRunning             ErrorCode = "server_running"
+	Unauthorized              ErrorCode = "unauthorized"
+	UnsupportedVersion        ErrorCode = "unsupported_version"
+	VersionCatalogUnavailable ErrorCode = "version_catalog_unavailable"
+)
+
+// Defines values for JVMPreset.
+const (
+	Aikar JVMPreset = "aikar"
+)
+
+// Defines values for ReleaseType.
+const (
+	OldAlpha ReleaseType = "old_alpha"
+	OldBeta  ReleaseType = "old_beta"
+	Release  ReleaseType = "release"
+	Snapshot ReleaseType = "snapshot"
+)
+
 // Defines values for ServerPropertiesDifficulty.
 const (
 	Easy     ServerPropertiesDifficulty = "easy"
@@ -34,9 +110,74 @@ const (
 	Survival  ServerPropertiesGamemode = "survival"
 )
 
+// Defines values for ServerStatusState.
+const (
+	Crashed  ServerStatusState = "crashed"
+	Running  ServerStatusState = "running"
+	Starting ServerStatusState = "starting"
+	Stopped  ServerStatusState = "stopped"
+	Stopping ServerStatusState = "stopping"
+)
+
 // Allowlist defines model for Allowlist.
 type Allowlist = []PlayerInfo
 
+// Backup A backup of the worlds of a Minecraft server
+type Backup struct {
+	// Id ID of the backup, made from the time it was created
+	Id string `json:"id"`
+
+	// Size Size of the backup archive in bytes
+	Size int64 `json:"size"`
+
+	// Time Time the backup was created
+	Time time.Time `json:"time"`
+
+	// Worlds World directories in the backup, like `world`, `world_nether` and
+	// `world_the_end`
+	Worlds []string `json:"worlds"`
+}
+
+// BackupList defines model for BackupList.
+type BackupList = []Backup
+
+// BackupRetention Grandfather-father-son retention of backups. The newest backup of each
+// of the last `hourly` hours, `daily` days and `weekly` weeks (starting on
+// Monday) that have backups is kept, and the other backups are deleted
+// after every scheduled backup. Backups created through the API count as
+// well. Backups are only deleted if at least one of the counts is set
+type BackupRetention struct {
+	Daily  int `json:"daily"`
+	Hourly int `json:"hourly"`
+	Weekly int `json:"weekly"`
+}
+
+// BackupSchedule When backups of the worlds of a Minecraft server are created
+// automatically, and which of the backups are kept. While the schedule
+// is enabled, exactly one of `cron` and `intervalMinutes` must be set
+type BackupSchedule struct {
+	// Cron Cron expression with five fields, `minute hour day-of-month month
+	// day-of-week`, in the local time zone of the server controller.
+	// `@hourly`, `@daily`, `@weekly`, `@monthly` and `@yearly` are
+	// accepted as well
+	Cron    *string `json:"cron,omitempty"`
+	Enabled bool    `json:"enabled"`
+
+	// IntervalMinutes Minutes between backups, counted from the end of the previous one
+	IntervalMinutes *int `json:"intervalMinutes,omitempty"`
+
+	// Retention Grandfather-father-son retention of backups. The newest backup of each
+	// of the last `hourly` hours, `daily` days and `weekly` weeks (starting on
+	// Monday) that have backups is kept, and the other backups are deleted
+	// after every scheduled backup. Backups created through the API count as
+	// well. Backups are only deleted if at least one of the counts is set
+	Retention *BackupRetention `json:"retention,omitempty"`
+
+	// SkipIfNoPlayers Skip a scheduled backup if no players have joined the Minecraft
+	// server since the last backup and none are online
+	SkipIfNoPlayers *bool `json:"skipIfNoPlayers,omitempty"`
+}
+
 // BannedIP defines model for BannedIP.
 type BannedIP struct {
 	Created string `json:"created"`
@@ -62,6 +203,106 @@ type BannedPlayer struct {
 // BannedPlayerList defines model for BannedPlayerList.
 type BannedPlayerList = []BannedPlayer
 
+// CloneServerRequest defines model for CloneServerRequest.
+type CloneServerRequest struct {
+	// Id ID of the new server
+	Id   string `json:"id"`
+	Port *int   `json:"port,omitempty"`
+}
+
+// ConfigEvent A change to the allowlist, operators or bans of the Minecraft server,
+// made through the API or by the Minecraft server itself, like when an
+// operator runs `/ban` in game
+type ConfigEvent struct {
+	// Change Whether the entry was added to, removed from or changed in the file
+	Change ConfigEventChange `json:"change"`
+
+	// File The configuration file that changed
+	File ConfigEventFile `json:"file"`
+
+	// Ip The banned IP address, for changes to `banned_ips`
+	Ip     *string     `json:"ip,omitempty"`
+	Player *PlayerInfo `json:"player,omitempty"`
+
+	// Time Time the change was noticed
+	Time time.Time `json:"time"`
+}
+
+// ConfigEventChange Whether the entry was added to, removed from or changed in the file
+type ConfigEventChange string
+
+// ConfigEventFile The configuration file that changed
+type ConfigEventFile string
+
+// ConsoleLine A line of output from the Minecraft server console
+type ConsoleLine struct {
+	// Seq Sequence number of the line. Sequence numbers increase by one for
+	// every line written to the console.
+	Seq int64 `json:"seq"`
+
+	// Stream Where the line came from, the standard output or standard error of the Minecraft server process, or the response to a command sent over RCON
+	Stream ConsoleLineStream `json:"stream"`
+	Text   string            `json:"text"`
+
+	// Time Time the line was read from the Minecraft server process
+	Time time.Time `json:"time"`
+}
+
+// ConsoleLineStream Where the line came from, the standard output or standard error of the Minecraft server process, or the response to a command sent over RCON
+type ConsoleLineStream string
+
+// ConsoleLineList defines model for ConsoleLineList.
+type ConsoleLineList = []ConsoleLine
+
+// CreateServerRequest defines model for CreateServerRequest.
+type CreateServerRequest struct {
+	// Id ID of the server, made of lowercase letters, digits, `-` and `_`
+	Id   string `json:"id"`
+	Port *int   `json:"port,omitempty"`
+
+	// Version Version of the server, or `latest` or `latest-snapshot`. Defaults to
+	// the newest release
+	Version *string `json:"version,omitempty"`
+}
+
+// Error Why a request failed
+type Error struct {
+	// Code Machine readable reason of an error. Codes are stable, new ones may be
+	// added but existing ones don't change meaning
+	Code ErrorCode `json:"code"`
+
+	// Details More information about the error, depending on its code. Errors
+	// with the `invalid_arguments` or `invalid_properties` code have a
+	// `fields` object with the reason each invalid field is invalid, keyed
+	// by the name of the field
+	Details *map[string]interface{} `json:"details,omitempty"`
+
+	// Message Human readable description of the error
+	Message string `json:"message"`
+
+	// RequestId ID of the request, also sent in the `X-Request-ID` response header
+	RequestId string `json:"requestId"`
+}
+
+// ErrorCode Machine readable reason of an error. Codes are stable, new ones may be
+// added but existing ones don't change meaning
+type ErrorCode string
+
+// JVMPreset Named set of JVM flags to tune the Minecraft server with. `aikar` is
+// Aikar's G1GC flags, which are tuned for the heap size
+type JVMPreset string
+
+// JavaRuntime defines model for JavaRuntime.
+type JavaRuntime struct {
+	MajorVersion int `json:"majorVersion"`
+
+	// Path Path of the `java` executable of the runtime
+	Path string `json:"path"`
+
+	// Version Version of the runtime as reported by `java -version`
+	Version string `json:"version"`
+}
+
 // Message defines model for Message.
 type Message = string
 
@@ -71,20 +312,105 @@ type PlayerInfo struct {
 	Uuid *openapi_types.UUID `json:"uuid,omitempty"`
 }
 
+// PropertiesUpdate How the changes to the server properties were applied. Properties are
+// listed by their names in ServerProperties
+type PropertiesUpdate struct {
+	// Applied Changed properties applied to the running Minecraft server through
+	// its console, like `difficulty`, `gamemode` and `whitelist`
+	Applied []string `json:"applied"`
+
+	// Changed Properties whose values changed
+	Changed []string `json:"changed"`
+
+	// Ignored Properties set in the request that the configured version of the
+	// Minecraft server doesn't have, like `acceptsTransfers` before
+	// 1.20.5, and ignores. They're kept, but left out of the
+	// server.properties file
+	Ignored []string `json:"ignored"`
+
+	// PendingRestart Changed properties that only take effect once the running Minecraft
+	// server is restarted, like `serverPort` and `levelSeed`. Changes made
+	// while the Minecraft server isn't running take effect when it starts
+	PendingRestart []string `json:"pendingRestart"`
+
+	// Restarted Whether the Minecraft server was restarted to apply the properties
+	// pending a restart, see the `restart` parameter
+	Restarted bool `json:"restarted"`
+}
+
+// QueryStatus Full stat of the Minecraft server from the GameSpy4 Query protocol,
+// available if `enableQuery` is set in the server properties
+type QueryStatus struct {
+	GameId     *string `json:"gameId,omitempty"`
+	GameType   string  `json:"gameType"`
+	HostIp     string  `json:"hostIp"`
+	HostPort   int     `json:"hostPort"`
+	Map        string  `json:"map"`
+	MaxPlayers int     `json:"maxPlayers"`
+	Motd       string  `json:"motd"`
+	NumPlayers int     `json:"numPlayers"`
+
+	// Players Names of all the players that are online
+	Players []string `json:"players"`
+	Plugins []string `json:"plugins"`
+
+	// ServerMod Name and version of the server mod, empty for the vanilla Minecraft server
+	ServerMod *string `json:"serverMod,omitempty"`
+	Version   *string `json:"version,omitempty"`
+}
+
+// ReleaseType defines model for ReleaseType.
+type ReleaseType string
+
 // ServerArguments defines model for ServerArguments.
 type ServerArguments struct {
-	BonusChest    *bool   `json:"bonusChest,omitempty"`
-	Demo          *bool   `json:"demo,omitempty"`
-	EraseCache    *bool   `json:"eraseCache,omitempty"`
-	ForceUpgrade  *bool   `json:"forceUpgrade,omitempty"`
-	MemoryMaxGB   *int    `json:"memoryMaxGB,omitempty"`
-	MemoryStartGB *int    `json:"memoryStartGB,omitempty"`
-	Port          *int    `json:"port,omitempty"`
-	SafeMode      *bool   `json:"safeMode,omitempty"`
-	ServerID      *string `json:"serverID,omitempty"`
-	SinglePlayer  *string `json:"singlePlayer,omitempty"`
-	Universe      *string `json:"universe,omitempty"`
-	World         *string `json:"world,omitempty"`
+	BonusChest *bool `json:"bonusChest,omitempty"`
+	Demo       *bool `json:"demo,omitempty"`
+
+	// Env Environment variables set for the Minecraft server process
+	Env          *map[string]string `json:"env,omitempty"`
+	EraseCache   *bool              `json:"eraseCache,omitempty"`
+	ForceUpgrade *bool              `json:"forceUpgrade,omitempty"`
+
+	// JavaPath Path of the `java` executable to run the Minecraft server with,
+	// which must be one of the discovered Java runtimes. If empty, a
+	// discovered Java runtime compatible with the server version is used
+	JavaPath *string `json:"javaPath,omitempty"`
+
+	// JvmFlags Extra JVM flags, passed after the flags of the preset
+	JvmFlags    *[]string `json:"jvmFlags,omitempty"`
+	MemoryMaxGB *int      `json:"memoryMaxGB,omitempty"`
+
+	// MemoryMaxMB Maximum heap size in MB, takes precedence over `memoryMaxGB`
+	MemoryMaxMB   *int `json:"memoryMaxMB,omitempty"`
+	MemoryStartGB *int `json:"memoryStartGB,omitempty"`
+
+	// MemoryStartMB Initial heap size in MB, takes precedence over `memoryStartGB`
+	MemoryStartMB *int `json:"memoryStartMB,omitempty"`
+
+	// Port Port the server listens on, overriding serverPort
+	Port *int `json:"port,omitempty"`
+
+	// Preset Named set of JVM flags to tune the Minecraft server with. `aikar` is
+	// Aikar's G1GC flags, which are tuned for the heap size
+	Preset   *JVMPreset `json:"preset,omitempty"`
+	SafeMode *bool      `json:"safeMode,omitempty"`
+
+	// ServerArgs Extra arguments passed to the Minecraft server
+	ServerArgs   *[]string `json:"serverArgs,omitempty"`
+	ServerID     *string   `json:"serverID,omitempty"`
+	SinglePlayer *string   `json:"singlePlayer,omitempty"`
+	Universe     *string   `json:"universe,omitempty"`
+	World        *string   `json:"world,omitempty"`
+}
+
+// ServerInstance defines model for ServerInstance.
+type ServerInstance struct {
+	Id         string            `json:"id"`
+	RconPort   int               `json:"rconPort"`
+	ServerPort int               `json:"serverPort"`
+	State      ServerStatusState `json:"state"`
+	Version    string            `json:"version"`
 }
 
 // ServerOperator defines model for ServerOperator.
@@ -170,21 +496,91 @@ type ServerPropertiesDifficulty string
 // ServerPropertiesGamemode defines model for ServerProperties.Gamemode.
 type ServerPropertiesGamemode string
 
+// ServerStatus State of the Minecraft server process along with the status the
+// Minecraft server reports through the Server List Ping protocol. The
+// Server List Ping fields are only present if the server responded to a
+// ping.
+type ServerStatus struct {
+	// Latency Round trip time of the Server List Ping in milliseconds
+	Latency *int    `json:"latency,omitempty"`
+	Motd    *string `json:"motd,omitempty"`
+
+	// Online Whether the Minecraft server responded to a Server List Ping
+	Online bool `json:"online"`
+
+	// Pid Process ID of the Minecraft server process if the controller launched it
+	Pid     *int                 `json:"pid,omitempty"`
+	Players *ServerStatusPlayers `json:"players,omitempty"`
+	State   ServerStatusState    `json:"state"`
+	Version *ServerStatusVersion `json:"version,omitempty"`
+}
+
+// ServerStatusPlayers defines model for ServerStatusPlayers.
+type ServerStatusPlayers struct {
+	Max    int `json:"max"`
+	Online int `json:"online"`
+
+	// Sample Some of the players that are online, chosen by the Minecraft server
+	Sample []PlayerInfo `json:"sample"`
+}
+
+// ServerStatusState defines model for ServerStatusState.
+type ServerStatusState string
+
+// ServerStatusVersion defines model for ServerStatusVersion.
+type ServerStatusVersion struct {
+	Name     string `json:"name"`
+	Protocol int    `json:"protocol"`
+}
+
+// BackupID defines model for BackupID.
+type BackupID = string
+
+// ServerID defines model for ServerID.
+type ServerID = string
+
 // AllowlistResponse defines model for AllowlistResponse.
 type AllowlistResponse = Allowlist
 
+// BackupResponse A backup of the worlds of a Minecraft server
+type BackupResponse = Backup
+
+// BackupScheduleResponse When backups of the worlds of a Minecraft server are created
+// automatically, and which of the backups are kept. While the schedule
+// is enabled, exactly one of `cron` and `intervalMinutes` must be set
+type BackupScheduleResponse = BackupSchedule
+
 // BannedIPListResponse defines model for BannedIPListResponse.
 type BannedIPListResponse = BannedIPList
 
 // BannedPlayerListResponse defines model for BannedPlayerListResponse.
 type BannedPlayerListResponse = BannedPlayerList
 
+// ErrorResponse Why a request failed
+type ErrorResponse = Error
+
+// ForbiddenResponse Why a request failed
+type ForbiddenResponse = Error
+
+// InternalErrorResponse Why a request failed
+type InternalErrorResponse = Error
+
 // MessageResponse defines model for MessageResponse.
 type MessageResponse = Message
 
+// PropertiesUpdateResponse How the changes to the server properties were applied. Properties are
+// listed by their names in ServerProperties
+type PropertiesUpdateResponse = PropertiesUpdate
+
+// ServerInstanceResponse defines model for ServerInstanceResponse.
+type ServerInstanceResponse = ServerInstance
+
 // ServerOperatorListResponse defines model for ServerOperatorListResponse.
 type ServerOperatorListResponse = ServerOperatorList
 
+// UnauthorizedResponse Why a request failed
+type UnauthorizedResponse = Error
+
 // AllowlistRequest defines model for AllowlistRequest.
 type AllowlistRequest = Allowlist
 
@@ -212,19 +608,54 @@ type ServerOperatorRequest = ServerOperator
 // UpdateArgsRequest defines model for UpdateArgsRequest.
 type UpdateArgsRequest = ServerArguments
 
+// UpdateBackupScheduleRequest When backups of the worlds of a Minecraft server are created
+// automatically, and which of the backups are kept. While the schedule
+// is enabled, exactly one of `cron` and `intervalMinutes` must be set
+type UpdateBackupScheduleRequest = BackupSchedule
+
 // UpdatePropertiesRequest defines model for UpdatePropertiesRequest.
 type UpdatePropertiesRequest = ServerProperties
 
+// GetAvailableVersionsParams defines parameters for GetAvailableVersions.
+type GetAvailableVersionsParams struct {
+	// Type Only list versions with this release type
+	Type *ReleaseType `form:"type,omitempty" json:"type,omitempty"`
+}
+
+// GetConsoleParams defines parameters for GetConsole.
+type GetConsoleParams struct {
+	// Since Replay the lines in the console history after this sequence number
+	Since *int64 `form:"since,omitempty" json:"since,omitempty"`
+}
+
+// GetConsoleHistoryParams defines parameters for GetConsoleHistory.
+type GetConsoleHistoryParams struct {
+	Since *int64 `form:"since,omitempty" json:"since,omitempty"`
+	Limit *int   `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
 // PostPardonIpJSONBody defines parameters for PostPardonIp.
 type PostPardonIpJSONBody struct {
 	Ip string `json:"ip"`
 }
 
+// PutPropertiesParams defines parameters for PutProperties.
+type PutPropertiesParams struct {
+	// Restart Restart the Minecraft server if any of the changed properties only
+	// take effect once it is restarted
+	Restart *bool `form:"restart,omitempty" json:"restart,omitempty"`
+}
+
 // PostSetVersionParams defines parameters for PostSetVersion.
 type PostSetVersionParams struct {
-	Version *string `form:"version,omitempty" json:"version,omitempty"`
+	// Version The version to run, or `latest` or `latest-snapshot` for the newest
+	// release or snapshot in the version catalog
+	Version string `form:"version" json:"version"`
 }
 
+// CreateServerJSONRequestBody defines body for CreateServer for application/json ContentType.
+type CreateServerJSONRequestBody = CreateServerRequest
+
 // PutAllowlistJSONRequestBody defines body for PutAllowlist for application/json ContentType.
 type PutAllowlistJSONRequestBody = Allowlist
 
@@ -237,6 +668,9 @@ type PostAllowlistRemoveJSONRequestBody = PlayerInfo
 // PutArgsJSONRequestBody defines body for PutArgs for application/json ContentType.
 type PutArgsJSONRequestBody = ServerArguments
 
+// PutBackupScheduleJSONRequestBody defines body for PutBackupSchedule for application/json ContentType.
+type PutBackupScheduleJSONRequestBody = BackupSchedule
+
 // PostBanJSONRequestBody defines body for PostBan for application/json ContentType.
 type PostBanJSONRequestBody = BannedPlayer
 
@@ -249,6 +683,9 @@ type PutBannedIpsJSONRequestBody = BannedIPList
 // PutBannedPlayersJSONRequestBody defines body for PutBannedPlayers for application/json ContentType.
 type PutBannedPlayersJSONRequestBody = BannedPlayerList
 
+// CloneServerJSONRequestBody defines body for CloneServer for application/json ContentType.
+type CloneServerJSONRequestBody = CloneServerRequest
+
 // PostDeopJSONRequestBody defines body for PostDeop for application/json ContentType.
 type PostDeopJSONRequestBody = PlayerInfo
 
@@ -270,192 +707,328 @@ type PutPropertiesJSONRequestBody = ServerProperties
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 
-	// (GET /allowlist)
-	GetAllowlist(w http.ResponseWriter, r *http.Request)
+	// (POST /available-versions/refresh)
+	RefreshAvailableVersions(w http.ResponseWriter, r *http.Request)
+
+	// (GET /servers)
+	ListServers(w http.ResponseWriter, r *http.Request)
+
+	// (POST /servers)
+	CreateServer(w http.ResponseWriter, r *http.Request)
+
+	// (DELETE /servers/{id})
+	DeleteServer(w http.ResponseWriter, r *http.Request, id ServerID)
+
+	// (GET /servers/{id})
+	GetServer(w http.ResponseWriter, r *http.Request, id ServerID)
+
+	// (GET /servers/{id}/allowlist)
+	GetAllowlist(w http.ResponseWriter, r *http.Request, id ServerID)
+
+	// (PUT /servers/{id}/allowlist)
+	PutAllowlist(w http.ResponseWriter, r *http.Request, id ServerID)
+
+	// (POST /servers/{id}/allowlist/add)
+	PostAllowlistAdd(w http.ResponseWriter, r *http.Request, id ServerID)
+
+	// (POST /servers/{id}/allowlist/remove)
+	PostAllowlistRemove(w http.ResponseWriter, r *http.Request, id ServerID)
+
+	// (PUT /servers/{id}/args)
+	PutArgs(w http.ResponseWriter, r *http.Request, id ServerID)
+
+	// (GET /servers/{id}/available-versions)
+	GetAvailableVersions(w http.ResponseWriter, r *http.Request, id ServerID, params GetAvailableVersionsParams)
+
+	// (GET /servers/{id}/backups)
+	ListBackups(w http.ResponseWriter, r *http.Request, id ServerID)
+
+	// (POST /servers/{id}/backups)
+	CreateBackup(w http.ResponseWriter, r *http.Request, id ServerID)
 
-	// (PUT /allowlist)
-	PutAllowlist(w http.ResponseWriter, r *http.Request)
+	// (GET /servers/{id}/backups/schedule)
+	GetBackupSchedule(w http.ResponseWriter, r *http.Request, id ServerID)
 
-	// (POST /allowlist/add)
-	PostAllowlistAdd(w http.ResponseWriter, r *http.Request)
+	// (PUT /servers/{id}/backups/schedule)
+	PutBackupSchedule(w http.ResponseWriter, r *http.Request, id ServerID)
 
-	// (POST /allowlist/remove)
-	PostAllowlistRemove(w http.ResponseWriter, r *http.Request)
+	// (DELETE /servers/{id}/backups/{backupId})
+	DeleteBackup(w http.ResponseWriter, r *http.Request, id ServerID, backupId BackupID)
 
-	// (PUT /args)
-	PutArgs(w http.ResponseWriter, r *http.Request)
+	// (POST /servers/{id}/ban)
+	PostBan(w http.ResponseWriter, r *http.Request, id ServerID)
 
-	// (GET /available-versions)
-	GetAvailableVersions(w http.ResponseWriter, r *http.Request)
+	// (POST /servers/{id}/ban-ip)
+	PostBanIp(w http.ResponseWriter, r *http.Request, id ServerID)
 
-	// (POST /ban)
-	PostBan(w http.ResponseWriter, r *http.Request)
+	// (GET /servers/{id}/banned-ips)
+	GetBannedIps(w http.ResponseWriter, r *http.Request, id ServerID)
 
-	// (POST /ban-ip)
-	PostBanIp(w http.ResponseWriter, r *http.Request)
+	// (PUT /servers/{id}/banned-ips)
+	PutBannedIps(w http.ResponseWriter, r *http.Request, id ServerID)
 
-	// (GET /banned-ips)
-	GetBannedIps(w http.ResponseWriter, r *http.Request)
+	// (GET /servers/{id}/banned-players)
+	GetBannedPlayers(w http.ResponseWriter, r *http.Request, id ServerID)
 
-	// (PUT /banned-ips)
-	PutBannedIps(w http.ResponseWriter, r *http.Request)
+	// (PUT /servers/{id}/banned-players)
+	PutBannedPlayers(w http.ResponseWriter, r *http.Request, id ServerID)
 
-	// (GET /banned-players)
-	GetBannedPlayers(w http.ResponseWriter, r *http.Request)
+	// (POST /servers/{id}/clone)
+	CloneServer(w http.ResponseWriter, r *http.Request, id ServerID)
 
-	// (PUT /banned-players)
-	PutBannedPlayers(w http.ResponseWriter, r *http.Request)
+	// (GET /servers/{id}/console)
+	GetConsole(w http.ResponseWriter, r *http.Request, id ServerID, params GetConsoleParams)
 
-	// (POST /deop)
-	PostDeop(w http.ResponseWriter, r *http.Request)
+	// (GET /servers/{id}/console/history)
+	GetConsoleHistory(w http.ResponseWriter, r *http.Request, id ServerID, params GetConsoleHistoryParams)
 
-	// (POST /op)
-	PostOp(w http.ResponseWriter, r *http.Request)
+	// (POST /servers/{id}/deop)
+	PostDeop(w http.ResponseWriter, r *http.Request, id ServerID)
 
-	// (GET /ops)
-	GetOps(w http.ResponseWriter, r *http.Request)
+	// (GET /servers/{id}/events)
+	GetEvents(w http.ResponseWriter, r *http.Request, id ServerID)
 
-	// (PUT /ops)
-	PutOps(w http.ResponseWriter, r *http.Request)
+	// (GET /servers/{id}/java-runtimes)
+	GetJavaRuntimes(w http.ResponseWriter, r *http.Request, id ServerID)
 
-	// (POST /pardon)
-	PostPardon(w http.ResponseWriter, r *http.Request)
+	// (POST /servers/{id}/op)
+	PostOp(w http.ResponseWriter, r *http.Request, id ServerID)
 
-	// (POST /pardon-ip)
-	PostPardonIp(w http.ResponseWriter, r *http.Request)
+	// (GET /servers/{id}/ops)
+	GetOps(w http.ResponseWriter, r *http.Request, id ServerID)
 
-	// (PUT /properties)
-	PutProperties(w http.ResponseWriter, r *http.Request)
+	// (PUT /servers/{id}/ops)
+	PutOps(w http.ResponseWriter, r *http.Request, id ServerID)
 
-	// (POST /restart)
-	PostRestart(w http.ResponseWriter, r *http.Request)
+	// (POST /servers/{id}/pardon)
+	PostPardon(w http.ResponseWriter, r *http.Request, id ServerID)
 
-	// (POST /set-version)
-	PostSetVersion(w http.ResponseWriter, r *http.Request, params PostSetVersionParams)
+	// (POST /servers/{id}/pardon-ip)
+	PostPardonIp(w http.ResponseWriter, r *http.Request, id ServerID)
 
-	// (POST /start)
-	PostStart(w http.ResponseWriter, r *http.Request)
+	// (PUT /servers/{id}/properties)
+	PutProperties(w http.ResponseWriter, r *http.Request, id ServerID, params PutPropertiesParams)
 
-	// (POST /stop)
-	PostStop(w http.ResponseWriter, r *http.Request)
+	// (GET /servers/{id}/query)
+	GetQuery(w http.ResponseWriter, r *http.Request, id ServerID)
+
+	// (POST /servers/{id}/restart)
+	PostRestart(w http.ResponseWriter, r *http.Request, id ServerID)
+
+	// (POST /servers/{id}/set-version)
+	PostSetVersion(w http.ResponseWriter, r *http.Request, id ServerID, params PostSetVersionParams)
+
+	// (POST /servers/{id}/start)
+	PostStart(w http.ResponseWriter, r *http.Request, id ServerID)
+
+	// (GET /servers/{id}/status)
+	GetStatus(w http.ResponseWriter, r *http.Request, id ServerID)
+
+	// (POST /servers/{id}/stop)
+	PostStop(w http.ResponseWriter, r *http.Request, id ServerID)
 }
 
 // Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
 
 type Unimplemented struct{}
 
-// (GET /allowlist)
-func (_ Unimplemented) GetAllowlist(w http.ResponseWriter, r *http.Request) {
+// (POST /available-versions/refresh)
+func (_ Unimplemented) RefreshAvailableVersions(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /servers)
+func (_ Unimplemented) ListServers(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /servers)
+func (_ Unimplemented) CreateServer(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (DELETE /servers/{id})
+func (_ Unimplemented) DeleteServer(w http.ResponseWriter, r *http.Request, id ServerID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /servers/{id})
+func (_ Unimplemented) GetServer(w http.ResponseWriter, r *http.Request, id ServerID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /servers/{id}/allowlist)
+func (_ Unimplemented) GetAllowlist(w http.ResponseWriter, r *http.Request, id ServerID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (PUT /servers/{id}/allowlist)
+func (_ Unimplemented) PutAllowlist(w http.ResponseWriter, r *http.Request, id ServerID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /servers/{id}/allowlist/add)
+func (_ Unimplemented) PostAllowlistAdd(w http.ResponseWriter, r *http.Request, id ServerID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /servers/{id}/allowlist/remove)
+func (_ Unimplemented) PostAllowlistRemove(w http.ResponseWriter, r *http.Request, id ServerID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (PUT /servers/{id}/args)
+func (_ Unimplemented) PutArgs(w http.ResponseWriter, r *http.Request, id ServerID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (PUT /allowlist)
-func (_ Unimplemented) PutAllowlist(w http.ResponseWriter, r *http.Request) {
+// (GET /servers/{id}/available-versions)
+func (_ Unimplemented) GetAvailableVersions(w http.ResponseWriter, r *http.Request, id ServerID, params GetAvailableVersionsParams) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (POST /allowlist/add)
-func (_ Unimplemented) PostAllowlistAdd(w http.ResponseWriter, r *http.Request) {
+// (GET /servers/{id}/backups)
+func (_ Unimplemented) ListBackups(w http.ResponseWriter, r *http.Request, id ServerID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (POST /allowlist/remove)
-func (_ Unimplemented) PostAllowlistRemove(w http.ResponseWriter, r *http.Request) {
+// (POST /servers/{id}/backups)
+func (_ Unimplemented) CreateBackup(w http.ResponseWriter, r *http.Request, id ServerID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (PUT /args)
-func (_ Unimplemented) PutArgs(w http.ResponseWriter, r *http.Request) {
+// (GET /servers/{id}/backups/schedule)
+func (_ Unimplemented) GetBackupSchedule(w http.ResponseWriter, r *http.Request, id ServerID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (GET /available-versions)
-func (_ Unimplemented) GetAvailableVersions(w http.ResponseWriter, r *http.Request) {
+// (PUT /servers/{id}/backups/schedule)
+func (_ Unimplemented) PutBackupSchedule(w http.ResponseWriter, r *http.Request, id ServerID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (POST /ban)
-func (_ Unimplemented) PostBan(w http.ResponseWriter, r *http.Request) {
+// (DELETE /servers/{id}/backups/{backupId})
+func (_ Unimplemented) DeleteBackup(w http.ResponseWriter, r *http.Request, id ServerID, backupId BackupID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (POST /ban-ip)
-func (_ Unimplemented) PostBanIp(w http.ResponseWriter, r *http.Request) {
+// (POST /servers/{id}/ban)
+func (_ Unimplemented) PostBan(w http.ResponseWriter, r *http.Request, id ServerID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (GET /banned-ips)
-func (_ Unimplemented) GetBannedIps(w http.ResponseWriter, r *http.Request) {
+// (POST /servers/{id}/ban-ip)
+func (_ Unimplemented) PostBanIp(w http.ResponseWriter, r *http.Request, id ServerID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (PUT /banned-ips)
-func (_ Unimplemented) PutBannedIps(w http.ResponseWriter, r *http.Request) {
+// (GET /servers/{id}/banned-ips)
+func (_ Unimplemented) GetBannedIps(w http.ResponseWriter, r *http.Request, id ServerID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (GET /banned-players)
-func (_ Unimplemented) GetBannedPlayers(w http.ResponseWriter, r *http.Request) {
+// (PUT /servers/{id}/banned-ips)
+func (_ Unimplemented) PutBannedIps(w http.ResponseWriter, r *http.Request, id ServerID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (PUT /banned-players)
-func (_ Unimplemented) PutBannedPlayers(w http.ResponseWriter, r *http.Request) {
+// (GET /servers/{id}/banned-players)
+func (_ Unimplemented) GetBannedPlayers(w http.ResponseWriter, r *http.Request, id ServerID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (POST /deop)
-func (_ Unimplemented) PostDeop(w http.ResponseWriter, r *http.Request) {
+// (PUT /servers/{id}/banned-players)
+func (_ Unimplemented) PutBannedPlayers(w http.ResponseWriter, r *http.Request, id ServerID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (POST /op)
-func (_ Unimplemented) PostOp(w http.ResponseWriter, r *http.Request) {
+// (POST /servers/{id}/clone)
+func (_ Unimplemented) CloneServer(w http.ResponseWriter, r *http.Request, id ServerID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (GET /ops)
-func (_ Unimplemented) GetOps(w http.ResponseWriter, r *http.Request) {
+// (GET /servers/{id}/console)
+func (_ Unimplemented) GetConsole(w http.ResponseWriter, r *http.Request, id ServerID, params GetConsoleParams) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (PUT /ops)
-func (_ Unimplemented) PutOps(w http.ResponseWriter, r *http.Request) {
+// (GET /servers/{id}/console/history)
+func (_ Unimplemented) GetConsoleHistory(w http.ResponseWriter, r *http.Request, id ServerID, params GetConsoleHistoryParams) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (POST /pardon)
-func (_ Unimplemented) PostPardon(w http.ResponseWriter, r *http.Request) {
+// (POST /servers/{id}/deop)
+func (_ Unimplemented) PostDeop(w http.ResponseWriter, r *http.Request, id ServerID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (POST /pardon-ip)
-func (_ Unimplemented) PostPardonIp(w http.ResponseWriter, r *http.Request) {
+// (GET /servers/{id}/events)
+func (_ Unimplemented) GetEvents(w http.ResponseWriter, r *http.Request, id ServerID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (PUT /properties)
-func (_ Unimplemented) PutProperties(w http.ResponseWriter, r *http.Request) {
+// (GET /servers/{id}/java-runtimes)
+func (_ Unimplemented) GetJavaRuntimes(w http.ResponseWriter, r *http.Request, id ServerID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (POST /restart)
-func (_ Unimplemented) PostRestart(w http.ResponseWriter, r *http.Request) {
+// (POST /servers/{id}/op)
+func (_ Unimplemented) PostOp(w http.ResponseWriter, r *http.Request, id ServerID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (POST /set-version)
-func (_ Unimplemented) PostSetVersion(w http.ResponseWriter, r *http.Request, params PostSetVersionParams) {
+// (GET /servers/{id}/ops)
+func (_ Unimplemented) GetOps(w http.ResponseWriter, r *http.Request, id ServerID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (POST /start)
-func (_ Unimplemented) PostStart(w http.ResponseWriter, r *http.Request) {
+// (PUT /servers/{id}/ops)
+func (_ Unimplemented) PutOps(w http.ResponseWriter, r *http.Request, id ServerID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// (POST /stop)
-func (_ Unimplemented) PostStop(w http.ResponseWriter, r *http.Request) {
+// (POST /servers/{id}/pardon)
+func (_ Unimplemented) PostPardon(w http.ResponseWriter, r *http.Request, id ServerID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /servers/{id}/pardon-ip)
+func (_ Unimplemented) PostPardonIp(w http.ResponseWriter, r *http.Request, id ServerID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (PUT /servers/{id}/properties)
+func (_ Unimplemented) PutProperties(w http.ResponseWriter, r *http.Request, id ServerID, params PutPropertiesParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /servers/{id}/query)
+func (_ Unimplemented) GetQuery(w http.ResponseWriter, r *http.Request, id ServerID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /servers/{id}/restart)
+func (_ Unimplemented) PostRestart(w http.ResponseWriter, r *http.Request, id ServerID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /servers/{id}/set-version)
+func (_ Unimplemented) PostSetVersion(w http.ResponseWriter, r *http.Request, id ServerID, params PostSetVersionParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /servers/{id}/start)
+func (_ Unimplemented) PostStart(w http.ResponseWriter, r *http.Request, id ServerID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /servers/{id}/status)
+func (_ Unimplemented) GetStatus(w http.ResponseWriter, r *http.Request, id ServerID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /servers/{id}/stop)
+func (_ Unimplemented) PostStop(w http.ResponseWriter, r *http.Request, id ServerID) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
@@ -468,14 +1041,14 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(http.Handler) http.Handler
 
-// GetAllowlist operation middleware
-func (siw *ServerInterfaceWrapper) GetAllowlist(w http.ResponseWriter, r *http.Request) {
+// RefreshAvailableVersions operation middleware
+func (siw *ServerInterfaceWrapper) RefreshAvailableVersions(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"config"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetAllowlist(w, r)
+		siw.Handler.RefreshAvailableVersions(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -485,14 +1058,14 @@ func (siw *ServerInterfaceWrapper) GetAllowlist(w http.ResponseWriter, r *http.R
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PutAllowlist operation middleware
-func (siw *ServerInterfaceWrapper) PutAllowlist(w http.ResponseWriter, r *http.Request) {
+// ListServers operation middleware
+func (siw *ServerInterfaceWrapper) ListServers(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"read"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PutAllowlist(w, r)
+		siw.Handler.ListServers(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -502,14 +1075,14 @@ func (siw *ServerInterfaceWrapper) PutAllowlist(w http.ResponseWriter, r *http.R
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PostAllowlistAdd operation middleware
-func (siw *ServerInterfaceWrapper) PostAllowlistAdd(w http.ResponseWriter, r *http.Request) {
+// CreateServer operation middleware
+func (siw *ServerInterfaceWrapper) CreateServer(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"admin"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostAllowlistAdd(w, r)
+		siw.Handler.CreateServer(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -519,14 +1092,25 @@ func (siw *ServerInterfaceWrapper) PostAllowlistAdd(w http.ResponseWriter, r *ht
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PostAllowlistRemove operation middleware
-func (siw *ServerInterfaceWrapper) PostAllowlistRemove(w http.ResponseWriter, r *http.Request) {
+// DeleteServer operation middleware
+func (siw *ServerInterfaceWrapper) DeleteServer(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"admin"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostAllowlistRemove(w, r)
+		siw.Handler.DeleteServer(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -536,14 +1120,25 @@ func (siw *ServerInterfaceWrapper) PostAllowlistRemove(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PutArgs operation middleware
-func (siw *ServerInterfaceWrapper) PutArgs(w http.ResponseWriter, r *http.Request) {
+// GetServer operation middleware
+func (siw *ServerInterfaceWrapper) GetServer(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"read"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PutArgs(w, r)
+		siw.Handler.GetServer(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -553,14 +1148,25 @@ func (siw *ServerInterfaceWrapper) PutArgs(w http.ResponseWriter, r *http.Reques
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetAvailableVersions operation middleware
-func (siw *ServerInterfaceWrapper) GetAvailableVersions(w http.ResponseWriter, r *http.Request) {
+// GetAllowlist operation middleware
+func (siw *ServerInterfaceWrapper) GetAllowlist(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"read"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetAvailableVersions(w, r)
+		siw.Handler.GetAllowlist(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -570,14 +1176,25 @@ func (siw *ServerInterfaceWrapper) GetAvailableVersions(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PostBan operation middleware
-func (siw *ServerInterfaceWrapper) PostBan(w http.ResponseWriter, r *http.Request) {
+// PutAllowlist operation middleware
+func (siw *ServerInterfaceWrapper) PutAllowlist(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"moderation"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostBan(w, r)
+		siw.Handler.PutAllowlist(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -587,14 +1204,25 @@ func (siw *ServerInterfaceWrapper) PostBan(w http.ResponseWriter, r *http.Reques
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PostBanIp operation middleware
-func (siw *ServerInterfaceWrapper) PostBanIp(w http.ResponseWriter, r *http.Request) {
+// PostAllowlistAdd operation middleware
+func (siw *ServerInterfaceWrapper) PostAllowlistAdd(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"moderation"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostBanIp(w, r)
+		siw.Handler.PostAllowlistAdd(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -604,14 +1232,25 @@ func (siw *ServerInterfaceWrapper) PostBanIp(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetBannedIps operation middleware
-func (siw *ServerInterfaceWrapper) GetBannedIps(w http.ResponseWriter, r *http.Request) {
+// PostAllowlistRemove operation middleware
+func (siw *ServerInterfaceWrapper) PostAllowlistRemove(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"moderation"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetBannedIps(w, r)
+		siw.Handler.PostAllowlistRemove(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -621,14 +1260,409 @@ func (siw *ServerInterfaceWrapper) GetBannedIps(w http.ResponseWriter, r *http.R
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PutBannedIps operation middleware
-func (siw *ServerInterfaceWrapper) PutBannedIps(w http.ResponseWriter, r *http.Request) {
+// PutArgs operation middleware
+func (siw *ServerInterfaceWrapper) PutArgs(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"config"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PutArgs(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetAvailableVersions operation middleware
+func (siw *ServerInterfaceWrapper) GetAvailableVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAvailableVersionsParams
+
+	// ------------- Optional query parameter "type" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "type", r.URL.Query(), &params.Type)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "type", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAvailableVersions(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListBackups operation middleware
+func (siw *ServerInterfaceWrapper) ListBackups(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"read"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListBackups(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreateBackup operation middleware
+func (siw *ServerInterfaceWrapper) CreateBackup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"lifecycle"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateBackup(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetBackupSchedule operation middleware
+func (siw *ServerInterfaceWrapper) GetBackupSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"read"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetBackupSchedule(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PutBackupSchedule operation middleware
+func (siw *ServerInterfaceWrapper) PutBackupSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"lifecycle"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PutBackupSchedule(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteBackup operation middleware
+func (siw *ServerInterfaceWrapper) DeleteBackup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "backupId" -------------
+	var backupId BackupID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "backupId", chi.URLParam(r, "backupId"), &backupId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "backupId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"lifecycle"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteBackup(w, r, id, backupId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostBan operation middleware
+func (siw *ServerInterfaceWrapper) PostBan(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"moderation"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostBan(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostBanIp operation middleware
+func (siw *ServerInterfaceWrapper) PostBanIp(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"moderation"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostBanIp(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetBannedIps operation middleware
+func (siw *ServerInterfaceWrapper) GetBannedIps(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"read"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetBannedIps(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PutBannedIps operation middleware
+func (siw *ServerInterfaceWrapper) PutBannedIps(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"moderation"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PutBannedIps(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetBannedPlayers operation middleware
+func (siw *ServerInterfaceWrapper) GetBannedPlayers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"read"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetBannedPlayers(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PutBannedPlayers operation middleware
+func (siw *ServerInterfaceWrapper) PutBannedPlayers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"moderation"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PutBannedPlayers(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CloneServer operation middleware
+func (siw *ServerInterfaceWrapper) CloneServer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"admin"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PutBannedIps(w, r)
+		siw.Handler.CloneServer(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -638,14 +1672,36 @@ func (siw *ServerInterfaceWrapper) PutBannedIps(w http.ResponseWriter, r *http.R
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetBannedPlayers operation middleware
-func (siw *ServerInterfaceWrapper) GetBannedPlayers(w http.ResponseWriter, r *http.Request) {
+// GetConsole operation middleware
+func (siw *ServerInterfaceWrapper) GetConsole(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"console"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetConsoleParams
+
+	// ------------- Optional query parameter "since" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "since", r.URL.Query(), &params.Since)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "since", Err: err})
+		return
+	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetBannedPlayers(w, r)
+		siw.Handler.GetConsole(w, r, id, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -655,14 +1711,44 @@ func (siw *ServerInterfaceWrapper) GetBannedPlayers(w http.ResponseWriter, r *ht
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PutBannedPlayers operation middleware
-func (siw *ServerInterfaceWrapper) PutBannedPlayers(w http.ResponseWriter, r *http.Request) {
+// GetConsoleHistory operation middleware
+func (siw *ServerInterfaceWrapper) GetConsoleHistory(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"console"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetConsoleHistoryParams
+
+	// ------------- Optional query parameter "since" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "since", r.URL.Query(), &params.Since)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "since", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PutBannedPlayers(w, r)
+		siw.Handler.GetConsoleHistory(w, r, id, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -676,10 +1762,77 @@ func (siw *ServerInterfaceWrapper) PutBannedPlayers(w http.ResponseWriter, r *ht
 func (siw *ServerInterfaceWrapper) PostDeop(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"moderation"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostDeop(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetEvents operation middleware
+func (siw *ServerInterfaceWrapper) GetEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"read"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetEvents(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetJavaRuntimes operation middleware
+func (siw *ServerInterfaceWrapper) GetJavaRuntimes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"read"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostDeop(w, r)
+		siw.Handler.GetJavaRuntimes(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -693,10 +1846,21 @@ func (siw *ServerInterfaceWrapper) PostDeop(w http.ResponseWriter, r *http.Reque
 func (siw *ServerInterfaceWrapper) PostOp(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"moderation"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostOp(w, r)
+		siw.Handler.PostOp(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -710,10 +1874,21 @@ func (siw *ServerInterfaceWrapper) PostOp(w http.ResponseWriter, r *http.Request
 func (siw *ServerInterfaceWrapper) GetOps(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"read"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetOps(w, r)
+		siw.Handler.GetOps(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -727,10 +1902,21 @@ func (siw *ServerInterfaceWrapper) GetOps(w http.ResponseWriter, r *http.Request
 func (siw *ServerInterfaceWrapper) PutOps(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"moderation"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PutOps(w, r)
+		siw.Handler.PutOps(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -744,10 +1930,21 @@ func (siw *ServerInterfaceWrapper) PutOps(w http.ResponseWriter, r *http.Request
 func (siw *ServerInterfaceWrapper) PostPardon(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"moderation"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostPardon(w, r)
+		siw.Handler.PostPardon(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -761,10 +1958,21 @@ func (siw *ServerInterfaceWrapper) PostPardon(w http.ResponseWriter, r *http.Req
 func (siw *ServerInterfaceWrapper) PostPardonIp(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"moderation"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostPardonIp(w, r)
+		siw.Handler.PostPardonIp(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -778,10 +1986,60 @@ func (siw *ServerInterfaceWrapper) PostPardonIp(w http.ResponseWriter, r *http.R
 func (siw *ServerInterfaceWrapper) PutProperties(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"config"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PutPropertiesParams
+
+	// ------------- Optional query parameter "restart" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "restart", r.URL.Query(), &params.Restart)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "restart", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PutProperties(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetQuery operation middleware
+func (siw *ServerInterfaceWrapper) GetQuery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"read"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PutProperties(w, r)
+		siw.Handler.GetQuery(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -795,10 +2053,21 @@ func (siw *ServerInterfaceWrapper) PutProperties(w http.ResponseWriter, r *http.
 func (siw *ServerInterfaceWrapper) PostRestart(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"lifecycle"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostRestart(w, r)
+		siw.Handler.PostRestart(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -814,21 +2083,37 @@ func (siw *ServerInterfaceWrapper) PostSetVersion(w http.ResponseWriter, r *http
 
 	var err error
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"config"})
 
 	// Parameter object where we will unmarshal all parameters from the context
 	var params PostSetVersionParams
 
-	// ------------- Optional query parameter "version" -------------
+	// ------------- Required query parameter "version" -------------
 
-	err = runtime.BindQueryParameter("form", true, false, "version", r.URL.Query(), &params.Version)
+	if paramValue := r.URL.Query().Get("version"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "version"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "version", r.URL.Query(), &params.Version)
 	if err != nil {
 		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "version", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostSetVersion(w, r, params)
+		siw.Handler.PostSetVersion(w, r, id, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -842,10 +2127,49 @@ func (siw *ServerInterfaceWrapper) PostSetVersion(w http.ResponseWriter, r *http
 func (siw *ServerInterfaceWrapper) PostStart(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"lifecycle"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostStart(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetStatus operation middleware
+func (siw *ServerInterfaceWrapper) GetStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"read"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostStart(w, r)
+		siw.Handler.GetStatus(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -859,10 +2183,21 @@ func (siw *ServerInterfaceWrapper) PostStart(w http.ResponseWriter, r *http.Requ
 func (siw *ServerInterfaceWrapper) PostStop(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{})
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ServerID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, APIKeyAuthScopes, []string{"lifecycle"})
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostStop(w, r)
+		siw.Handler.PostStop(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -986,73 +2321,124 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	}
 
 	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/allowlist", wrapper.GetAllowlist)
+		r.Post(options.BaseURL+"/available-versions/refresh", wrapper.RefreshAvailableVersions)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/servers", wrapper.ListServers)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/servers", wrapper.CreateServer)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/servers/{id}", wrapper.DeleteServer)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/servers/{id}", wrapper.GetServer)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/servers/{id}/allowlist", wrapper.GetAllowlist)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/servers/{id}/allowlist", wrapper.PutAllowlist)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/servers/{id}/allowlist/add", wrapper.PostAllowlistAdd)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/servers/{id}/allowlist/remove", wrapper.PostAllowlistRemove)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/servers/{id}/args", wrapper.PutArgs)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/servers/{id}/available-versions", wrapper.GetAvailableVersions)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/servers/{id}/backups", wrapper.ListBackups)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/servers/{id}/backups", wrapper.CreateBackup)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/servers/{id}/backups/schedule", wrapper.GetBackupSchedule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/servers/{id}/backups/schedule", wrapper.PutBackupSchedule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/servers/{id}/backups/{backupId}", wrapper.DeleteBackup)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/servers/{id}/ban", wrapper.PostBan)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/servers/{id}/ban-ip", wrapper.PostBanIp)
 	})
 	r.Group(func(r chi.Router) {
-		r.Put(options.BaseURL+"/allowlist", wrapper.PutAllowlist)
+		r.Get(options.BaseURL+"/servers/{id}/banned-ips", wrapper.GetBannedIps)
 	})
 	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/allowlist/add", wrapper.PostAllowlistAdd)
+		r.Put(options.BaseURL+"/servers/{id}/banned-ips", wrapper.PutBannedIps)
 	})
 	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/allowlist/remove", wrapper.PostAllowlistRemove)
+		r.Get(options.BaseURL+"/servers/{id}/banned-players", wrapper.GetBannedPlayers)
 	})
 	r.Group(func(r chi.Router) {
-		r.Put(options.BaseURL+"/args", wrapper.PutArgs)
+		r.Put(options.BaseURL+"/servers/{id}/banned-players", wrapper.PutBannedPlayers)
 	})
 	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/available-versions", wrapper.GetAvailableVersions)
+		r.Post(options.BaseURL+"/servers/{id}/clone", wrapper.CloneServer)
 	})
 	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/ban", wrapper.PostBan)
+		r.Get(options.BaseURL+"/servers/{id}/console", wrapper.GetConsole)
 	})
 	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/ban-ip", wrapper.PostBanIp)
+		r.Get(options.BaseURL+"/servers/{id}/console/history", wrapper.GetConsoleHistory)
 	})
 	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/banned-ips", wrapper.GetBannedIps)
+		r.Post(options.BaseURL+"/servers/{id}/deop", wrapper.PostDeop)
 	})
 	r.Group(func(r chi.Router) {
-		r.Put(options.BaseURL+"/banned-ips", wrapper.PutBannedIps)
+		r.Get(options.BaseURL+"/servers/{id}/events", wrapper.GetEvents)
 	})
 	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/banned-players", wrapper.GetBannedPlayers)
+		r.Get(options.BaseURL+"/servers/{id}/java-runtimes", wrapper.GetJavaRuntimes)
 	})
 	r.Group(func(r chi.Router) {
-		r.Put(options.BaseURL+"/banned-players", wrapper.PutBannedPlayers)
+		r.Post(options.BaseURL+"/servers/{id}/op", wrapper.PostOp)
 	})
 	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/deop", wrapper.PostDeop)
+		r.Get(options.BaseURL+"/servers/{id}/ops", wrapper.GetOps)
 	})
 	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/op", wrapper.PostOp)
+		r.Put(options.BaseURL+"/servers/{id}/ops", wrapper.PutOps)
 	})
 	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/ops", wrapper.GetOps)
+		r.Post(options.BaseURL+"/servers/{id}/pardon", wrapper.PostPardon)
 	})
 	r.Group(func(r chi.Router) {
-		r.Put(options.BaseURL+"/ops", wrapper.PutOps)
+		r.Post(options.BaseURL+"/servers/{id}/pardon-ip", wrapper.PostPardonIp)
 	})
 	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/pardon", wrapper.PostPardon)
+		r.Put(options.BaseURL+"/servers/{id}/properties", wrapper.PutProperties)
 	})
 	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/pardon-ip", wrapper.PostPardonIp)
+		r.Get(options.BaseURL+"/servers/{id}/query", wrapper.GetQuery)
 	})
 	r.Group(func(r chi.Router) {
-		r.Put(options.BaseURL+"/properties", wrapper.PutProperties)
+		r.Post(options.BaseURL+"/servers/{id}/restart", wrapper.PostRestart)
 	})
 	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/restart", wrapper.PostRestart)
+		r.Post(options.BaseURL+"/servers/{id}/set-version", wrapper.PostSetVersion)
 	})
 	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/set-version", wrapper.PostSetVersion)
+		r.Post(options.BaseURL+"/servers/{id}/start", wrapper.PostStart)
 	})
 	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/start", wrapper.PostStart)
+		r.Get(options.BaseURL+"/servers/{id}/status", wrapper.GetStatus)
 	})
 	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/stop", wrapper.PostStop)
+		r.Post(options.BaseURL+"/servers/{id}/stop", wrapper.PostStop)
 	})
 
 	return r
@@ -0,0 +1,306 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// apiKeyPrefix starts every API key, so they're easy to recognize.
+	apiKeyPrefix = "mcsc_"
+
+	apiKeyIDBytes     = 8
+	apiKeySaltBytes   = 16
+	apiKeySecretBytes = 32
+)
+
+// Scopes of API keys. Every operation requires one, see the APIKeyAuth
+// security scheme.
+const (
+	ScopeRead       = "read"
+	ScopeConsole    = "console"
+	ScopeLifecycle  = "lifecycle"
+	ScopeConfig     = "config"
+	ScopeModeration = "moderation"
+	// ScopeAdmin grants every other scope.
+	ScopeAdmin = "admin"
+)
+
+// Scopes are all the scopes an API key can have.
+var Scopes = []string{ScopeRead, ScopeConsole, ScopeLifecycle, ScopeConfig, ScopeModeration, ScopeAdmin}
+
+var (
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	ErrInvalidAPIKey  = errors.New("invalid API key")
+	ErrUnknownScope   = errors.New("unknown API key scope")
+)
+
+// APIKey is an API key in a KeyStore. Only a salted hash of the secret part
+// of the key is kept.
+type APIKey struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Salt      string    `json:"salt"`
+	Hash      string    `json:"hash"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// HasScope reports whether the API key has scope, either directly or through
+// the admin scope.
+func (k *APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope) || slices.Contains(k.Scopes, ScopeAdmin)
+}
+
+// KeyStore keeps the API keys of the server controller in a file. API keys
+// look like `mcsc_<id>_<secret>`.
+//
+// The file is read again whenever it changes, so keys created or revoked by
+// another process, like the `keys` command, take effect right away.
+type KeyStore struct {
+	path    string
+	keys    []APIKey
+	modTime time.Time
+	size    int64
+
+	mutex sync.Mutex
+}
+
+func NewKeyStore(path string) *KeyStore {
+	return &KeyStore{path: path}
+}
+
+// Load reads the API keys from the key file. A missing key file has no keys.
+func (s *KeyStore) Load() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.load()
+}
+
+// Keys returns the API keys in the store.
+func (s *KeyStore) Keys() ([]APIKey, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.reloadIfChanged(); err != nil {
+		return nil, err
+	}
+
+	return append([]APIKey{}, s.keys...), nil
+}
+
+// Create creates an API key with the given scopes and saves it to the key
+// file. The returned key is the only time the key itself is available.
+func (s *KeyStore) Create(name string, scopes []string) (string, APIKey, error) {
+	if len(scopes) == 0 {
+		return "", APIKey{}, ErrUnknownScope
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(Scopes, scope) {
+			return "", APIKey{}, fmt.Errorf("%w: %s", ErrUnknownScope, scope)
+		}
+	}
+	scopes = slices.Clone(scopes)
+	slices.Sort(scopes)
+	scopes = slices.Compact(scopes)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.reloadIfChanged(); err != nil {
+		return "", APIKey{}, err
+	}
+
+	id, err := randomBytes(apiKeyIDBytes)
+	if err != nil {
+		return "", APIKey{}, err
+	}
+	salt, err := randomBytes(apiKeySaltBytes)
+	if err != nil {
+		return "", APIKey{}, err
+	}
+	secret, err := randomBytes(apiKeySecretBytes)
+	if err != nil {
+		return "", APIKey{}, err
+	}
+
+	key := APIKey{
+		ID:        hex.EncodeToString(id),
+		Name:      name,
+		Salt:      hex.EncodeToString(salt),
+		Scopes:    scopes,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	encodedSecret := base64.RawURLEncoding.EncodeToString(secret)
+	key.Hash = hashSecret(salt, encodedSecret)
+
+	s.keys = append(s.keys, key)
+	if err := s.save(); err != nil {
+		s.keys = s.keys[:len(s.keys)-1]
+		return "", APIKey{}, err
+	}
+
+	return apiKeyPrefix + key.ID + "_" + encodedSecret, key, nil
+}
+
+// Revoke deletes the API key with the given ID from the key file.
+func (s *KeyStore) Revoke(id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.reloadIfChanged(); err != nil {
+		return err
+	}
+
+	for i, key := range s.keys {
+		if key.ID == id {
+			s.keys = append(s.keys[:i], s.keys[i+1:]...)
+			return s.save()
+		}
+	}
+
+	return ErrAPIKeyNotFound
+}
+
+// Verify returns the API key matching key, or ErrInvalidAPIKey if there is
+// none.
+func (s *KeyStore) Verify(key string) (*APIKey, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+	id, secret, ok := strings.Cut(strings.TrimPrefix(key, apiKeyPrefix), "_")
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.reloadIfChanged(); err != nil {
+		return nil, err
+	}
+
+	for _, stored := range s.keys {
+		if stored.ID != id {
+			continue
+		}
+		salt, err := hex.DecodeString(stored.Salt)
+		if err != nil {
+			return nil, ErrInvalidAPIKey
+		}
+		hash := hashSecret(salt, secret)
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(stored.Hash)) != 1 {
+			return nil, ErrInvalidAPIKey
+		}
+		found := stored
+		return &found, nil
+	}
+
+	return nil, ErrInvalidAPIKey
+}
+
+// load reads the key file. s must be locked.
+func (s *KeyStore) load() error {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		s.keys, s.modTime, s.size = nil, time.Time{}, 0
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	var keys []APIKey
+	if err := json.NewDecoder(file).Decode(&keys); err != nil {
+		return err
+	}
+	// keys created before API keys had scopes keep the access they had
+	for i := range keys {
+		if keys[i].Scopes == nil {
+			keys[i].Scopes = []string{ScopeAdmin}
+		}
+	}
+	s.keys, s.modTime, s.size = keys, info.ModTime(), info.Size()
+
+	return nil
+}
+
+// reloadIfChanged reads the key file again if it changed since it was last
+// read. s must be locked.
+func (s *KeyStore) reloadIfChanged() error {
+	info, err := os.Stat(s.path)
+	if os.IsNotExist(err) {
+		s.keys, s.modTime, s.size = nil, time.Time{}, 0
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return nil
+	}
+
+	return s.load()
+}
+
+// save writes the key file, which only its owner can read, atomically so that
+// a crash or a full disk can't leave a truncated key file behind. s must be
+// locked.
+func (s *KeyStore) save() error {
+	keys := s.keys
+	if keys == nil {
+		keys = []APIKey{}
+	}
+
+	err := WriteAtomic(s.path, 0o600, func(file io.Writer) error {
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(keys)
+	})
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return err
+	}
+	s.modTime, s.size = info.ModTime(), info.Size()
+
+	return nil
+}
+
+func hashSecret(salt []byte, secret string) string {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(secret))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
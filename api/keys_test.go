@@ -0,0 +1,144 @@
+package api
+
+import (
+	"errors"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestKeyStore(t *testing.T) {
+	t.Parallel()
+
+	keysFile := path.Join(t.TempDir(), "api-keys.json")
+	store := NewKeyStore(keysFile)
+	if err := store.Load(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+
+	key, apiKey, err := store.Create("dashboard", []string{ScopeRead})
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if !strings.HasPrefix(key, "mcsc_"+apiKey.ID+"_") {
+		t.Fatalf("expected key to start with `mcsc_%s_`, got `%s`", apiKey.ID, key)
+	}
+
+	data, err := os.ReadFile(keysFile)
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if secret := key[strings.LastIndex(key, "_")+1:]; strings.Contains(string(data), secret) {
+		t.Fatal("expected the key file not to contain the secret")
+	}
+	if info, err := os.Stat(keysFile); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected key file permissions 0600, got `%v` with error `%v`", info.Mode().Perm(), err)
+	}
+
+	verified, err := store.Verify(key)
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if verified.ID != apiKey.ID || verified.Name != "dashboard" {
+		t.Fatalf("expected API key `%+v`, got `%+v`", apiKey, *verified)
+	}
+
+	testCases := []struct {
+		name string
+		key  string
+	}{
+		{name: "empty key", key: ""},
+		{name: "no prefix", key: strings.TrimPrefix(key, "mcsc_")},
+		{name: "no secret", key: "mcsc_" + apiKey.ID},
+		{name: "wrong secret", key: key[:len(key)-1] + "x"},
+		{name: "unknown ID", key: "mcsc_0000000000000000_" + key[strings.LastIndex(key, "_")+1:]},
+	}
+
+	for _, tc := range testCases {
+		if _, err := store.Verify(tc.key); !errors.Is(err, ErrInvalidAPIKey) {
+			t.Errorf("%s: expected error `%v`, got `%v`", tc.name, ErrInvalidAPIKey, err)
+		}
+	}
+
+	// keys created and revoked by another process are picked up
+	other := NewKeyStore(keysFile)
+	otherKey, otherAPIKey, err := other.Create("backups", []string{ScopeAdmin})
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if _, err := store.Verify(otherKey); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if keys, err := store.Keys(); err != nil || len(keys) != 2 {
+		t.Fatalf("expected 2 keys, got `%+v` with error `%v`", keys, err)
+	}
+
+	if err := other.Revoke(apiKey.ID); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if _, err := store.Verify(key); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Fatalf("expected error `%v`, got `%v`", ErrInvalidAPIKey, err)
+	}
+	if err := store.Revoke(apiKey.ID); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Fatalf("expected error `%v`, got `%v`", ErrAPIKeyNotFound, err)
+	}
+	if keys, err := store.Keys(); err != nil || len(keys) != 1 || keys[0].ID != otherAPIKey.ID {
+		t.Fatalf("expected only key `%s`, got `%+v` with error `%v`", otherAPIKey.ID, keys, err)
+	}
+}
+
+func TestKeyStoreScopes(t *testing.T) {
+	t.Parallel()
+
+	keysFile := path.Join(t.TempDir(), "api-keys.json")
+	store := NewKeyStore(keysFile)
+
+	testCases := []struct {
+		name       string
+		scopes     []string
+		wantScopes []string
+		wantErr    error
+	}{
+		{name: "one scope", scopes: []string{ScopeRead}, wantScopes: []string{ScopeRead}},
+		{
+			name:       "duplicate scopes",
+			scopes:     []string{ScopeModeration, ScopeRead, ScopeModeration},
+			wantScopes: []string{ScopeModeration, ScopeRead},
+		},
+		{name: "no scopes", scopes: nil, wantErr: ErrUnknownScope},
+		{name: "unknown scope", scopes: []string{ScopeRead, "root"}, wantErr: ErrUnknownScope},
+	}
+
+	for _, tc := range testCases {
+		_, apiKey, err := store.Create(tc.name, tc.scopes)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: expected error `%v`, got `%v`", tc.name, tc.wantErr, err)
+			continue
+		}
+		if !slices.Equal(apiKey.Scopes, tc.wantScopes) {
+			t.Errorf("%s: expected scopes `%v`, got `%v`", tc.name, tc.wantScopes, apiKey.Scopes)
+		}
+	}
+
+	// keys created before API keys had scopes can do everything
+	legacy := `[{"id": "00", "name": "legacy", "salt": "00", "hash": "00", "createdAt": "2024-01-01T00:00:00Z"}]`
+	if err := os.WriteFile(keysFile, []byte(legacy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := store.Keys()
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	for _, scope := range Scopes {
+		if !keys[0].HasScope(scope) {
+			t.Errorf("expected the legacy key to have scope `%s`", scope)
+		}
+	}
+
+	readOnly := APIKey{Scopes: []string{ScopeRead}}
+	if !readOnly.HasScope(ScopeRead) || readOnly.HasScope(ScopeConfig) {
+		t.Errorf("expected only scope `%s`, got `%v`", ScopeRead, readOnly.Scopes)
+	}
+}
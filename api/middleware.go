@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader is the header that carries the ID of a request. Clients may
+// pass their own request IDs, which are sent back in the response.
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDPattern matches the request IDs clients may pass.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+type requestIDContextKey struct{}
+
+// NewRouter returns the handler of the API of si, with request IDs and
+// recovery from panics. middlewares, like APIKeyAuth, run before every
+// operation.
+func NewRouter(si ServerInterface, middlewares ...MiddlewareFunc) http.Handler {
+	r := chi.NewMux()
+	r.Use(RequestID, Recoverer)
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, r, fmt.Errorf("%w: %s", ErrRouteNotFound, r.URL.Path))
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, r, invalidRequest(fmt.Sprintf("method %s not allowed", r.Method)))
+	})
+
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseRouter:       r,
+		Middlewares:      middlewares,
+		ErrorHandlerFunc: WriteRequestError,
+	})
+}
+
+// RequestID is a middleware that gives every request an ID, the one passed in
+// the X-Request-ID header if there is a valid one, and sends it back in the
+// X-Request-ID response header.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = uuid.NewString()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		ctx := context.WithValue(r.Context(), requestIDContextKey{}, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestIDFromContext returns the ID the RequestID middleware gave a
+// request, or an empty string if it didn't give it one.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
+
+// Recoverer is a middleware that recovers from panics in handlers, replying
+// with an internal error instead of dropping the connection.
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				// the handler meant to abort the response
+				panic(v)
+			}
+
+			writeError(w, r, fmt.Errorf("panic: %v\n%s", v, debug.Stack()))
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// panickingServer panics when asked for its status.
+type panickingServer struct {
+	MinecraftServerInterface
+}
+
+func (p *panickingServer) Status() *ServerStatus {
+	panic("status is broken")
+}
+
+func TestNewRouter(t *testing.T) {
+	t.Parallel()
+
+	handler := newTestHandler(&panickingServer{})
+
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		requestID  string
+		wantStatus int
+		wantCode   ErrorCode
+	}{
+		{name: "panic", method: http.MethodGet, path: "/servers/test/status", wantStatus: http.StatusInternalServerError, wantCode: InternalError},
+		{name: "unknown route", method: http.MethodGet, path: "/nowhere", wantStatus: http.StatusNotFound, wantCode: NotFound},
+		{name: "method not allowed", method: http.MethodDelete, path: "/servers/test/status", wantStatus: http.StatusBadRequest, wantCode: InvalidRequest},
+		{name: "missing parameter", method: http.MethodPost, path: "/servers/test/set-version", wantStatus: http.StatusBadRequest, wantCode: InvalidRequest},
+		{name: "malformed parameter", method: http.MethodGet, path: "/servers/test/console/history?limit=ten", wantStatus: http.StatusBadRequest, wantCode: InvalidRequest},
+		{name: "passed request ID", method: http.MethodGet, path: "/nowhere", requestID: "req-42", wantStatus: http.StatusNotFound, wantCode: NotFound},
+		{name: "invalid request ID", method: http.MethodGet, path: "/nowhere", requestID: "req 42\n", wantStatus: http.StatusNotFound, wantCode: NotFound},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(tc.method, tc.path, nil)
+			if len(tc.requestID) > 0 {
+				r.Header.Set(RequestIDHeader, tc.requestID)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d", tc.wantStatus, w.Code)
+			}
+
+			var e Error
+			if err := json.NewDecoder(w.Body).Decode(&e); err != nil {
+				t.Fatalf("expected no error, got `%v`", err)
+			}
+			if e.Code != tc.wantCode {
+				t.Fatalf("expected error code `%s`, got `%s`", tc.wantCode, e.Code)
+			}
+
+			requestID := w.Header().Get(RequestIDHeader)
+			if len(requestID) == 0 || e.RequestId != requestID {
+				t.Fatalf("expected request ID `%s` in the error, got `%s`", requestID, e.RequestId)
+			}
+			if requestIDPattern.MatchString(tc.requestID) && requestID != tc.requestID {
+				t.Fatalf("expected request ID `%s`, got `%s`", tc.requestID, requestID)
+			}
+			if strings.Contains(e.Message, "status is broken") {
+				t.Fatalf("expected the panic to be hidden, got message `%s`", e.Message)
+			}
+		})
+	}
+}
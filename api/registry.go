@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrServerNotFound  = errors.New("minecraft server not found")
+	ErrServerExists    = errors.New("minecraft server already exists")
+	ErrInvalidServerID = errors.New("invalid minecraft server ID")
+	ErrServerInUse     = errors.New("minecraft server is in use")
+	ErrPortInUse       = errors.New("port is used by another minecraft server")
+	ErrInvalidServer   = errors.New("invalid minecraft server configuration")
+)
+
+// ServerRegistryInterface manages the Minecraft servers of the server
+// controller.
+type ServerRegistryInterface interface {
+	Servers() []ServerInstance
+	Instance(id string) (*ServerInstance, error)
+	Server(id string) (MinecraftServerInterface, error)
+	CreateServer(req CreateServerRequest) (*ServerInstance, error)
+	CloneServer(id string, req CloneServerRequest) (*ServerInstance, error)
+	DeleteServer(id string) error
+	RefreshVersions(ctx context.Context) ([]string, error)
+}
+
+// ListServers implements ServerInterface.
+func (s *ServerController) ListServers(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.servers.Servers())
+}
+
+// CreateServer implements ServerInterface.
+func (s *ServerController) CreateServer(w http.ResponseWriter, r *http.Request) {
+	var req CreateServerRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	instance, err := s.servers.CreateServer(req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, instance)
+}
+
+// GetServer implements ServerInterface.
+func (s *ServerController) GetServer(w http.ResponseWriter, r *http.Request, id string) {
+	instance, err := s.servers.Instance(id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, instance)
+}
+
+// DeleteServer implements ServerInterface.
+func (s *ServerController) DeleteServer(w http.ResponseWriter, r *http.Request, id string) {
+	if err := s.servers.DeleteServer(id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CloneServer implements ServerInterface.
+func (s *ServerController) CloneServer(w http.ResponseWriter, r *http.Request, id string) {
+	var req CloneServerRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	instance, err := s.servers.CloneServer(id, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, instance)
+}
+
+// server returns the Minecraft server with the given ID, replying with 404 Not
+// Found if there isn't one.
+func (s *ServerController) server(w http.ResponseWriter, r *http.Request, id string) (MinecraftServerInterface, bool) {
+	msi, err := s.servers.Server(id)
+	if err != nil {
+		writeError(w, r, err)
+		return nil, false
+	}
+
+	return msi, true
+}
+
+// RefreshAvailableVersions implements ServerInterface.
+func (s *ServerController) RefreshAvailableVersions(w http.ResponseWriter, r *http.Request) {
+	versions, err := s.servers.RefreshVersions(r.Context())
+	if err != nil {
+		if !errors.Is(err, ErrCatalogRefresh) {
+			err = fmt.Errorf("%w: %w", ErrCatalogRefresh, err)
+		}
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, versions)
+}
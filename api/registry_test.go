@@ -0,0 +1,222 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeRegistry keeps Minecraft servers in memory.
+type fakeRegistry struct {
+	servers    map[string]MinecraftServerInterface
+	inUse      map[string]bool
+	versions   []string
+	refreshErr error
+	mutex      sync.Mutex
+}
+
+func newFakeRegistry() *fakeRegistry {
+	return &fakeRegistry{
+		servers: make(map[string]MinecraftServerInterface),
+		inUse:   make(map[string]bool),
+	}
+}
+
+// newTestHandler serves the API for msi as the Minecraft server `test`.
+func newTestHandler(msi MinecraftServerInterface) http.Handler {
+	registry := newFakeRegistry()
+	registry.servers["test"] = msi
+
+	return NewRouter(NewServerController(registry))
+}
+
+func (f *fakeRegistry) Servers() []ServerInstance {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	instances := make([]ServerInstance, 0, len(f.servers))
+	for id := range f.servers {
+		instances = append(instances, ServerInstance{Id: id, State: Stopped})
+	}
+	slices.SortFunc(instances, func(a, b ServerInstance) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	return instances
+}
+
+func (f *fakeRegistry) Instance(id string) (*ServerInstance, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, ok := f.servers[id]; !ok {
+		return nil, ErrServerNotFound
+	}
+
+	return &ServerInstance{Id: id, State: Stopped}, nil
+}
+
+func (f *fakeRegistry) Server(id string) (MinecraftServerInterface, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	msi, ok := f.servers[id]
+	if !ok {
+		return nil, ErrServerNotFound
+	}
+
+	return msi, nil
+}
+
+func (f *fakeRegistry) CreateServer(req CreateServerRequest) (*ServerInstance, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(req.Id) == 0 || strings.ContainsAny(req.Id, "/.") {
+		return nil, ErrInvalidServerID
+	}
+	if _, ok := f.servers[req.Id]; ok {
+		return nil, ErrServerExists
+	}
+	f.servers[req.Id] = nil
+
+	return &ServerInstance{Id: req.Id, State: Stopped}, nil
+}
+
+func (f *fakeRegistry) CloneServer(id string, req CloneServerRequest) (*ServerInstance, error) {
+	f.mutex.Lock()
+	if _, ok := f.servers[id]; !ok {
+		f.mutex.Unlock()
+		return nil, ErrServerNotFound
+	}
+	if f.inUse[id] {
+		f.mutex.Unlock()
+		return nil, ErrServerInUse
+	}
+	f.mutex.Unlock()
+
+	return f.CreateServer(CreateServerRequest{Id: req.Id, Port: req.Port})
+}
+
+func (f *fakeRegistry) DeleteServer(id string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, ok := f.servers[id]; !ok {
+		return ErrServerNotFound
+	}
+	if f.inUse[id] {
+		return ErrServerInUse
+	}
+	delete(f.servers, id)
+
+	return nil
+}
+
+func (f *fakeRegistry) RefreshVersions(ctx context.Context) ([]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.refreshErr != nil {
+		return nil, f.refreshErr
+	}
+	f.versions = append(f.versions, "24w14a")
+
+	return f.versions, nil
+}
+
+func TestServerRegistryRoutes(t *testing.T) {
+	t.Parallel()
+
+	registry := newFakeRegistry()
+	registry.servers["survival"] = nil
+	registry.servers["creative"] = nil
+	registry.inUse["survival"] = true
+	handler := NewRouter(NewServerController(registry))
+
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{name: "get server", method: http.MethodGet, path: "/servers/survival", wantStatus: http.StatusOK},
+		{name: "get missing server", method: http.MethodGet, path: "/servers/lobby", wantStatus: http.StatusNotFound},
+		{name: "create server", method: http.MethodPost, path: "/servers", body: `{"id": "lobby"}`, wantStatus: http.StatusCreated},
+		{name: "create existing server", method: http.MethodPost, path: "/servers", body: `{"id": "creative"}`, wantStatus: http.StatusConflict},
+		{name: "create server with invalid ID", method: http.MethodPost, path: "/servers", body: `{"id": "../etc"}`, wantStatus: http.StatusBadRequest},
+		{name: "create server with invalid body", method: http.MethodPost, path: "/servers", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "clone server", method: http.MethodPost, path: "/servers/creative/clone", body: `{"id": "creative-2"}`, wantStatus: http.StatusCreated},
+		{name: "clone running server", method: http.MethodPost, path: "/servers/survival/clone", body: `{"id": "survival-2"}`, wantStatus: http.StatusConflict},
+		{name: "clone missing server", method: http.MethodPost, path: "/servers/lobby-2/clone", body: `{"id": "lobby-3"}`, wantStatus: http.StatusNotFound},
+		{name: "delete running server", method: http.MethodDelete, path: "/servers/survival", wantStatus: http.StatusConflict},
+		{name: "delete server", method: http.MethodDelete, path: "/servers/creative", wantStatus: http.StatusNoContent},
+		{name: "delete missing server", method: http.MethodDelete, path: "/servers/creative", wantStatus: http.StatusNotFound},
+		{name: "route of missing server", method: http.MethodGet, path: "/servers/creative/status", wantStatus: http.StatusNotFound},
+	}
+
+	// the test cases build on each other, so they aren't run in parallel
+	for _, tc := range testCases {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body)))
+		if w.Code != tc.wantStatus {
+			t.Errorf("%s: expected status %d, got %d", tc.name, tc.wantStatus, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/servers", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var instances []ServerInstance
+	if err := json.NewDecoder(w.Body).Decode(&instances); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	want := []ServerInstance{
+		{Id: "creative-2", State: Stopped},
+		{Id: "lobby", State: Stopped},
+		{Id: "survival", State: Stopped},
+	}
+	if !reflect.DeepEqual(want, instances) {
+		t.Fatalf("expected servers `%+v`, got `%+v`", want, instances)
+	}
+}
+
+func TestRefreshAvailableVersions(t *testing.T) {
+	t.Parallel()
+
+	registry := newFakeRegistry()
+	registry.versions = []string{"1.20.6"}
+	handler := NewRouter(NewServerController(registry))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/available-versions/refresh", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var versions []string
+	if err := json.NewDecoder(w.Body).Decode(&versions); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if want := []string{"1.20.6", "24w14a"}; !reflect.DeepEqual(want, versions) {
+		t.Fatalf("expected versions `%v`, got `%v`", want, versions)
+	}
+
+	registry.refreshErr = errors.New("failed to refresh version catalog")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/available-versions/refresh", nil))
+	if w.Code != http.StatusBadGateway {
+		t.Fatalf("expected status %d, got %d", http.StatusBadGateway, w.Code)
+	}
+}
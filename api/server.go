@@ -2,138 +2,485 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
+	"log"
 	"net/http"
+
+	"github.com/google/uuid"
 )
 
 type ServerController struct {
-	msi MinecraftServerInterface
+	servers ServerRegistryInterface
 }
 
 // GetAllowlist implements ServerInterface.
-func (s *ServerController) GetAllowlist(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) GetAllowlist(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	allowlist := msi.Allowlist()
+	if allowlist == nil {
+		writeError(w, r, ErrNilConfig)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, allowlist)
 }
 
 // GetAvailableVersions implements ServerInterface.
-func (s *ServerController) GetAvailableVersions(w http.ResponseWriter, r *http.Request) {
-	versions := s.msi.Versions()
-
-	if versions == nil {
-		w.WriteHeader(http.StatusInternalServerError)
+func (s *ServerController) GetAvailableVersions(w http.ResponseWriter, r *http.Request, id string, params GetAvailableVersionsParams) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
 		return
 	}
 
-	w.Header().Add("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(versions); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+	var versions *[]string
+	if params.Type != nil {
+		switch *params.Type {
+		case Release, Snapshot, OldBeta, OldAlpha:
+			versions = msi.VersionsOfType(*params.Type)
+		default:
+			writeError(w, r, invalidRequest(fmt.Sprintf("unknown release type `%s`", *params.Type)))
+			return
+		}
+	} else {
+		versions = msi.Versions()
+	}
+
+	if versions == nil {
+		writeError(w, r, ErrNilConfig)
 		return
 	}
+
+	writeJSON(w, http.StatusOK, versions)
 }
 
 // GetBannedIps implements ServerInterface.
-func (s *ServerController) GetBannedIps(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) GetBannedIps(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	bannedIPs := msi.BannedIPs()
+	if bannedIPs == nil {
+		writeError(w, r, ErrNilConfig)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, bannedIPs)
 }
 
 // GetBannedPlayers implements ServerInterface.
-func (s *ServerController) GetBannedPlayers(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) GetBannedPlayers(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	bannedPlayers := msi.BannedPlayers()
+	if bannedPlayers == nil {
+		writeError(w, r, ErrNilConfig)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, bannedPlayers)
+}
+
+// GetJavaRuntimes implements ServerInterface.
+func (s *ServerController) GetJavaRuntimes(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, msi.JavaRuntimes())
 }
 
 // GetOps implements ServerInterface.
-func (s *ServerController) GetOps(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) GetOps(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	ops := msi.Ops()
+	if ops == nil {
+		writeError(w, r, ErrNilConfig)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ops)
 }
 
 // PostAllowlistAdd implements ServerInterface.
-func (s *ServerController) PostAllowlistAdd(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) PostAllowlistAdd(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	var p PlayerInfo
+	if !decodePlayer(w, r, &p) {
+		return
+	}
+
+	update(w, r, msi, func() error { return msi.AllowPlayer(&p) })
 }
 
 // PostAllowlistRemove implements ServerInterface.
-func (s *ServerController) PostAllowlistRemove(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) PostAllowlistRemove(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	var p PlayerInfo
+	if !decodePlayer(w, r, &p) {
+		return
+	}
+
+	update(w, r, msi, func() error { return msi.DisallowPlayer(&p) })
 }
 
 // PostBan implements ServerInterface.
-func (s *ServerController) PostBan(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) PostBan(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	var p BannedPlayer
+	if !decodeJSON(w, r, &p) {
+		return
+	}
+	if !hasPlayerInfo(PlayerInfo{Name: p.Name, Uuid: &p.Uuid}) {
+		writeError(w, r, errNoPlayerInfo)
+		return
+	}
+
+	update(w, r, msi, func() error { return msi.BanPlayer(&p) })
 }
 
 // PostBanIp implements ServerInterface.
-func (s *ServerController) PostBanIp(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) PostBanIp(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	var ip BannedIP
+	if !decodeJSON(w, r, &ip) {
+		return
+	}
+
+	update(w, r, msi, func() error { return msi.BanIP(&ip) })
 }
 
 // PostDeop implements ServerInterface.
-func (s *ServerController) PostDeop(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) PostDeop(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	var p PlayerInfo
+	if !decodePlayer(w, r, &p) {
+		return
+	}
+
+	update(w, r, msi, func() error { return msi.Deop(&p) })
 }
 
 // PostOp implements ServerInterface.
-func (s *ServerController) PostOp(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) PostOp(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	var op ServerOperator
+	if !decodeJSON(w, r, &op) {
+		return
+	}
+	if !hasPlayerInfo(PlayerInfo{Name: &op.Name, Uuid: &op.Uuid}) {
+		writeError(w, r, errNoPlayerInfo)
+		return
+	}
+
+	update(w, r, msi, func() error { return msi.Op(&op) })
 }
 
 // PostPardon implements ServerInterface.
-func (s *ServerController) PostPardon(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) PostPardon(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	var p PlayerInfo
+	if !decodePlayer(w, r, &p) {
+		return
+	}
+
+	update(w, r, msi, func() error { return msi.PardonPlayer(&p) })
 }
 
 // PostPardonIp implements ServerInterface.
-func (s *ServerController) PostPardonIp(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) PostPardonIp(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	var req PostPardonIpJSONRequestBody
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	update(w, r, msi, func() error { return msi.PardonIP(req.Ip) })
 }
 
 // PostRestart implements ServerInterface.
-func (s *ServerController) PostRestart(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) PostRestart(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	if err := msi.Restart(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeMessage(w, http.StatusOK, "minecraft server restarted")
 }
 
 // PostSetVersion implements ServerInterface.
-func (s *ServerController) PostSetVersion(w http.ResponseWriter, r *http.Request, params PostSetVersionParams) {
-	panic("unimplemented")
+func (s *ServerController) PostSetVersion(w http.ResponseWriter, r *http.Request, id string, params PostSetVersionParams) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	update(w, r, msi, func() error { return msi.SetVersion(params.Version) })
 }
 
 // PostStart implements ServerInterface.
-func (s *ServerController) PostStart(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) PostStart(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	if err := msi.Start(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeMessage(w, http.StatusOK, "minecraft server started")
 }
 
 // PostStop implements ServerInterface.
-func (s *ServerController) PostStop(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) PostStop(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	if err := msi.Stop(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeMessage(w, http.StatusOK, "minecraft server stopped")
 }
 
 // PutAllowlist implements ServerInterface.
-func (s *ServerController) PutAllowlist(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) PutAllowlist(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	var allowlist Allowlist
+	if !decodeJSON(w, r, &allowlist) {
+		return
+	}
+
+	update(w, r, msi, func() error {
+		msi.SetAllowlist(&allowlist)
+		return nil
+	})
 }
 
 // PutArgs implements ServerInterface.
-func (s *ServerController) PutArgs(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) PutArgs(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	var args ServerArguments
+	if !decodeJSON(w, r, &args) {
+		return
+	}
+
+	update(w, r, msi, func() error { return msi.SetArgs(&args) })
 }
 
 // PutBannedIps implements ServerInterface.
-func (s *ServerController) PutBannedIps(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) PutBannedIps(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	var bannedIPs BannedIPList
+	if !decodeJSON(w, r, &bannedIPs) {
+		return
+	}
+
+	update(w, r, msi, func() error {
+		msi.SetBannedIPs(&bannedIPs)
+		return nil
+	})
 }
 
 // PutBannedPlayers implements ServerInterface.
-func (s *ServerController) PutBannedPlayers(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) PutBannedPlayers(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	var bannedPlayers BannedPlayerList
+	if !decodeJSON(w, r, &bannedPlayers) {
+		return
+	}
+
+	update(w, r, msi, func() error {
+		msi.SetBannedPlayers(&bannedPlayers)
+		return nil
+	})
 }
 
 // PutOps implements ServerInterface.
-func (s *ServerController) PutOps(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+func (s *ServerController) PutOps(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	var ops ServerOperatorList
+	if !decodeJSON(w, r, &ops) {
+		return
+	}
+
+	update(w, r, msi, func() error {
+		msi.SetOperators(&ops)
+		return nil
+	})
 }
 
 // PutProperties implements ServerInterface.
-func (s *ServerController) PutProperties(w http.ResponseWriter, r *http.Request) {
-	panic("unimplemented")
+//
+// PutProperties replies with the properties that changed, which of them were
+// applied to the running Minecraft server and which of them are pending a
+// restart. If the restart parameter is set, the Minecraft server is restarted
+// when any are.
+func (s *ServerController) PutProperties(w http.ResponseWriter, r *http.Request, id string, params PutPropertiesParams) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	var props ServerProperties
+	if !decodeJSON(w, r, &props) {
+		return
+	}
+
+	result, err := msi.UpdateProperties(&props)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := msi.SaveConfigs(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if params.Restart != nil && *params.Restart && len(result.PendingRestart) > 0 {
+		if err := msi.Restart(); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		result.Restarted = true
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// errNoPlayerInfo is the error players without a name or a UUID are rejected
+// with.
+var errNoPlayerInfo = invalidRequest("player must have a name or a UUID")
+
+// update changes the configuration of msi with change and saves it to disk,
+// replying with 200 OK, or with the error if either fails.
+func update(w http.ResponseWriter, r *http.Request, msi MinecraftServerInterface, change func() error) {
+	if err := change(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := msi.SaveConfigs(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// decodeJSON decodes the JSON request body into v, replying with 400 Bad
+// Request if it can't be decoded.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, r, invalidRequest("malformed request body: "+err.Error()))
+		return false
+	}
+
+	return true
+}
+
+// decodePlayer decodes a PlayerInfo request body into p, replying with 400 Bad
+// Request if it can't be decoded or has neither a name nor a UUID.
+func decodePlayer(w http.ResponseWriter, r *http.Request, p *PlayerInfo) bool {
+	if !decodeJSON(w, r, p) {
+		return false
+	}
+	if !hasPlayerInfo(*p) {
+		writeError(w, r, errNoPlayerInfo)
+		return false
+	}
+
+	return true
+}
+
+// hasPlayerInfo reports whether p has a name or a UUID to refer to the player
+// by.
+func hasPlayerInfo(p PlayerInfo) bool {
+	return (p.Name != nil && len(*p.Name) > 0) || (p.Uuid != nil && *p.Uuid != uuid.Nil)
+}
+
+// writeJSON replies with status and v encoded as JSON.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		// the status code has already been sent
+		log.Println("error encoding response:", err)
+	}
 }
 
 type MinecraftServerConfig struct {
@@ -147,6 +494,14 @@ type MinecraftServerInterface interface {
 	AllowPlayer(p *PlayerInfo) error
 	DisallowPlayer(p *PlayerInfo) error
 
+	// backup methods
+
+	Backups() (*BackupList, error)
+	CreateBackup() (*Backup, error)
+	DeleteBackup(id string) error
+	BackupSchedule() *BackupSchedule
+	SetBackupSchedule(schedule *BackupSchedule) error
+
 	// ban and unban methods
 
 	BannedIPs() *BannedIPList
@@ -156,10 +511,22 @@ type MinecraftServerInterface interface {
 	PardonIP(ip string) error
 	PardonPlayer(p *PlayerInfo) error
 
+	// console methods
+
+	AttachRCON() error
+	ConsoleHistory(since int64, limit int) []ConsoleLine
+	SendCommand(cmd string) error
+	SubscribeConsole() (lines <-chan ConsoleLine, cancel func())
+
+	// config event methods
+
+	SubscribeConfigEvents() (events <-chan ConfigEvent, cancel func())
+
 	// config files initialization methods
 
 	CreateAllowlist()
 	CreateArgs()
+	CreateBackupSchedule()
 	CreateBannedIPs()
 	CreateBannedPlayers()
 	CreateConfig()
@@ -177,6 +544,7 @@ type MinecraftServerInterface interface {
 
 	LoadAllowlist(file io.Reader) error
 	LoadArgs(file io.Reader) error
+	LoadBackupSchedule(file io.Reader) error
 	LoadBannedIPs(file io.Reader) error
 	LoadBannedPlayers(file io.Reader) error
 	LoadConfig(file io.Reader) error
@@ -191,36 +559,45 @@ type MinecraftServerInterface interface {
 	Config() *MinecraftServerConfig
 	Properties() *ServerProperties
 	Versions() *[]string
+	VersionsOfType(releaseType ReleaseType) *[]string
+	JavaRuntimes() *[]JavaRuntime
 
 	// save config files methods
 
 	SaveAllowlist(file io.Writer) error
 	SaveArgs(file io.Writer) error
+	SaveBackupSchedule(file io.Writer) error
 	SaveBannedIPs(file io.Writer) error
 	SaveBannedPlayers(file io.Writer) error
 	SaveConfig(file io.Writer) error
 	SaveOperators(file io.Writer) error
 	SaveProperties(file io.Writer) error
+	SaveConfigs() error
 
 	// update server configs methods (doesn't save to disk)
 
 	SetAllowlist(a *Allowlist)
-	SetArgs(args *ServerArguments)
+	SetArgs(args *ServerArguments) error
 	SetBannedPlayers(bp *BannedPlayerList)
 	SetVersion(version string) error
+	SetManifestBaseURL(baseURL string)
+	SetJavaDirs(dirs []string)
 	SetBannedIPs(bp *BannedIPList)
 	SetOperators(ops *ServerOperatorList)
 	SetProperties(props *ServerProperties)
+	UpdateProperties(props *ServerProperties) (*PropertiesUpdate, error)
 
 	// mc server process management methods
 
 	Start() error
 	Stop() error
 	Restart() error
+	Status() *ServerStatus
+	Query() (*QueryStatus, error)
 }
 
 var _ ServerInterface = (*ServerController)(nil)
 
-func NewServerController(msi MinecraftServerInterface) ServerInterface {
-	return &ServerController{msi}
+func NewServerController(servers ServerRegistryInterface) ServerInterface {
+	return &ServerController{servers}
 }
@@ -0,0 +1,499 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type fakeVersionsServer struct {
+	MinecraftServerInterface
+
+	versions map[ReleaseType][]string
+}
+
+func (f *fakeVersionsServer) Versions() *[]string {
+	versions := make([]string, 0)
+	for _, releaseType := range []ReleaseType{Release, Snapshot} {
+		versions = append(versions, f.versions[releaseType]...)
+	}
+
+	return &versions
+}
+
+func (f *fakeVersionsServer) VersionsOfType(releaseType ReleaseType) *[]string {
+	versions := append([]string{}, f.versions[releaseType]...)
+	return &versions
+}
+
+func TestGetAvailableVersions(t *testing.T) {
+	t.Parallel()
+
+	msi := &fakeVersionsServer{
+		versions: map[ReleaseType][]string{
+			Release:  {"1.20.5", "1.20.6"},
+			Snapshot: {"24w13a"},
+		},
+	}
+	handler := newTestHandler(msi)
+
+	testCases := []struct {
+		name         string
+		query        string
+		wantStatus   int
+		wantVersions []string
+	}{
+		{
+			name:         "all versions",
+			query:        "",
+			wantStatus:   http.StatusOK,
+			wantVersions: []string{"1.20.5", "1.20.6", "24w13a"},
+		},
+		{
+			name:         "releases",
+			query:        "?type=release",
+			wantStatus:   http.StatusOK,
+			wantVersions: []string{"1.20.5", "1.20.6"},
+		},
+		{
+			name:         "old betas",
+			query:        "?type=old_beta",
+			wantStatus:   http.StatusOK,
+			wantVersions: []string{},
+		},
+		{
+			name:       "unknown release type",
+			query:      "?type=nightly",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/servers/test/available-versions"+tc.query, nil))
+
+			if w.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d", tc.wantStatus, w.Code)
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+
+			var versions []string
+			if err := json.NewDecoder(w.Body).Decode(&versions); err != nil {
+				t.Fatalf("expected no error, got `%v`", err)
+			}
+			if !reflect.DeepEqual(tc.wantVersions, versions) {
+				t.Fatalf("expected versions `%v`, got `%v`", tc.wantVersions, versions)
+			}
+		})
+	}
+}
+
+type fakeJavaServer struct {
+	MinecraftServerInterface
+
+	runtimes []JavaRuntime
+}
+
+func (f *fakeJavaServer) JavaRuntimes() *[]JavaRuntime {
+	return &f.runtimes
+}
+
+func TestGetJavaRuntimes(t *testing.T) {
+	t.Parallel()
+
+	want := []JavaRuntime{
+		{Path: "/usr/lib/jvm/java-21-openjdk/bin/java", Version: "21.0.2", MajorVersion: 21},
+		{Path: "/usr/lib/jvm/java-8-openjdk/bin/java", Version: "1.8.0_392", MajorVersion: 8},
+	}
+	handler := newTestHandler(&fakeJavaServer{runtimes: want})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/servers/test/java-runtimes", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var runtimes []JavaRuntime
+	if err := json.NewDecoder(w.Body).Decode(&runtimes); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if !reflect.DeepEqual(want, runtimes) {
+		t.Fatalf("expected runtimes `%+v`, got `%+v`", want, runtimes)
+	}
+}
+
+// fakeConfigServer keeps the configuration of a Minecraft server in memory
+// and fails like a Minecraft server does.
+type fakeConfigServer struct {
+	MinecraftServerInterface
+
+	allowlist     *Allowlist
+	ops           *ServerOperatorList
+	bannedPlayers *BannedPlayerList
+	bannedIPs     *BannedIPList
+	args          *ServerArguments
+	properties    *ServerProperties
+	version       string
+	running       bool
+	saves         int
+	saveErr       error
+}
+
+func newFakeConfigServer() *fakeConfigServer {
+	return &fakeConfigServer{
+		allowlist:     &Allowlist{},
+		ops:           &ServerOperatorList{},
+		bannedPlayers: &BannedPlayerList{},
+		bannedIPs:     &BannedIPList{},
+		args:          &ServerArguments{},
+		properties:    &ServerProperties{},
+		version:       "1.20.6",
+	}
+}
+
+func fakePlayerName(p PlayerInfo) string {
+	if p.Name != nil {
+		return *p.Name
+	}
+	return p.Uuid.String()
+}
+
+func (f *fakeConfigServer) Allowlist() *Allowlist { return f.allowlist }
+
+func (f *fakeConfigServer) AllowPlayer(p *PlayerInfo) error {
+	for _, allowed := range *f.allowlist {
+		if fakePlayerName(allowed) == fakePlayerName(*p) {
+			return ErrPlayerAlreadyAllowed
+		}
+	}
+	*f.allowlist = append(*f.allowlist, *p)
+	return nil
+}
+
+func (f *fakeConfigServer) DisallowPlayer(p *PlayerInfo) error {
+	for i, allowed := range *f.allowlist {
+		if fakePlayerName(allowed) == fakePlayerName(*p) {
+			*f.allowlist = append((*f.allowlist)[:i], (*f.allowlist)[i+1:]...)
+			return nil
+		}
+	}
+	return ErrPlayerNotInAllowlist
+}
+
+func (f *fakeConfigServer) Ops() *ServerOperatorList { return f.ops }
+
+func (f *fakeConfigServer) Op(op *ServerOperator) error {
+	if op.Name == "nobody" {
+		return ErrPlayerNotFound
+	}
+	for _, existing := range *f.ops {
+		if existing.Name == op.Name {
+			return ErrAlreadyOp
+		}
+	}
+	*f.ops = append(*f.ops, *op)
+	return nil
+}
+
+func (f *fakeConfigServer) Deop(p *PlayerInfo) error {
+	for i, op := range *f.ops {
+		if op.Name == fakePlayerName(*p) {
+			*f.ops = append((*f.ops)[:i], (*f.ops)[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotInOps
+}
+
+func (f *fakeConfigServer) BannedPlayers() *BannedPlayerList { return f.bannedPlayers }
+
+func (f *fakeConfigServer) BanPlayer(p *BannedPlayer) error {
+	for _, banned := range *f.bannedPlayers {
+		if *banned.Name == *p.Name {
+			return ErrPlayerAlreadyBanned
+		}
+	}
+	*f.bannedPlayers = append(*f.bannedPlayers, *p)
+	return nil
+}
+
+func (f *fakeConfigServer) PardonPlayer(p *PlayerInfo) error {
+	for i, banned := range *f.bannedPlayers {
+		if *banned.Name == fakePlayerName(*p) {
+			*f.bannedPlayers = append((*f.bannedPlayers)[:i], (*f.bannedPlayers)[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotInBannedPlayers
+}
+
+func (f *fakeConfigServer) BannedIPs() *BannedIPList { return f.bannedIPs }
+
+func (f *fakeConfigServer) BanIP(ip *BannedIP) error {
+	if net.ParseIP(ip.Ip) == nil {
+		return ErrInvalidIP
+	}
+	for _, banned := range *f.bannedIPs {
+		if banned.Ip == ip.Ip {
+			return ErrIPAlreadyBanned
+		}
+	}
+	*f.bannedIPs = append(*f.bannedIPs, *ip)
+	return nil
+}
+
+func (f *fakeConfigServer) PardonIP(ip string) error {
+	if net.ParseIP(ip) == nil {
+		return ErrInvalidIP
+	}
+	for i, banned := range *f.bannedIPs {
+		if banned.Ip == ip {
+			*f.bannedIPs = append((*f.bannedIPs)[:i], (*f.bannedIPs)[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotInBannedIPs
+}
+
+func (f *fakeConfigServer) SetAllowlist(a *Allowlist)             { f.allowlist = a }
+func (f *fakeConfigServer) SetOperators(ops *ServerOperatorList)  { f.ops = ops }
+func (f *fakeConfigServer) SetBannedPlayers(b *BannedPlayerList)  { f.bannedPlayers = b }
+func (f *fakeConfigServer) SetBannedIPs(b *BannedIPList)          { f.bannedIPs = b }
+func (f *fakeConfigServer) SetProperties(props *ServerProperties) { f.properties = props }
+
+// UpdateProperties applies the MOTD to the running server and leaves the
+// server port pending a restart.
+func (f *fakeConfigServer) UpdateProperties(props *ServerProperties) (*PropertiesUpdate, error) {
+	result := &PropertiesUpdate{Changed: []string{}, Applied: []string{}, PendingRestart: []string{}, Ignored: []string{}}
+	if props.MOTD != nil {
+		f.properties.MOTD = props.MOTD
+		result.Changed = append(result.Changed, "MOTD")
+		if f.running {
+			result.Applied = append(result.Applied, "MOTD")
+		}
+	}
+	if props.ServerPort != nil {
+		f.properties.ServerPort = props.ServerPort
+		result.Changed = append(result.Changed, "serverPort")
+		if f.running {
+			result.PendingRestart = append(result.PendingRestart, "serverPort")
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeConfigServer) SetArgs(args *ServerArguments) error {
+	if args.MemoryMaxMB != nil && *args.MemoryMaxMB <= 0 {
+		return ErrInvalidMemory
+	}
+	f.args = args
+	return nil
+}
+
+func (f *fakeConfigServer) SetVersion(version string) error {
+	if version != "1.20.4" && version != "1.20.6" {
+		return ErrVersionUnsupported
+	}
+	f.version = version
+	return nil
+}
+
+func (f *fakeConfigServer) Start() error {
+	if f.running {
+		return ErrServerRunning
+	}
+	f.running = true
+	return nil
+}
+
+func (f *fakeConfigServer) Stop() error {
+	if !f.running {
+		return ErrServerNotRunning
+	}
+	f.running = false
+	return nil
+}
+
+func (f *fakeConfigServer) Restart() error {
+	f.running = true
+	return nil
+}
+
+func (f *fakeConfigServer) SaveConfigs() error {
+	if f.saveErr != nil {
+		return f.saveErr
+	}
+	f.saves++
+	return nil
+}
+
+func TestServerRoutes(t *testing.T) {
+	t.Parallel()
+
+	msi := newFakeConfigServer()
+	handler := newTestHandler(msi)
+
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+		wantCode   ErrorCode
+		wantSave   bool
+	}{
+		// allowlist
+		{name: "allow player", method: http.MethodPost, path: "/allowlist/add", body: `{"name": "steve"}`, wantStatus: http.StatusOK, wantSave: true},
+		{name: "allow allowed player", method: http.MethodPost, path: "/allowlist/add", body: `{"name": "steve"}`, wantStatus: http.StatusConflict, wantCode: AlreadyAllowed},
+		{name: "allow player without name", method: http.MethodPost, path: "/allowlist/add", body: `{}`, wantStatus: http.StatusBadRequest, wantCode: InvalidRequest},
+		{name: "allow player with invalid body", method: http.MethodPost, path: "/allowlist/add", body: `[`, wantStatus: http.StatusBadRequest, wantCode: InvalidRequest},
+		{name: "get allowlist", method: http.MethodGet, path: "/allowlist", wantStatus: http.StatusOK, wantBody: `[{"name":"steve"}]`},
+		{name: "disallow player", method: http.MethodPost, path: "/allowlist/remove", body: `{"name": "steve"}`, wantStatus: http.StatusOK, wantSave: true},
+		{name: "disallow missing player", method: http.MethodPost, path: "/allowlist/remove", body: `{"name": "steve"}`, wantStatus: http.StatusNotFound, wantCode: NotAllowed},
+		{name: "put allowlist", method: http.MethodPut, path: "/allowlist", body: `[{"name": "alex"}]`, wantStatus: http.StatusOK, wantSave: true},
+		{name: "put invalid allowlist", method: http.MethodPut, path: "/allowlist", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "get put allowlist", method: http.MethodGet, path: "/allowlist", wantStatus: http.StatusOK, wantBody: `[{"name":"alex"}]`},
+
+		// operators
+		{name: "op player", method: http.MethodPost, path: "/op", body: `{"name": "steve", "level": 4}`, wantStatus: http.StatusOK, wantSave: true},
+		{name: "op operator", method: http.MethodPost, path: "/op", body: `{"name": "steve", "level": 4}`, wantStatus: http.StatusConflict, wantCode: AlreadyAnOperator},
+		{name: "op missing player", method: http.MethodPost, path: "/op", body: `{"name": "nobody", "level": 4}`, wantStatus: http.StatusNotFound, wantCode: PlayerNotFound},
+		{name: "op player without name", method: http.MethodPost, path: "/op", body: `{"level": 4}`, wantStatus: http.StatusBadRequest},
+		{name: "get ops", method: http.MethodGet, path: "/ops", wantStatus: http.StatusOK},
+		{name: "deop player", method: http.MethodPost, path: "/deop", body: `{"name": "steve"}`, wantStatus: http.StatusOK, wantSave: true},
+		{name: "deop player who isn't an operator", method: http.MethodPost, path: "/deop", body: `{"name": "steve"}`, wantStatus: http.StatusNotFound, wantCode: NotAnOperator},
+		{name: "put ops", method: http.MethodPut, path: "/ops", body: `[]`, wantStatus: http.StatusOK, wantSave: true},
+		{name: "put invalid ops", method: http.MethodPut, path: "/ops", body: `"steve"`, wantStatus: http.StatusBadRequest},
+
+		// banned players
+		{name: "ban player", method: http.MethodPost, path: "/ban", body: `{"name": "griefer"}`, wantStatus: http.StatusOK, wantSave: true},
+		{name: "ban banned player", method: http.MethodPost, path: "/ban", body: `{"name": "griefer"}`, wantStatus: http.StatusConflict, wantCode: PlayerAlreadyBanned},
+		{name: "ban player without name", method: http.MethodPost, path: "/ban", body: `{"reason": "griefing"}`, wantStatus: http.StatusBadRequest},
+		{name: "get banned players", method: http.MethodGet, path: "/banned-players", wantStatus: http.StatusOK},
+		{name: "pardon player", method: http.MethodPost, path: "/pardon", body: `{"name": "griefer"}`, wantStatus: http.StatusOK, wantSave: true},
+		{name: "pardon player who isn't banned", method: http.MethodPost, path: "/pardon", body: `{"name": "griefer"}`, wantStatus: http.StatusNotFound, wantCode: PlayerNotBanned},
+		{name: "put banned players", method: http.MethodPut, path: "/banned-players", body: `[]`, wantStatus: http.StatusOK, wantSave: true},
+		{name: "put invalid banned players", method: http.MethodPut, path: "/banned-players", body: `[1]`, wantStatus: http.StatusBadRequest},
+
+		// banned IPs
+		{name: "ban IP", method: http.MethodPost, path: "/ban-ip", body: `{"ip": "192.0.2.1"}`, wantStatus: http.StatusOK, wantSave: true},
+		{name: "ban banned IP", method: http.MethodPost, path: "/ban-ip", body: `{"ip": "192.0.2.1"}`, wantStatus: http.StatusConflict, wantCode: IpAlreadyBanned},
+		{name: "ban invalid IP", method: http.MethodPost, path: "/ban-ip", body: `{"ip": "192.0.2"}`, wantStatus: http.StatusBadRequest, wantCode: InvalidIp},
+		{name: "get banned IPs", method: http.MethodGet, path: "/banned-ips", wantStatus: http.StatusOK},
+		{name: "pardon IP", method: http.MethodPost, path: "/pardon-ip", body: `{"ip": "192.0.2.1"}`, wantStatus: http.StatusOK, wantSave: true},
+		{name: "pardon IP that isn't banned", method: http.MethodPost, path: "/pardon-ip", body: `{"ip": "192.0.2.1"}`, wantStatus: http.StatusNotFound, wantCode: IpNotBanned},
+		{name: "put banned IPs", method: http.MethodPut, path: "/banned-ips", body: `[]`, wantStatus: http.StatusOK, wantSave: true},
+		{name: "put invalid banned IPs", method: http.MethodPut, path: "/banned-ips", body: `{`, wantStatus: http.StatusBadRequest},
+
+		// configuration
+		{name: "put args", method: http.MethodPut, path: "/args", body: `{"memoryMaxMB": 2048}`, wantStatus: http.StatusOK, wantSave: true},
+		{name: "put invalid args", method: http.MethodPut, path: "/args", body: `{"memoryMaxMB": -1}`, wantStatus: http.StatusBadRequest, wantCode: InvalidArguments},
+		{name: "put properties", method: http.MethodPut, path: "/properties", body: `{"MOTD": "hello"}`, wantStatus: http.StatusOK, wantBody: `{"applied":[],"changed":["MOTD"],"ignored":[],"pendingRestart":[],"restarted":false}`, wantSave: true},
+		{name: "put invalid properties", method: http.MethodPut, path: "/properties", body: `{"MOTD": 1}`, wantStatus: http.StatusBadRequest},
+		{name: "set version", method: http.MethodPost, path: "/set-version?version=1.20.4", wantStatus: http.StatusOK, wantSave: true},
+		{name: "set unsupported version", method: http.MethodPost, path: "/set-version?version=1.99", wantStatus: http.StatusBadRequest, wantCode: UnsupportedVersion},
+		{name: "set no version", method: http.MethodPost, path: "/set-version", wantStatus: http.StatusBadRequest, wantCode: InvalidRequest},
+
+		// process management
+		{name: "stop stopped server", method: http.MethodPost, path: "/stop", wantStatus: http.StatusConflict, wantCode: ServerNotRunning},
+		{name: "start server", method: http.MethodPost, path: "/start", wantStatus: http.StatusOK, wantBody: `"minecraft server started"`},
+		{name: "start running server", method: http.MethodPost, path: "/start", wantStatus: http.StatusConflict, wantCode: ServerRunning},
+		{name: "put live properties", method: http.MethodPut, path: "/properties?restart=true", body: `{"MOTD": "hello"}`, wantStatus: http.StatusOK, wantBody: `{"applied":["MOTD"],"changed":["MOTD"],"ignored":[],"pendingRestart":[],"restarted":false}`, wantSave: true},
+		{name: "put properties pending restart", method: http.MethodPut, path: "/properties", body: `{"serverPort": 25566}`, wantStatus: http.StatusOK, wantBody: `{"applied":[],"changed":["serverPort"],"ignored":[],"pendingRestart":["serverPort"],"restarted":false}`, wantSave: true},
+		{name: "put properties and restart", method: http.MethodPut, path: "/properties?restart=true", body: `{"serverPort": 25567}`, wantStatus: http.StatusOK, wantBody: `{"applied":[],"changed":["serverPort"],"ignored":[],"pendingRestart":["serverPort"],"restarted":true}`, wantSave: true},
+		{name: "put properties with invalid restart", method: http.MethodPut, path: "/properties?restart=maybe", body: `{}`, wantStatus: http.StatusBadRequest, wantCode: InvalidRequest},
+		{name: "restart server", method: http.MethodPost, path: "/restart", wantStatus: http.StatusOK},
+		{name: "stop server", method: http.MethodPost, path: "/stop", wantStatus: http.StatusOK},
+	}
+
+	// the test cases build on each other, so they aren't run in parallel
+	for _, tc := range testCases {
+		saves := msi.saves
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(tc.method, "/servers/test"+tc.path, strings.NewReader(tc.body)))
+
+		if w.Code != tc.wantStatus {
+			t.Errorf("%s: expected status %d, got %d", tc.name, tc.wantStatus, w.Code)
+		}
+		if body := strings.TrimSpace(w.Body.String()); len(tc.wantBody) > 0 && body != tc.wantBody {
+			t.Errorf("%s: expected body `%s`, got `%s`", tc.name, tc.wantBody, body)
+		}
+		if code := errorCode(w.Body.Bytes()); len(tc.wantCode) > 0 && code != tc.wantCode {
+			t.Errorf("%s: expected error code `%s`, got `%s`", tc.name, tc.wantCode, code)
+		}
+		if saved := msi.saves > saves; saved != tc.wantSave {
+			t.Errorf("%s: expected saved to be %v, got %v", tc.name, tc.wantSave, saved)
+		}
+	}
+
+	if msi.version != "1.20.4" || *msi.args.MemoryMaxMB != 2048 || *msi.properties.MOTD != "hello" {
+		t.Errorf("expected the configuration to be updated, got version `%s`, args `%+v` and properties `%+v`", msi.version, *msi.args, *msi.properties)
+	}
+}
+
+func TestServerRoutesErrors(t *testing.T) {
+	t.Parallel()
+
+	msi := newFakeConfigServer()
+	msi.ops = nil
+	msi.saveErr = errors.New("disk full")
+	handler := newTestHandler(msi)
+
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantCode   ErrorCode
+	}{
+		{name: "unloaded config", method: http.MethodGet, path: "/servers/test/ops", wantStatus: http.StatusInternalServerError, wantCode: ConfigNotLoaded},
+		{name: "failed save", method: http.MethodPost, path: "/servers/test/allowlist/add", body: `{"name": "steve"}`, wantStatus: http.StatusInternalServerError, wantCode: InternalError},
+		{name: "missing server", method: http.MethodGet, path: "/servers/lobby/allowlist", wantStatus: http.StatusNotFound, wantCode: ServerNotFound},
+	}
+
+	for _, tc := range testCases {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body)))
+
+		if w.Code != tc.wantStatus {
+			t.Errorf("%s: expected status %d, got %d", tc.name, tc.wantStatus, w.Code)
+		}
+		var e Error
+		if err := json.NewDecoder(w.Body).Decode(&e); err != nil {
+			t.Fatalf("%s: expected no error, got `%v`", tc.name, err)
+		}
+		if e.Code != tc.wantCode {
+			t.Errorf("%s: expected error code `%s`, got `%s`", tc.name, tc.wantCode, e.Code)
+		}
+		// internal errors don't leak their cause
+		if e.Code == InternalError && strings.Contains(e.Message, "disk full") {
+			t.Errorf("%s: expected the cause to be hidden, got message `%s`", tc.name, e.Message)
+		}
+	}
+}
+
+// errorCode returns the code of the Error in body, or an empty code if body
+// isn't one.
+func errorCode(body []byte) ErrorCode {
+	var e Error
+	if err := json.Unmarshal(body, &e); err != nil {
+		return ""
+	}
+
+	return e.Code
+}
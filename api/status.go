@@ -0,0 +1,32 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// GetStatus implements ServerInterface.
+func (s *ServerController) GetStatus(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, msi.Status())
+}
+
+// GetQuery implements ServerInterface.
+func (s *ServerController) GetQuery(w http.ResponseWriter, r *http.Request, id string) {
+	msi, ok := s.server(w, r, id)
+	if !ok {
+		return
+	}
+
+	status, err := msi.Query()
+	if err != nil {
+		writeError(w, r, fmt.Errorf("%w: %w", ErrQueryUnavailable, err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, status)
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type fakeStatusServer struct {
+	MinecraftServerInterface
+
+	status   *ServerStatus
+	query    *QueryStatus
+	queryErr error
+}
+
+func (f *fakeStatusServer) Status() *ServerStatus { return f.status }
+
+func (f *fakeStatusServer) Query() (*QueryStatus, error) { return f.query, f.queryErr }
+
+func TestGetStatus(t *testing.T) {
+	t.Parallel()
+
+	pid, motd := 1234, "A Minecraft Server"
+	msi := &fakeStatusServer{
+		status: &ServerStatus{
+			State:   Running,
+			Pid:     &pid,
+			Online:  true,
+			Motd:    &motd,
+			Version: &ServerStatusVersion{Name: "1.20.6", Protocol: 766},
+			Players: &ServerStatusPlayers{Max: 20, Online: 0, Sample: []PlayerInfo{}},
+		},
+	}
+	handler := newTestHandler(msi)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/servers/test/status", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var got ServerStatus
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if !reflect.DeepEqual(*msi.status, got) {
+		t.Fatalf("expected status `%+v`, got `%+v`", *msi.status, got)
+	}
+}
+
+func TestGetQuery(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		msi        *fakeStatusServer
+		wantStatus int
+	}{
+		{
+			name: "full stat",
+			msi: &fakeStatusServer{
+				query: &QueryStatus{
+					Motd:       "A Minecraft Server",
+					GameType:   "SMP",
+					Map:        "world",
+					NumPlayers: 1,
+					MaxPlayers: 20,
+					HostPort:   25565,
+					HostIp:     "127.0.0.1",
+					Plugins:    []string{},
+					Players:    []string{"player1"},
+				},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "query failed",
+			msi:        &fakeStatusServer{queryErr: errors.New("query is not enabled in the server properties")},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := newTestHandler(tc.msi)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/servers/test/query", nil))
+
+			if w.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d", tc.wantStatus, w.Code)
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got QueryStatus
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("expected no error, got `%v`", err)
+			}
+			if !reflect.DeepEqual(*tc.msi.query, got) {
+				t.Fatalf("expected full stat `%+v`, got `%+v`", *tc.msi.query, got)
+			}
+		})
+	}
+}
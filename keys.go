@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+var errKeysUsage = errors.New("usage: go-mcsc [flags] keys create -name <name> [-scopes <scopes>] | keys list | keys revoke <id>")
+
+// runKeysCommand runs the `keys` command, which manages the API keys in store.
+func runKeysCommand(store *api.KeyStore, args []string) error {
+	if len(args) == 0 {
+		return errKeysUsage
+	}
+
+	switch args[0] {
+	case "create":
+		flags := flag.NewFlagSet("keys create", flag.ContinueOnError)
+		name := flags.String("name", "", "name of the API key, to tell keys apart")
+		scopes := flags.String(
+			"scopes",
+			api.ScopeRead,
+			"comma separated scopes of the API key, out of "+strings.Join(api.Scopes, ", "),
+		)
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		key, apiKey, err := store.Create(*name, strings.Split(*scopes, ","))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("created API key %s, it won't be shown again:\n%s\n", apiKey.ID, key)
+	case "list":
+		keys, err := store.Keys()
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tSCOPES\tCREATED")
+		for _, key := range keys {
+			fmt.Fprintf(
+				w,
+				"%s\t%s\t%s\t%s\n",
+				key.ID,
+				key.Name,
+				strings.Join(key.Scopes, ","),
+				key.CreatedAt.Local().Format(time.DateTime),
+			)
+		}
+		return w.Flush()
+	case "revoke":
+		if len(args) != 2 {
+			return errKeysUsage
+		}
+		if err := store.Revoke(args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("revoked API key %s\n", args[1])
+	default:
+		return errKeysUsage
+	}
+
+	return nil
+}
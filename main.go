@@ -1,46 +1,98 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"log"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 
-	"github.com/go-chi/chi/v5"
 	"github.com/raian621/go-mcsc/api"
 	"github.com/raian621/go-mcsc/minecraft"
 )
 
+const (
+	legacyServerDir = "server-data"
+	legacyServerID  = "default"
+)
+
 func main() {
 	host := flag.String("host", "0.0.0.0", "host to serve requests from")
 	port := flag.String("port", "5000", "port to listen on")
+	dataDir := flag.String("data-dir", "servers", "directory the data directories of the minecraft servers are kept in")
+	manifestURL := flag.String(
+		"manifest-url",
+		minecraft.DefaultManifestBaseURL,
+		"base URL of the Minecraft version manifest, or of a mirror of it",
+	)
+	javaDirs := flag.String(
+		"java-dirs",
+		strings.Join(minecraft.DefaultJavaDirs, string(os.PathListSeparator)),
+		"directories to search for Java runtimes, separated like the PATH",
+	)
+	refreshVersions := flag.Bool("refresh-versions", false, "refresh the available versions from the version manifest on startup")
+	keysFile := flag.String("keys-file", "api-keys.json", "file the API keys are kept in")
 	flag.Parse()
 
-	log.Println("creating server folder...")
-	if err := minecraft.CreateServerFolder("server-data"); err != nil {
+	keys := api.NewKeyStore(*keysFile)
+	if flag.Arg(0) == "keys" {
+		if err := runKeysCommand(keys, flag.Args()[1:]); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+	if err := keys.Load(); err != nil {
 		log.Fatalln(err)
 	}
+	if apiKeys, err := keys.Keys(); err == nil && len(apiKeys) == 0 {
+		log.Println("there are no API keys yet, create one with `go-mcsc keys create -name <name>`")
+	}
+
+	registry := minecraft.NewServerRegistry(*dataDir, "data/server-download-links.json")
+	registry.SetManifestBaseURL(*manifestURL)
+	registry.SetJavaDirs(filepath.SplitList(*javaDirs))
+	for _, runtime := range registry.JavaRuntimes() {
+		log.Printf("found java %s at %s", runtime.Version, runtime.Path)
+	}
 
-	mcServer := minecraft.NewJavaMinecraftServer(&minecraft.MinecraftServerConfigFilepaths{
-		Allowlist:          "server-data/whitelist.json",
-		Args:               "server-data/args.json",
-		BannedIPs:          "server-data/banned-ips.json",
-		BannedPlayers:      "server-data/banned-players.json",
-		Config:             "server-data/config.json",
-		Ops:                "server-data/ops.json",
-		Properties:         "server-data/properties.json",
-		PropertiesTemplate: "templates/server.properties.tmpl",
-		Versions:           "data/server-download-links.json",
-	})
-
-	log.Println("loading server config...")
-	if err := mcServer.LoadConfigs(); err != nil {
+	log.Println("loading minecraft servers...")
+	if err := registry.Load(); err != nil {
 		log.Fatalln(err)
 	}
 
-	server := api.NewServerController(mcServer)
-	r := chi.NewMux()
-	h := api.HandlerFromMux(server, r)
+	// the single server data directory of earlier versions of the server
+	// controller becomes the default server
+	if _, err := os.Stat(legacyServerDir); err == nil && len(registry.Servers()) == 0 {
+		log.Printf("registering %s as minecraft server %s", legacyServerDir, legacyServerID)
+		if err := registry.Adopt(legacyServerID, legacyServerDir); err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	if *refreshVersions {
+		if _, err := registry.RefreshVersions(context.Background()); err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	for _, instance := range registry.Servers() {
+		mcServer, err := registry.Server(instance.Id)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		// the minecraft server may have outlived the previous run of the
+		// server controller
+		if err := mcServer.AttachRCON(); err != nil && !errors.Is(err, minecraft.ErrRCONDisabled) {
+			log.Printf("not attaching to minecraft server %s over RCON: %v", instance.Id, err)
+		}
+	}
+
+	server := api.NewServerController(registry)
+	h := api.NewRouter(server, api.APIKeyAuth(keys))
 
 	addr := net.JoinHostPort(*host, *port)
 	s := &http.Server{
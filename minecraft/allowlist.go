@@ -11,7 +11,8 @@ import (
 )
 
 var (
-	ErrPlayerNotInAllowlist = errors.New("player is not in server allowlist")
+	ErrPlayerAlreadyAllowed = api.ErrPlayerAlreadyAllowed
+	ErrPlayerNotInAllowlist = api.ErrPlayerNotInAllowlist
 )
 
 // Adds a player to the Minecraft server's allowlist.
@@ -22,9 +23,10 @@ var (
 // is called.
 //
 // If the Minecraft server process is currently running, the command `/whitelist
-// <playername>` is sent to the Minecraft server console and the player will be
-// added to the Minecraft server's allowlist and the in-memory allowlist upon
-// success.
+// add <playername>` is sent to the Minecraft server console and the player is
+// added to the in-memory allowlist once the Minecraft server has added them to
+// its allowlist. If the Minecraft server rejects the command, the error it
+// responded with is returned.
 func (m *JavaMinecraftServer) AllowPlayer(p *api.PlayerInfo) error {
 	m.Lock()
 	defer m.Unlock()
@@ -33,10 +35,26 @@ func (m *JavaMinecraftServer) AllowPlayer(p *api.PlayerInfo) error {
 		return ErrNilConfig
 	}
 
-	if m.console != nil {
-		if err := m.console.SendCommand(fmt.Sprintf("/whitelist add %s", *p.Name)); err != nil {
+	if m.console.Attached() {
+		err := m.execute(fmt.Sprintf("/whitelist add %s", playerName(*p)))
+		if err != nil && !errors.Is(err, ErrPlayerAlreadyAllowed) {
 			return err
 		}
+		// the list may have been replaced while the server was unlocked
+		if m.allowlist == nil {
+			return ErrNilConfig
+		}
+
+		// the player is in the Minecraft server's allowlist either way
+		if idx := m.allowlistIndex(p); idx == -1 {
+			*m.allowlist = append(*m.allowlist, *p)
+		}
+
+		return err
+	}
+
+	if idx := m.allowlistIndex(p); idx != -1 {
+		return ErrPlayerAlreadyAllowed
 	}
 
 	*m.allowlist = append(*m.allowlist, *p)
@@ -66,9 +84,10 @@ func (m *JavaMinecraftServer) Allowlist() *api.Allowlist {
 // SaveAllowList method is called.
 //
 // If the Minecraft server process is currently running, the command `/whitelist
-// remove <playername>` is sent to the Minecraft server console and the player will be
-// added to the Minecraft server's allowlist and the in-memory allowlist upon
-// success.func (m *JavaMinecraftServer) DisallowPlayer(p api.PlayerInfo) error {
+// remove <playername>` is sent to the Minecraft server console and the player
+// is removed from the in-memory allowlist once the Minecraft server has removed
+// them from its allowlist. If the Minecraft server rejects the command, the
+// error it responded with is returned.
 func (m *JavaMinecraftServer) DisallowPlayer(p *api.PlayerInfo) error {
 	m.Lock()
 	defer m.Unlock()
@@ -77,23 +96,25 @@ func (m *JavaMinecraftServer) DisallowPlayer(p *api.PlayerInfo) error {
 		return ErrNilConfig
 	}
 
-	if len(*m.allowlist) == 0 {
-		return ErrPlayerNotInAllowlist
-	}
-
-	if m.console != nil {
-		if err := m.console.SendCommand(fmt.Sprintf("/whitelist remove %s", *p.Name)); err != nil {
+	if m.console.Attached() {
+		err := m.execute(fmt.Sprintf("/whitelist remove %s", playerName(*p)))
+		if err != nil && !errors.Is(err, ErrPlayerNotInAllowlist) {
 			return err
 		}
-	}
+		// the list may have been replaced while the server was unlocked
+		if m.allowlist == nil {
+			return ErrNilConfig
+		}
 
-	idx := -1
-	for i, player := range *m.allowlist {
-		if *player.Name == *p.Name && *player.Uuid == *p.Uuid {
-			idx = i
-			break
+		// the player isn't in the Minecraft server's allowlist either way
+		if idx := m.allowlistIndex(p); idx != -1 {
+			*m.allowlist = append((*m.allowlist)[:idx], (*m.allowlist)[idx+1:]...)
 		}
+
+		return err
 	}
+
+	idx := m.allowlistIndex(p)
 	if idx == -1 {
 		return ErrPlayerNotInAllowlist
 	}
@@ -103,6 +124,18 @@ func (m *JavaMinecraftServer) DisallowPlayer(p *api.PlayerInfo) error {
 	return nil
 }
 
+// allowlistIndex returns the index of p in the in-memory allowlist, or -1 if p
+// isn't in it. m must be locked.
+func (m *JavaMinecraftServer) allowlistIndex(p *api.PlayerInfo) int {
+	for i, player := range *m.allowlist {
+		if samePlayer(player, *p) {
+			return i
+		}
+	}
+
+	return -1
+}
+
 func (m *JavaMinecraftServer) CreateAllowlist() {
 	m.Lock()
 	defer m.Unlock()
@@ -136,7 +169,7 @@ func (m *JavaMinecraftServer) SetAllowlist(a *api.Allowlist) {
 	m.Lock()
 	defer m.Unlock()
 
-	if m.console != nil {
+	if m.console.Attached() {
 		playerlist := make(map[string]bool, 0)
 
 		for _, player := range *m.allowlist {
@@ -4,11 +4,55 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"unicode"
 
 	"github.com/raian621/go-mcsc/api"
 )
 
+var (
+	ErrInvalidMemory   = api.ErrInvalidMemory
+	ErrInvalidArgument = api.ErrInvalidArgument
+	ErrUnknownPreset   = api.ErrUnknownPreset
+)
+
+// aikarFlags are Aikar's G1GC flags, see https://mcflags.emc.gs.
+var aikarFlags = []string{
+	"-XX:+UseG1GC",
+	"-XX:+ParallelRefProcEnabled",
+	"-XX:MaxGCPauseMillis=200",
+	"-XX:+UnlockExperimentalVMOptions",
+	"-XX:+DisableExplicitGC",
+	"-XX:+AlwaysPreTouch",
+	"-XX:G1HeapWastePercent=5",
+	"-XX:G1MixedGCCountTarget=4",
+	"-XX:G1MixedGCLiveThresholdPercent=90",
+	"-XX:G1RSetUpdatingPauseTimePercent=5",
+	"-XX:SurvivorRatio=32",
+	"-XX:+PerfDisableSharedMem",
+	"-XX:MaxTenuringThreshold=1",
+	"-Dusing.aikars.flags=https://mcflags.emc.gs",
+	"-Daikars.new.flags=true",
+}
+
+// forbiddenJVMFlags are the JVM flags that would replace the server jar or its
+// main class, or run code other than the Minecraft server's. The ones ending
+// with `:` or `=` are prefixes of flags with a value.
+var forbiddenJVMFlags = []string{
+	"-jar",
+	"-m", "--module", "--module=",
+	"-cp", "-classpath", "--class-path", "--class-path=",
+	"-javaagent:", "-agentlib:", "-agentpath:",
+	"-XX:OnError=", "-XX:OnOutOfMemoryError=",
+}
+
+// aikarLargeHeapMB is the heap size from which Aikar recommends giving the
+// young generation more room.
+const aikarLargeHeapMB = 12 * 1024
+
 func NewServerArgs() *api.ServerArguments {
 	return &api.ServerArguments{
 		MemoryStartGB: ref(1),
@@ -16,15 +60,182 @@ func NewServerArgs() *api.ServerArguments {
 	}
 }
 
+// memoryMB returns the heap size set in MB or, failing that, in GB, in MB.
+func memoryMB(mb, gb *int) int {
+	if mb != nil {
+		return *mb
+	}
+	if gb != nil {
+		return *gb * 1024
+	}
+
+	return 0
+}
+
+// memoryFlag formats a heap size flag in whichever unit it was set in.
+func memoryFlag(flag string, mb, gb *int) string {
+	if mb != nil {
+		return fmt.Sprintf("%s%dM", flag, *mb)
+	}
+
+	return fmt.Sprintf("%s%dG", flag, *gb)
+}
+
+// presetFlags returns the JVM flags of preset, tuned for a heap of maxMB.
+func presetFlags(preset *api.JVMPreset, maxMB int) []string {
+	if preset == nil {
+		return nil
+	}
+
+	switch *preset {
+	case api.Aikar:
+		if maxMB >= aikarLargeHeapMB {
+			return append(slices.Clone(aikarFlags),
+				"-XX:G1NewSizePercent=40",
+				"-XX:G1MaxNewSizePercent=50",
+				"-XX:G1HeapRegionSize=16M",
+				"-XX:G1ReservePercent=15",
+				"-XX:InitiatingHeapOccupancyPercent=20",
+			)
+		}
+		return append(slices.Clone(aikarFlags),
+			"-XX:G1NewSizePercent=30",
+			"-XX:G1MaxNewSizePercent=40",
+			"-XX:G1HeapRegionSize=8M",
+			"-XX:G1ReservePercent=20",
+			"-XX:InitiatingHeapOccupancyPercent=15",
+		)
+	}
+
+	return nil
+}
+
+// ValidateArgs returns an api.ValidationError listing the fields of args that
+// can't be used to run the Minecraft server. Since every argument is passed to
+// the process as is, values that start with a dash are rejected where they'd
+// be read as another flag.
+func ValidateArgs(args *api.ServerArguments) error {
+	if args == nil {
+		return ErrNilConfig
+	}
+
+	var errs fieldErrors
+
+	startMB := memoryMB(args.MemoryStartMB, args.MemoryStartGB)
+	maxMB := memoryMB(args.MemoryMaxMB, args.MemoryMaxGB)
+	if startMB <= 0 {
+		errs.add(ErrInvalidMemory, "memoryStartMB", "must be positive")
+	}
+	if maxMB <= 0 {
+		errs.add(ErrInvalidMemory, "memoryMaxMB", "must be positive")
+	}
+	if startMB > 0 && maxMB > 0 && startMB > maxMB {
+		errs.add(
+			ErrInvalidMemory, "memoryStartMB",
+			"(%d MB) must not be larger than the maximum heap size (%d MB)", startMB, maxMB,
+		)
+	}
+
+	if args.Preset != nil {
+		switch *args.Preset {
+		case api.Aikar:
+		default:
+			errs.add(ErrUnknownPreset, "preset", "`%s` is not a known preset", *args.Preset)
+		}
+	}
+
+	for _, value := range []struct {
+		name  string
+		value *string
+	}{
+		{"serverID", args.ServerID},
+		{"singlePlayer", args.SinglePlayer},
+		{"universe", args.Universe},
+		{"world", args.World},
+	} {
+		if value.value == nil {
+			continue
+		}
+		if strings.HasPrefix(*value.value, "-") || hasControlChars(*value.value) {
+			errs.add(ErrInvalidArgument, value.name, "`%s` must not start with a dash or contain control characters", *value.value)
+		}
+	}
+
+	if args.Port != nil && (*args.Port < 1 || *args.Port > 65535) {
+		errs.add(ErrInvalidArgument, "port", "must be between 1 and 65535")
+	}
+
+	if args.JvmFlags != nil {
+		for _, flag := range *args.JvmFlags {
+			switch {
+			// anything else would be taken as the main class
+			case !strings.HasPrefix(flag, "-") || hasControlChars(flag):
+				errs.add(ErrInvalidArgument, "jvmFlags", "`%s` must be a flag", flag)
+			case isForbiddenJVMFlag(flag):
+				errs.add(ErrInvalidArgument, "jvmFlags", "`%s` must not change the code the JVM runs", flag)
+			}
+		}
+	}
+	if args.ServerArgs != nil {
+		for _, arg := range *args.ServerArgs {
+			if hasControlChars(arg) {
+				errs.add(ErrInvalidArgument, "serverArgs", "`%s` must not contain control characters", arg)
+			}
+		}
+	}
+	if args.Env != nil {
+		for name := range *args.Env {
+			if len(name) == 0 || strings.ContainsAny(name, "=\x00") {
+				errs.add(ErrInvalidArgument, "env", "`%s` is not a valid environment variable name", name)
+			}
+		}
+	}
+
+	return errs.err()
+}
+
+func isForbiddenJVMFlag(flag string) bool {
+	for _, forbidden := range forbiddenJVMFlags {
+		if flag == forbidden {
+			return true
+		}
+		if strings.HasSuffix(forbidden, ":") || strings.HasSuffix(forbidden, "=") {
+			if strings.HasPrefix(flag, forbidden) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func hasControlChars(s string) bool {
+	return strings.ContainsFunc(s, unicode.IsControl)
+}
+
+// BuildStringArgs returns the command line the Minecraft server is run with.
+// The `java` on the PATH is run unless args has a Java path. args should be
+// validated with ValidateArgs first.
 func BuildStringArgs(version string, args *api.ServerArguments) []string {
-	strArgs := []string{"java"}
+	java := "java"
+	if args.JavaPath != nil && len(*args.JavaPath) > 0 {
+		java = *args.JavaPath
+	}
+	strArgs := []string{java}
 
 	strArgs = append(
 		strArgs,
-		fmt.Sprintf("-Xms%dG", *args.MemoryStartGB),
-		fmt.Sprintf("-Xmx%dG", *args.MemoryMaxGB),
+		memoryFlag("-Xms", args.MemoryStartMB, args.MemoryStartGB),
+		memoryFlag("-Xmx", args.MemoryMaxMB, args.MemoryMaxGB),
+	)
+	strArgs = append(strArgs, presetFlags(args.Preset, memoryMB(args.MemoryMaxMB, args.MemoryMaxGB))...)
+	if args.JvmFlags != nil {
+		strArgs = append(strArgs, *args.JvmFlags...)
+	}
+	strArgs = append(
+		strArgs,
 		"-jar",
-		fmt.Sprintf("server-%s.jar", version),
+		JarName(version),
 		"--nogui",
 	)
 
@@ -59,9 +270,34 @@ func BuildStringArgs(version string, args *api.ServerArguments) []string {
 		strArgs = append(strArgs, "--port", strconv.FormatInt(int64(*args.Port), 10))
 	}
 
+	if args.ServerArgs != nil {
+		strArgs = append(strArgs, *args.ServerArgs...)
+	}
+
 	return strArgs
 }
 
+// BuildEnv returns the environment the Minecraft server is run with: the
+// environment of the server controller with the variables in args added.
+func BuildEnv(args *api.ServerArguments) []string {
+	env := os.Environ()
+	if args.Env == nil {
+		return env
+	}
+
+	names := make([]string, 0, len(*args.Env))
+	for name := range *args.Env {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		env = append(env, name+"="+(*args.Env)[name])
+	}
+
+	return env
+}
+
 func (m *JavaMinecraftServer) Args() *api.ServerArguments {
 	m.Lock()
 	defer m.Unlock()
@@ -90,7 +326,11 @@ func (m *JavaMinecraftServer) LoadArgs(file io.Reader) error {
 		return ErrNilConfig
 	}
 
-	return json.NewDecoder(file).Decode(m.args)
+	if err := json.NewDecoder(file).Decode(m.args); err != nil {
+		return err
+	}
+
+	return ValidateArgs(m.args)
 }
 
 func (m *JavaMinecraftServer) SaveArgs(file io.Writer) error {
@@ -104,9 +344,41 @@ func (m *JavaMinecraftServer) SaveArgs(file io.Writer) error {
 	return json.NewEncoder(file).Encode(m.args)
 }
 
-func (m *JavaMinecraftServer) SetArgs(args *api.ServerArguments) {
+// SetArgs implements api.MinecraftServerInterface.
+func (m *JavaMinecraftServer) SetArgs(args *api.ServerArguments) error {
+	if err := ValidateArgs(args); err != nil {
+		return err
+	}
+
+	m.Lock()
+	registry := m.java
+	current := m.args
+	m.Unlock()
+
+	// the Java path is run as is, so it has to be one of the Java runtimes
+	// found on the host, unless it's the one set in the arguments file already
+	if java := args.JavaPath; java != nil && len(*java) > 0 &&
+		(current == nil || current.JavaPath == nil || *current.JavaPath != *java) {
+		if _, ok := registry.Lookup(*java); !ok {
+			return api.ValidationError{{
+				Err:    ErrInvalidArgument,
+				Field:  "javaPath",
+				Reason: fmt.Sprintf("`%s` must be the `java` executable of a Java runtime found on the host", *java),
+			}}
+		}
+	}
+
 	m.Lock()
 	defer m.Unlock()
 
+	if m.properties != nil && rconOnServerPort(m.properties, args) {
+		return api.ValidationError{{
+			Err:    ErrInvalidArgument,
+			Field:  "port",
+			Reason: fmt.Sprintf("must differ from RCONPort (%d)", *m.properties.RCONPort),
+		}}
+	}
 	m.args = args
+
+	return nil
 }
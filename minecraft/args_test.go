@@ -2,7 +2,9 @@ package minecraft
 
 import (
 	"bytes"
+	"errors"
 	"reflect"
+	"slices"
 	"testing"
 
 	"github.com/raian621/go-mcsc/api"
@@ -89,6 +91,64 @@ func TestBuildArgs(t *testing.T) {
 				"1000",
 			},
 		},
+		{
+			name:    "java path",
+			version: "1.7.10",
+			args: api.ServerArguments{
+				JavaPath:      ref("/usr/lib/jvm/java-8-openjdk/bin/java"),
+				MemoryStartGB: ref(1),
+				MemoryMaxGB:   ref(2),
+			},
+			wantArgs: []string{
+				"/usr/lib/jvm/java-8-openjdk/bin/java",
+				"-Xms1G",
+				"-Xmx2G",
+				"-jar",
+				"server-1.7.10.jar",
+				"--nogui",
+			},
+		},
+		{
+			name:    "memory in MB",
+			version: "1.20.6",
+			args: api.ServerArguments{
+				MemoryStartMB: ref(512),
+				MemoryMaxMB:   ref(1536),
+				MemoryStartGB: ref(1),
+				MemoryMaxGB:   ref(2),
+			},
+			wantArgs: []string{
+				"java",
+				"-Xms512M",
+				"-Xmx1536M",
+				"-jar",
+				"server-1.20.6.jar",
+				"--nogui",
+			},
+		},
+		{
+			name:    "JVM flags and server args",
+			version: "1.20.6",
+			args: api.ServerArguments{
+				MemoryStartGB: ref(1),
+				MemoryMaxGB:   ref(2),
+				JvmFlags:      &[]string{"-XX:+UseZGC"},
+				ServerArgs:    &[]string{"--jfrProfile"},
+				Port:          ref(25566),
+			},
+			wantArgs: []string{
+				"java",
+				"-Xms1G",
+				"-Xmx2G",
+				"-XX:+UseZGC",
+				"-jar",
+				"server-1.20.6.jar",
+				"--nogui",
+				"--port",
+				"25566",
+				"--jfrProfile",
+			},
+		},
 		{
 			name:    "all args off",
 			version: "1.20.6",
@@ -217,9 +277,223 @@ func TestSetArgs(t *testing.T) {
 	server := JavaMinecraftServer{}
 	args := NewServerArgs()
 
-	server.SetArgs(args)
+	if err := server.SetArgs(args); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
 
 	if args != server.args {
 		t.Fatal("args and server args should have the same memory address")
 	}
+
+	invalid := &api.ServerArguments{MemoryStartGB: ref(4), MemoryMaxGB: ref(2)}
+	if err := server.SetArgs(invalid); !errors.Is(err, ErrInvalidMemory) {
+		t.Fatalf("expected error `%v`, got `%v`", ErrInvalidMemory, err)
+	}
+	if args != server.args {
+		t.Fatal("invalid args should not replace the server args")
+	}
+
+	// the port argument overrides the server port, so it can't be the RCON port
+	server.properties = &api.ServerProperties{EnableRCON: ref(true), RCONPort: ref(25575)}
+	conflicting := NewServerArgs()
+	conflicting.Port = ref(25575)
+	if err := server.SetArgs(conflicting); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected error `%v`, got `%v`", ErrInvalidArgument, err)
+	}
+	conflicting.Port = ref(25566)
+	if err := server.SetArgs(conflicting); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+}
+
+func TestValidateArgs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		args    *api.ServerArguments
+		wantErr error
+	}{
+		{
+			name: "default args",
+			args: NewServerArgs(),
+		},
+		{
+			name:    "nil args",
+			args:    nil,
+			wantErr: ErrNilConfig,
+		},
+		{
+			name: "all args",
+			args: &api.ServerArguments{
+				MemoryStartMB: ref(512),
+				MemoryMaxGB:   ref(4),
+				Preset:        ref(api.Aikar),
+				JvmFlags:      &[]string{"-XX:+UseZGC", "-Dlog4j2.formatMsgNoLookups=true"},
+				ServerArgs:    &[]string{"--jfrProfile"},
+				Env:           &map[string]string{"TZ": "UTC"},
+				ServerID:      ref("server_id"),
+				World:         ref("my world"),
+				Port:          ref(25565),
+			},
+		},
+		{
+			name:    "initial heap larger than maximum heap",
+			args:    &api.ServerArguments{MemoryStartGB: ref(4), MemoryMaxGB: ref(2)},
+			wantErr: ErrInvalidMemory,
+		},
+		{
+			name:    "initial heap in MB larger than maximum heap in GB",
+			args:    &api.ServerArguments{MemoryStartMB: ref(3072), MemoryMaxGB: ref(2)},
+			wantErr: ErrInvalidMemory,
+		},
+		{
+			name:    "no maximum heap",
+			args:    &api.ServerArguments{MemoryStartGB: ref(1)},
+			wantErr: ErrInvalidMemory,
+		},
+		{
+			name:    "zero heap",
+			args:    &api.ServerArguments{MemoryStartMB: ref(0), MemoryMaxMB: ref(0)},
+			wantErr: ErrInvalidMemory,
+		},
+		{
+			name:    "unknown preset",
+			args:    &api.ServerArguments{MemoryStartGB: ref(1), MemoryMaxGB: ref(2), Preset: ref(api.JVMPreset("fast"))},
+			wantErr: ErrUnknownPreset,
+		},
+		{
+			name:    "flag in server ID",
+			args:    &api.ServerArguments{MemoryStartGB: ref(1), MemoryMaxGB: ref(2), ServerID: ref("--eraseCache")},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "flag in world",
+			args:    &api.ServerArguments{MemoryStartGB: ref(1), MemoryMaxGB: ref(2), World: ref("-forceUpgrade")},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "newline in universe",
+			args:    &api.ServerArguments{MemoryStartGB: ref(1), MemoryMaxGB: ref(2), Universe: ref("worlds\n--demo")},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "port out of range",
+			args:    &api.ServerArguments{MemoryStartGB: ref(1), MemoryMaxGB: ref(2), Port: ref(70000)},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "zero port",
+			args:    &api.ServerArguments{MemoryStartGB: ref(1), MemoryMaxGB: ref(2), Port: ref(0)},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name: "highest port",
+			args: &api.ServerArguments{MemoryStartGB: ref(1), MemoryMaxGB: ref(2), Port: ref(65535)},
+		},
+		{
+			name:    "JVM flag that isn't a flag",
+			args:    &api.ServerArguments{MemoryStartGB: ref(1), MemoryMaxGB: ref(2), JvmFlags: &[]string{"Main"}},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "JVM flag replacing the jar",
+			args:    &api.ServerArguments{MemoryStartGB: ref(1), MemoryMaxGB: ref(2), JvmFlags: &[]string{"-jar", "other.jar"}},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "JVM flag replacing the class path",
+			args:    &api.ServerArguments{MemoryStartGB: ref(1), MemoryMaxGB: ref(2), JvmFlags: &[]string{"--class-path=other.jar"}},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "JVM flag running a module",
+			args:    &api.ServerArguments{MemoryStartGB: ref(1), MemoryMaxGB: ref(2), JvmFlags: &[]string{"-m", "other/Main"}},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "JVM flag loading an agent",
+			args:    &api.ServerArguments{MemoryStartGB: ref(1), MemoryMaxGB: ref(2), JvmFlags: &[]string{"-javaagent:agent.jar"}},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "JVM flag running a command",
+			args:    &api.ServerArguments{MemoryStartGB: ref(1), MemoryMaxGB: ref(2), JvmFlags: &[]string{"-XX:OnOutOfMemoryError=sh"}},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "invalid environment variable",
+			args:    &api.ServerArguments{MemoryStartGB: ref(1), MemoryMaxGB: ref(2), Env: &map[string]string{"A=B": "C"}},
+			wantErr: ErrInvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		if err := ValidateArgs(tc.args); !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: expected error `%v`, got `%v`", tc.name, tc.wantErr, err)
+		}
+	}
+
+	// every invalid field is reported
+	err := ValidateArgs(&api.ServerArguments{
+		MemoryMaxMB: ref(0),
+		Port:        ref(-1),
+		JvmFlags:    &[]string{"-jar"},
+	})
+	var validation api.ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("expected a validation error, got `%v`", err)
+	}
+	fields := make([]string, 0)
+	for _, field := range validation {
+		fields = append(fields, field.Field)
+	}
+	if want := []string{"memoryStartMB", "memoryMaxMB", "port", "jvmFlags"}; !reflect.DeepEqual(want, fields) {
+		t.Fatalf("expected invalid fields `%v`, got `%v`", want, fields)
+	}
+}
+
+func TestBuildEnv(t *testing.T) {
+	t.Setenv("GOMCSC_TEST", "1")
+
+	env := BuildEnv(&api.ServerArguments{
+		Env: &map[string]string{"TZ": "UTC", "LANG": "C.UTF-8"},
+	})
+
+	want := []string{"GOMCSC_TEST=1", "LANG=C.UTF-8", "TZ=UTC"}
+	for _, variable := range want {
+		if !slices.Contains(env, variable) {
+			t.Errorf("expected `%s` in the environment", variable)
+		}
+	}
+	// variables from args come last so they override the inherited ones
+	if got := env[len(env)-2:]; !reflect.DeepEqual(want[1:], got) {
+		t.Errorf("expected environment to end with `%v`, got `%v`", want[1:], got)
+	}
+}
+
+func TestPresetFlags(t *testing.T) {
+	t.Parallel()
+
+	args := &api.ServerArguments{
+		MemoryStartGB: ref(4),
+		MemoryMaxGB:   ref(4),
+		Preset:        ref(api.Aikar),
+		JvmFlags:      &[]string{"-XX:+UseLargePages"},
+	}
+
+	gotArgs := BuildStringArgs("1.20.6", args)
+	if !slices.Contains(gotArgs, "-XX:G1HeapRegionSize=8M") {
+		t.Errorf("expected the flags for heaps smaller than 12GB, got `%v`", gotArgs)
+	}
+	// extra JVM flags come after the preset so they can override it
+	preset, extra := slices.Index(gotArgs, "-XX:+UseG1GC"), slices.Index(gotArgs, "-XX:+UseLargePages")
+	if preset < 0 || extra < preset || extra > slices.Index(gotArgs, "-jar") {
+		t.Errorf("expected preset flags, then extra JVM flags, then the jar, got `%v`", gotArgs)
+	}
+
+	args.MemoryMaxGB = ref(16)
+	if gotArgs := BuildStringArgs("1.20.6", args); !slices.Contains(gotArgs, "-XX:G1HeapRegionSize=16M") {
+		t.Errorf("expected the flags for heaps of 12GB or more, got `%v`", gotArgs)
+	}
 }
@@ -0,0 +1,386 @@
+package minecraft
+
+import (
+	"archive/zip"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+var (
+	ErrBackupNotFound   = api.ErrBackupNotFound
+	ErrBackupInProgress = api.ErrBackupInProgress
+	ErrNoWorlds         = api.ErrNoWorlds
+)
+
+const (
+	// BackupSaveTimeout is how long a backup waits for the Minecraft server to
+	// save its worlds to disk. Saving large worlds takes a lot longer than
+	// other commands do.
+	BackupSaveTimeout = 5 * time.Minute
+
+	// backupsDirName is the name of the directory, in the server directory,
+	// that backups are kept in.
+	backupsDirName = "backups"
+	// backupIDFormat is the time layout backup IDs are made from.
+	backupIDFormat = "20060102T150405Z"
+	backupExt      = ".zip"
+
+	backupFileMode fs.FileMode = 0o644
+)
+
+// worldDirSuffixes are appended to the level name to get the directories the
+// Minecraft server keeps its dimensions in.
+var worldDirSuffixes = []string{"", "_nether", "_the_end"}
+
+// backupIDPattern matches the IDs of backups. Backups created within the same
+// second get a `-<n>` suffix.
+var backupIDPattern = regexp.MustCompile(`^\d{8}T\d{6}Z(-\d+)?$`)
+
+// BackupManager keeps backups of the worlds of a Minecraft server in a
+// directory as zip archives named after the time they were created.
+type BackupManager struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+func NewBackupManager(dir string) *BackupManager {
+	return &BackupManager{dir: dir}
+}
+
+// Create backs up the worlds of the Minecraft server in serverDir that has
+// the level name levelName. Only one backup is created at a time; Create
+// returns ErrBackupInProgress if another one is already being created.
+//
+// If a Minecraft server is attached to console, automatic saving is turned off
+// while the worlds are copied, after the server has saved them to disk, so
+// that they aren't changed halfway through the backup.
+func (b *BackupManager) Create(console *Console, serverDir, levelName string) (*api.Backup, error) {
+	if !b.mutex.TryLock() {
+		return nil, ErrBackupInProgress
+	}
+	defer b.mutex.Unlock()
+
+	worlds := make([]string, 0, len(worldDirSuffixes))
+	for _, suffix := range worldDirSuffixes {
+		info, err := os.Stat(filepath.Join(serverDir, levelName+suffix))
+		if err == nil && info.IsDir() {
+			worlds = append(worlds, levelName+suffix)
+		} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	if len(worlds) == 0 {
+		return nil, ErrNoWorlds
+	}
+
+	if console.Attached() {
+		if err := executeWithin(console, "save-off", CommandTimeout); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err := executeWithin(console, "save-on", CommandTimeout); err != nil {
+				log.Println("unexpected error turning automatic saving back on:", err)
+			}
+		}()
+
+		if err := executeWithin(console, "save-all flush", BackupSaveTimeout); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(b.dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	id, err := b.newID(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	err = api.WriteAtomic(b.filepath(id), backupFileMode, func(file io.Writer) error {
+		return zipWorlds(file, serverDir, worlds)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return b.info(id)
+}
+
+// List returns the backups in the directory of the backup manager, oldest
+// first. Backups whose archive can't be read are logged and left out.
+func (b *BackupManager) List() (*api.BackupList, error) {
+	backups := make(api.BackupList, 0)
+
+	entries, err := os.ReadDir(b.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &backups, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), backupExt)
+		if !ok || !entry.Type().IsRegular() || !backupIDPattern.MatchString(id) {
+			continue
+		}
+
+		backup, err := b.info(id)
+		if err != nil {
+			// one damaged archive shouldn't hide the other backups, or stop
+			// them from being pruned
+			log.Printf("skipping backup %s: %v", id, err)
+			continue
+		}
+		backups = append(backups, *backup)
+	}
+	slices.SortFunc(backups, func(a, b api.Backup) int { return compareBackupIDs(a.Id, b.Id) })
+
+	return &backups, nil
+}
+
+// Delete deletes the backup with the ID id.
+func (b *BackupManager) Delete(id string) error {
+	if !backupIDPattern.MatchString(id) {
+		return ErrBackupNotFound
+	}
+
+	if err := os.Remove(b.filepath(id)); errors.Is(err, fs.ErrNotExist) {
+		return ErrBackupNotFound
+	} else if err != nil {
+		return err
+	}
+
+	return api.SyncDir(b.dir)
+}
+
+// newID returns an ID for a backup created at t that no other backup has.
+func (b *BackupManager) newID(t time.Time) (string, error) {
+	base := t.UTC().Format(backupIDFormat)
+	id := base
+	for n := 2; ; n++ {
+		_, err := os.Stat(b.filepath(id))
+		if errors.Is(err, fs.ErrNotExist) {
+			return id, nil
+		} else if err != nil {
+			return "", err
+		}
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// compareBackupIDs compares the backup IDs a and b by the order the backups
+// were created in.
+func compareBackupIDs(a, b string) int {
+	aBase, aN, _ := strings.Cut(a, "-")
+	bBase, bN, _ := strings.Cut(b, "-")
+	if c := strings.Compare(aBase, bBase); c != 0 {
+		return c
+	}
+
+	// the first backup created within a second has no suffix
+	n, _ := strconv.Atoi(aN)
+	m, _ := strconv.Atoi(bN)
+
+	return cmp.Compare(n, m)
+}
+
+func (b *BackupManager) filepath(id string) string {
+	return filepath.Join(b.dir, id+backupExt)
+}
+
+// info describes the backup with the ID id.
+func (b *BackupManager) info(id string) (*api.Backup, error) {
+	created, err := time.Parse(backupIDFormat, id[:len(backupIDFormat)])
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := zip.OpenReader(b.filepath(id))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", b.filepath(id), err)
+	}
+	defer func() {
+		if err := r.Close(); err != nil {
+			log.Println("unexpected error closing backup:", err)
+		}
+	}()
+
+	info, err := os.Stat(b.filepath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	worlds := make([]string, 0, len(worldDirSuffixes))
+	for _, f := range r.File {
+		world, _, _ := strings.Cut(f.Name, "/")
+		if len(world) > 0 && (len(worlds) == 0 || worlds[len(worlds)-1] != world) {
+			worlds = append(worlds, world)
+		}
+	}
+
+	return &api.Backup{
+		Id:     id,
+		Size:   info.Size(),
+		Time:   created,
+		Worlds: worlds,
+	}, nil
+}
+
+// zipWorlds writes a zip archive of the world directories worlds in serverDir
+// to w, one after the other.
+func zipWorlds(w io.Writer, serverDir string, worlds []string) error {
+	zw := zip.NewWriter(w)
+
+	for _, world := range worlds {
+		err := filepath.WalkDir(filepath.Join(serverDir, world), func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// the lock the Minecraft server holds on the world, which can't
+			// be read on Windows
+			if d.Name() == "session.lock" {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && !info.Mode().IsRegular() {
+				return nil
+			}
+
+			rel, err := filepath.Rel(serverDir, name)
+			if err != nil {
+				return err
+			}
+			header, err := zip.FileInfoHeader(info)
+			if err != nil {
+				return err
+			}
+			header.Name = filepath.ToSlash(rel)
+			if d.IsDir() {
+				header.Name += "/"
+				_, err := zw.CreateHeader(header)
+				return err
+			}
+			header.Method = zip.Deflate
+
+			return zipFile(zw, header, name)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func zipFile(zw *zip.Writer, header *zip.FileHeader, name string) error {
+	in, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer closeFile(in)
+
+	out, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+
+	return err
+}
+
+// executeWithin executes cmd on console, giving the Minecraft server timeout
+// to respond to it.
+func executeWithin(console *Console, cmd string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	_, err := console.Execute(ctx, cmd)
+
+	return err
+}
+
+// backupManager returns the backup manager of the server, creating it if it
+// doesn't exist yet. m must be locked.
+func (m *JavaMinecraftServer) backupManager() (*BackupManager, error) {
+	if m.filepaths == nil {
+		return nil, ErrFilepathsNotProvided
+	}
+	if m.backups == nil {
+		m.backups = NewBackupManager(path.Join(m.serverDir(), backupsDirName))
+	}
+
+	return m.backups, nil
+}
+
+// Backups implements api.MinecraftServerInterface.
+func (m *JavaMinecraftServer) Backups() (*api.BackupList, error) {
+	m.Lock()
+	backups, err := m.backupManager()
+	m.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return backups.List()
+}
+
+// CreateBackup implements api.MinecraftServerInterface.
+func (m *JavaMinecraftServer) CreateBackup() (*api.Backup, error) {
+	m.Lock()
+	backups, err := m.backupManager()
+	if err != nil {
+		m.Unlock()
+		return nil, err
+	}
+	console := m.console
+	serverDir := m.serverDir()
+	levelName := "world"
+	if m.properties != nil && m.properties.LevelName != nil && len(*m.properties.LevelName) > 0 {
+		levelName = *m.properties.LevelName
+	}
+	m.Unlock()
+
+	if !filepath.IsLocal(levelName) {
+		return nil, api.ValidationError{{
+			Err:    ErrInvalidProperty,
+			Field:  "levelName",
+			Reason: "must be a directory inside the server directory",
+		}}
+	}
+
+	// the server stays unlocked while the worlds are saved and copied, which
+	// can take minutes
+	return backups.Create(console, serverDir, levelName)
+}
+
+// DeleteBackup implements api.MinecraftServerInterface.
+func (m *JavaMinecraftServer) DeleteBackup(id string) error {
+	m.Lock()
+	backups, err := m.backupManager()
+	m.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return backups.Delete(id)
+}
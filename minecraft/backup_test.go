@@ -0,0 +1,236 @@
+package minecraft
+
+import (
+	"archive/zip"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+// fakeSavingConsole responds to the commands that control saving the same way
+// the vanilla Minecraft server does.
+const fakeSavingConsole = `
+log() { echo "[12:00:00] [Server thread/INFO]: $1"; }
+while read line; do
+	line=${line%$(printf '\r')}
+	case "$line" in
+	"save-off") log "Automatic saving is now disabled";;
+	"save-on") log "Automatic saving is now enabled";;
+	"save-all flush") log "Saving the game (this may take a moment!)"; log "Saved the game";;
+	*) log "Unknown or incomplete command, see below for error";;
+	esac
+done
+`
+
+// createWorlds creates the world directories worlds in dir, with a few files
+// in each of them.
+func createWorlds(t *testing.T, dir string, worlds ...string) {
+	t.Helper()
+
+	for _, world := range worlds {
+		for name, data := range map[string]string{
+			"level.dat":          "level",
+			"session.lock":       "lock",
+			"region/r.0.0.mca":   "region",
+			"playerdata/abc.dat": "player",
+		} {
+			name = filepath.Join(dir, world, name)
+			if err := os.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(name, []byte(data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestBackupManager(t *testing.T) {
+	t.Parallel()
+
+	serverDir := t.TempDir()
+	createWorlds(t, serverDir, "world", "world_nether", "world_the_end")
+	backups := NewBackupManager(filepath.Join(serverDir, backupsDirName))
+
+	if list, err := backups.List(); err != nil || len(*list) != 0 {
+		t.Fatalf("expected no backups, got `%v` with error `%v`", list, err)
+	}
+
+	first, err := backups.Create(nil, serverDir, "world")
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	wantWorlds := []string{"world", "world_nether", "world_the_end"}
+	if !reflect.DeepEqual(wantWorlds, first.Worlds) {
+		t.Fatalf("expected worlds `%v`, got `%v`", wantWorlds, first.Worlds)
+	}
+	if !backupIDPattern.MatchString(first.Id) || first.Size == 0 || first.Time.IsZero() {
+		t.Fatalf("expected a backup with an ID, size and time, got `%+v`", *first)
+	}
+
+	r, err := zip.OpenReader(backups.filepath(first.Id))
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	names := make([]string, 0, len(r.File))
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	_ = r.Close()
+	for _, name := range []string{"world/level.dat", "world/region/r.0.0.mca", "world_nether/playerdata/abc.dat", "world_the_end/"} {
+		if !slices.Contains(names, name) {
+			t.Errorf("expected `%s` to be backed up, got `%v`", name, names)
+		}
+	}
+	if slices.Contains(names, "world/session.lock") {
+		t.Errorf("expected the session lock not to be backed up, got `%v`", names)
+	}
+
+	second, err := backups.Create(nil, serverDir, "world")
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if first.Id == second.Id {
+		t.Fatalf("expected backups to have different IDs, got `%s` twice", first.Id)
+	}
+
+	list, err := backups.List()
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if want := (api.BackupList{*first, *second}); !reflect.DeepEqual(want, *list) {
+		t.Fatalf("expected backups `%v`, got `%v`", want, *list)
+	}
+
+	if err := backups.Delete(first.Id); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if list, err := backups.List(); err != nil || len(*list) != 1 || (*list)[0].Id != second.Id {
+		t.Fatalf("expected only backup `%s`, got `%v` with error `%v`", second.Id, list, err)
+	}
+
+	for _, id := range []string{first.Id, "../world", ""} {
+		if err := backups.Delete(id); !errors.Is(err, ErrBackupNotFound) {
+			t.Errorf("expected error `%v` deleting `%s`, got `%v`", ErrBackupNotFound, id, err)
+		}
+	}
+}
+
+func TestBackupManagerErrors(t *testing.T) {
+	t.Parallel()
+
+	serverDir := t.TempDir()
+	backups := NewBackupManager(filepath.Join(serverDir, backupsDirName))
+
+	if _, err := backups.Create(nil, serverDir, "world"); !errors.Is(err, ErrNoWorlds) {
+		t.Fatalf("expected error `%v`, got `%v`", ErrNoWorlds, err)
+	}
+
+	createWorlds(t, serverDir, "world")
+	backups.mutex.Lock()
+	_, err := backups.Create(nil, serverDir, "world")
+	backups.mutex.Unlock()
+	if !errors.Is(err, ErrBackupInProgress) {
+		t.Fatalf("expected error `%v`, got `%v`", ErrBackupInProgress, err)
+	}
+}
+
+func TestBackupManagerDamagedBackup(t *testing.T) {
+	t.Parallel()
+
+	serverDir := t.TempDir()
+	createWorlds(t, serverDir, "world")
+	backups := NewBackupManager(filepath.Join(serverDir, backupsDirName))
+
+	backup, err := backups.Create(nil, serverDir, "world")
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	damaged := backup.Time.Add(-time.Hour).Format(backupIDFormat)
+	if err := os.WriteFile(backups.filepath(damaged), []byte("not a zip archive"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the damaged backup is left out instead of failing the whole list
+	list, err := backups.List()
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if want := (api.BackupList{*backup}); !reflect.DeepEqual(want, *list) {
+		t.Fatalf("expected backups `%v`, got `%v`", want, *list)
+	}
+}
+
+func TestBackupManagerWithAttachedConsole(t *testing.T) {
+	t.Parallel()
+
+	serverDir := t.TempDir()
+	createWorlds(t, serverDir, "survival")
+	console := attachFakeConsole(t, fakeSavingConsole)
+	backups := NewBackupManager(filepath.Join(serverDir, backupsDirName))
+
+	backup, err := backups.Create(console, serverDir, "survival")
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if want := []string{"survival"}; !reflect.DeepEqual(want, backup.Worlds) {
+		t.Fatalf("expected worlds `%v`, got `%v`", want, backup.Worlds)
+	}
+
+	// saving is turned off and the worlds saved before they're copied, and
+	// saving is turned back on afterwards
+	messages := make([]string, 0)
+	for _, line := range console.History(0, 10) {
+		messages = append(messages, consoleMessage(line.Text))
+	}
+	want := []string{
+		"Automatic saving is now disabled",
+		"Saving the game (this may take a moment!)",
+		"Saved the game",
+		"Automatic saving is now enabled",
+	}
+	if !reflect.DeepEqual(want, messages) {
+		t.Fatalf("expected messages `%v`, got `%v`", want, messages)
+	}
+}
+
+func TestCreateBackup(t *testing.T) {
+	t.Parallel()
+
+	server := JavaMinecraftServer{}
+	if _, err := server.CreateBackup(); !errors.Is(err, ErrFilepathsNotProvided) {
+		t.Fatalf("expected error `%v`, got `%v`", ErrFilepathsNotProvided, err)
+	}
+
+	serverDir := t.TempDir()
+	createWorlds(t, serverDir, "world", "world_nether")
+	server = JavaMinecraftServer{
+		filepaths: &MinecraftServerConfigFilepaths{Properties: filepath.Join(serverDir, "server.properties")},
+	}
+
+	// the level name defaults to `world`
+	backup, err := server.CreateBackup()
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if _, err := os.Stat(filepath.Join(serverDir, backupsDirName, backup.Id+backupExt)); err != nil {
+		t.Fatalf("expected the backup to be in the server directory, got error `%v`", err)
+	}
+	if list, err := server.Backups(); err != nil || len(*list) != 1 {
+		t.Fatalf("expected one backup, got `%v` with error `%v`", list, err)
+	}
+	if err := server.DeleteBackup(backup.Id); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+
+	server.properties = &api.ServerProperties{LevelName: ref("../world")}
+	if _, err := server.CreateBackup(); !errors.Is(err, ErrInvalidProperty) {
+		t.Fatalf("expected error `%v`, got `%v`", ErrInvalidProperty, err)
+	}
+}
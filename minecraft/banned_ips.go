@@ -6,13 +6,23 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
+	"strings"
 
 	"github.com/raian621/go-mcsc/api"
 )
 
-var ErrNotInBannedIPs = errors.New("IP was not in ban list")
+var (
+	ErrInvalidIP       = api.ErrInvalidIP
+	ErrIPAlreadyBanned = api.ErrIPAlreadyBanned
+	ErrNotInBannedIPs  = api.ErrNotInBannedIPs
+)
 
 // BanIP implements api.MinecraftServerInterface.
+//
+// If the Minecraft server process is currently running, the command `/ban-ip
+// <ip> [reason]` is sent to the Minecraft server console and the IP is added
+// to the in-memory banned IPs list once the Minecraft server has banned it.
 func (m *JavaMinecraftServer) BanIP(ip *api.BannedIP) error {
 	m.Lock()
 	defer m.Unlock()
@@ -21,12 +31,30 @@ func (m *JavaMinecraftServer) BanIP(ip *api.BannedIP) error {
 		return ErrNilConfig
 	}
 
-	if m.console != nil {
-		if err := m.console.SendCommand(fmt.Sprintf("/ban-ip %s", ip.Ip)); err != nil {
-			return nil
+	if m.console.Attached() {
+		err := m.execute(strings.TrimSpace(fmt.Sprintf("/ban-ip %s %s", ip.Ip, ip.Reason)))
+		if err != nil && !errors.Is(err, ErrIPAlreadyBanned) {
+			return err
+		}
+		// the list may have been replaced while the server was unlocked
+		if m.bannedIPs == nil {
+			return ErrNilConfig
+		}
+
+		// the IP is banned either way
+		if idx := m.bannedIPsIndex(ip.Ip); idx == -1 {
+			*m.bannedIPs = append(*m.bannedIPs, *ip)
 		}
+
+		return err
 	}
 
+	if net.ParseIP(ip.Ip) == nil {
+		return ErrInvalidIP
+	}
+	if idx := m.bannedIPsIndex(ip.Ip); idx != -1 {
+		return ErrIPAlreadyBanned
+	}
 	*m.bannedIPs = append(*m.bannedIPs, *ip)
 
 	return nil
@@ -42,12 +70,17 @@ func (m *JavaMinecraftServer) BannedIPs() *api.BannedIPList {
 	}
 
 	bannedIPsCpy := make(api.BannedIPList, len(*m.bannedIPs))
-	copy(*m.bannedIPs, bannedIPsCpy)
+	copy(bannedIPsCpy, *m.bannedIPs)
 
 	return &bannedIPsCpy
 }
 
 // PardonIP implements api.MinecraftServerInterface.
+//
+// If the Minecraft server process is currently running, the command
+// `/pardon-ip <ip>` is sent to the Minecraft server console and the IP is
+// removed from the in-memory banned IPs list once the Minecraft server has
+// unbanned it.
 func (m *JavaMinecraftServer) PardonIP(ip string) error {
 	m.Lock()
 	defer m.Unlock()
@@ -56,27 +89,43 @@ func (m *JavaMinecraftServer) PardonIP(ip string) error {
 		return ErrNilConfig
 	}
 
-	idx := -1
-	for i, b := range *m.bannedIPs {
-		if b.Ip == ip {
-			idx = i
-			break
+	if m.console.Attached() {
+		err := m.execute(fmt.Sprintf("/pardon-ip %s", ip))
+		if err != nil && !errors.Is(err, ErrNotInBannedIPs) {
+			return err
 		}
+		// the list may have been replaced while the server was unlocked
+		if m.bannedIPs == nil {
+			return ErrNilConfig
+		}
+
+		// the IP isn't banned either way
+		if idx := m.bannedIPsIndex(ip); idx != -1 {
+			*m.bannedIPs = append((*m.bannedIPs)[:idx], (*m.bannedIPs)[idx+1:]...)
+		}
+
+		return err
 	}
 
+	idx := m.bannedIPsIndex(ip)
 	if idx == -1 {
 		return ErrNotInBannedIPs
 	}
+	*m.bannedIPs = append((*m.bannedIPs)[:idx], (*m.bannedIPs)[idx+1:]...)
 
-	if m.console != nil {
-		if err := m.console.SendCommand(fmt.Sprintf("/pardon-ip %s", ip)); err != nil {
-			return err
+	return nil
+}
+
+// bannedIPsIndex returns the index of ip in the in-memory banned IPs list, or
+// -1 if ip isn't in it. m must be locked.
+func (m *JavaMinecraftServer) bannedIPsIndex(ip string) int {
+	for i, b := range *m.bannedIPs {
+		if b.Ip == ip {
+			return i
 		}
-	} else {
-		*m.bannedIPs = append((*m.bannedIPs)[:idx], (*m.bannedIPs)[:idx+1]...)
 	}
 
-	return nil
+	return -1
 }
 
 func (m *JavaMinecraftServer) CreateBannedIPs() {
@@ -112,7 +161,7 @@ func (m *JavaMinecraftServer) SetBannedIPs(b *api.BannedIPList) {
 	m.Lock()
 	defer m.Unlock()
 
-	if m.console != nil {
+	if m.console.Attached() {
 		bannedIPs := make(map[api.BannedIP]bool, 0)
 
 		for i := range *m.bannedIPs {
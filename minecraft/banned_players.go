@@ -5,13 +5,22 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/raian621/go-mcsc/api"
 )
 
-var ErrNotInBannedPlayers = errors.New("player not in banned players list")
+var (
+	ErrNotInBannedPlayers  = api.ErrNotInBannedPlayers
+	ErrPlayerAlreadyBanned = api.ErrPlayerAlreadyBanned
+)
 
 // BanPlayer implements api.MinecraftServerInterface.
+//
+// If the Minecraft server process is currently running, the command `/ban
+// <playername> [reason]` is sent to the Minecraft server console and the
+// player is added to the in-memory banned players list once the Minecraft
+// server has banned them.
 func (m *JavaMinecraftServer) BanPlayer(p *api.BannedPlayer) error {
 	m.Lock()
 	defer m.Unlock()
@@ -20,18 +29,40 @@ func (m *JavaMinecraftServer) BanPlayer(p *api.BannedPlayer) error {
 		return ErrNilConfig
 	}
 
-	if m.console != nil {
-		if err := m.console.SendCommand(fmt.Sprintf("/ban %s", *p.Name)); err != nil {
+	player := api.PlayerInfo{Name: p.Name, Uuid: &p.Uuid}
+
+	if m.console.Attached() {
+		err := m.execute(strings.TrimSpace(fmt.Sprintf("/ban %s %s", playerName(player), p.Reason)))
+		if err != nil && !errors.Is(err, ErrPlayerAlreadyBanned) {
 			return err
 		}
+		// the list may have been replaced while the server was unlocked
+		if m.bannedPlayers == nil {
+			return ErrNilConfig
+		}
+
+		// the player is banned either way
+		if idx := m.bannedPlayersIndex(player); idx == -1 {
+			*m.bannedPlayers = append(*m.bannedPlayers, *p)
+		}
+
+		return err
 	}
 
+	if idx := m.bannedPlayersIndex(player); idx != -1 {
+		return ErrPlayerAlreadyBanned
+	}
 	*m.bannedPlayers = append(*m.bannedPlayers, *p)
 
 	return nil
 }
 
 // PardonPlayer implements api.MinecraftServerInterface.
+//
+// If the Minecraft server process is currently running, the command `/pardon
+// <playername>` is sent to the Minecraft server console and the player is
+// removed from the in-memory banned players list once the Minecraft server has
+// unbanned them.
 func (m *JavaMinecraftServer) PardonPlayer(p *api.PlayerInfo) error {
 	m.Lock()
 	defer m.Unlock()
@@ -40,26 +71,44 @@ func (m *JavaMinecraftServer) PardonPlayer(p *api.PlayerInfo) error {
 		return ErrNilConfig
 	}
 
-	idx := -1
-	for i, b := range *m.bannedPlayers {
-		if *b.Name == *p.Name && b.Uuid == *p.Uuid {
-			idx = i
-			break
+	if m.console.Attached() {
+		err := m.execute(fmt.Sprintf("/pardon %s", playerName(*p)))
+		if err != nil && !errors.Is(err, ErrNotInBannedPlayers) {
+			return err
 		}
+		// the list may have been replaced while the server was unlocked
+		if m.bannedPlayers == nil {
+			return ErrNilConfig
+		}
+
+		// the player isn't banned either way
+		if idx := m.bannedPlayersIndex(*p); idx != -1 {
+			*m.bannedPlayers = append((*m.bannedPlayers)[:idx], (*m.bannedPlayers)[idx+1:]...)
+		}
+
+		return err
 	}
+
+	idx := m.bannedPlayersIndex(*p)
 	if idx == -1 {
 		return ErrNotInBannedPlayers
 	}
-
 	*m.bannedPlayers = append((*m.bannedPlayers)[:idx], (*m.bannedPlayers)[idx+1:]...)
 
-	if m.console != nil {
-		if err := m.console.SendCommand(fmt.Sprintf("/pardon %s", *p.Name)); err != nil {
-			return err
+	return nil
+}
+
+// bannedPlayersIndex returns the index of p in the in-memory banned players
+// list, or -1 if p isn't in it. m must be locked.
+func (m *JavaMinecraftServer) bannedPlayersIndex(p api.PlayerInfo) int {
+	for i := range *m.bannedPlayers {
+		b := &(*m.bannedPlayers)[i]
+		if samePlayer(api.PlayerInfo{Name: b.Name, Uuid: &b.Uuid}, p) {
+			return i
 		}
 	}
 
-	return nil
+	return -1
 }
 
 func (m *JavaMinecraftServer) BannedPlayers() *api.BannedPlayerList {
@@ -71,7 +120,7 @@ func (m *JavaMinecraftServer) BannedPlayers() *api.BannedPlayerList {
 	}
 
 	bannedPlayersCpy := make(api.BannedPlayerList, len(*m.bannedPlayers))
-	copy(*m.bannedPlayers, bannedPlayersCpy)
+	copy(bannedPlayersCpy, *m.bannedPlayers)
 
 	return &bannedPlayersCpy
 }
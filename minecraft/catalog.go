@@ -0,0 +1,213 @@
+package minecraft
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+const (
+	// DefaultManifestBaseURL is where Mojang serves the version manifest.
+	DefaultManifestBaseURL = "https://piston-meta.mojang.com"
+
+	// manifestPath is the path of the version manifest relative to the
+	// manifest base URL.
+	manifestPath = "/mc/game/version_manifest_v2.json"
+
+	// catalogConcurrency is how many version JSONs are fetched at once.
+	catalogConcurrency = 8
+)
+
+var ErrCatalogRefresh = api.ErrCatalogRefresh
+
+// versionManifest is the format of Mojang's version_manifest_v2.json.
+type versionManifest struct {
+	Latest struct {
+		Release  string `json:"release"`
+		Snapshot string `json:"snapshot"`
+	} `json:"latest"`
+	Versions []struct {
+		ID          string          `json:"id"`
+		Type        api.ReleaseType `json:"type"`
+		URL         string          `json:"url"`
+		ReleaseTime time.Time       `json:"releaseTime"`
+		SHA1        string          `json:"sha1"`
+	} `json:"versions"`
+}
+
+// versionJSON is the format of the per-version JSONs the version manifest
+// links to. Only the fields the catalog needs are decoded.
+type versionJSON struct {
+	Downloads struct {
+		Server *struct {
+			SHA1 string `json:"sha1"`
+			URL  string `json:"url"`
+		} `json:"server"`
+	} `json:"downloads"`
+	JavaVersion *struct {
+		MajorVersion int `json:"majorVersion"`
+	} `json:"javaVersion"`
+}
+
+// Catalog builds a VersionMap from Mojang's version manifest.
+type Catalog struct {
+	// BaseURL is where the version manifest is served from. If it isn't
+	// DefaultManifestBaseURL, the version JSONs and server jars the manifest
+	// links to are expected to be mirrored at the same paths under BaseURL.
+	BaseURL string
+	Client  *http.Client
+}
+
+func NewCatalog(baseURL string) *Catalog {
+	if len(baseURL) == 0 {
+		baseURL = DefaultManifestBaseURL
+	}
+
+	return &Catalog{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Client:  http.DefaultClient,
+	}
+}
+
+// Refresh fetches the version manifest and returns a VersionMap of every
+// version that has a server jar. The version JSONs of versions that are
+// already in known with a SHA1 sum and release type aren't fetched again,
+// since published versions don't change.
+func (c *Catalog) Refresh(ctx context.Context, known VersionMap) (VersionMap, error) {
+	var manifest versionManifest
+	if err := c.getJSON(ctx, c.BaseURL+manifestPath, &manifest); err != nil {
+		return nil, err
+	}
+
+	var (
+		versions = make(VersionMap, len(manifest.Versions))
+		mutex    sync.Mutex
+		wg       sync.WaitGroup
+		failed   int
+		firstErr error
+		sem      = make(chan struct{}, catalogConcurrency)
+	)
+
+	for _, v := range manifest.Versions {
+		if info, ok := known[v.ID]; ok && len(info.SHA1) > 0 && len(info.Type) > 0 {
+			versions[v.ID] = info
+			continue
+		}
+
+		v := v
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var vj versionJSON
+			if err := c.getJSON(ctx, c.mirror(v.URL), &vj); err != nil {
+				mutex.Lock()
+				if failed++; firstErr == nil {
+					firstErr = err
+				}
+				mutex.Unlock()
+				return
+			}
+
+			// old versions don't have a server jar
+			if vj.Downloads.Server == nil {
+				return
+			}
+
+			info := VersionInfo{
+				Link:        c.mirror(vj.Downloads.Server.URL),
+				SHA1:        vj.Downloads.Server.SHA1,
+				Type:        v.Type,
+				ReleaseTime: ref(v.ReleaseTime),
+			}
+			if vj.JavaVersion != nil {
+				info.JavaVersion = vj.JavaVersion.MajorVersion
+			}
+
+			mutex.Lock()
+			versions[v.ID] = info
+			mutex.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, fmt.Errorf("%w: %d versions failed: %w", ErrCatalogRefresh, failed, firstErr)
+	}
+
+	return versions, nil
+}
+
+// mirror rewrites a link from the version manifest to point to the same path
+// under the catalog's base URL, unless the catalog uses Mojang's servers.
+func (c *Catalog) mirror(link string) string {
+	if c.BaseURL == DefaultManifestBaseURL {
+		return link
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	base, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return link
+	}
+	base.Path = path.Join(base.Path, u.Path)
+
+	return base.String()
+}
+
+func (c *Catalog) getJSON(ctx context.Context, link string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return err
+	}
+	res, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded with %s", link, res.Status)
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// saveVersions saves versions to the versions file at filepath.
+func saveVersions(filepath string, versions VersionMap) error {
+	return saveJSON(func(file io.Writer) error {
+		return encodeVersions(file, versions)
+	}, filepath)
+}
+
+// encodeVersions writes versions to file the way they're kept in the versions
+// file.
+func encodeVersions(file io.Writer, versions VersionMap) error {
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(versions)
+}
+
+// SetManifestBaseURL implements api.MinecraftServerInterface.
+func (m *JavaMinecraftServer) SetManifestBaseURL(baseURL string) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.manifestBaseURL = baseURL
+}
@@ -0,0 +1,116 @@
+package minecraft
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+// fakeManifestMirror serves a version manifest in Mojang's format with a
+// release, a snapshot and an old alpha without a server jar. Links in the
+// manifest point to Mojang's servers, like they do in a real mirror.
+func fakeManifestMirror(t *testing.T, versionRequests *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	manifest := `{
+		"latest": {"release": "1.20.6", "snapshot": "24w14a"},
+		"versions": [
+			{"id": "24w14a", "type": "snapshot", "url": "https://piston-meta.mojang.com/v1/packages/aaa/24w14a.json", "releaseTime": "2024-04-03T12:00:00+00:00"},
+			{"id": "1.20.6", "type": "release", "url": "https://piston-meta.mojang.com/v1/packages/bbb/1.20.6.json", "releaseTime": "2024-04-29T12:28:06+00:00"},
+			{"id": "rd-132211", "type": "old_alpha", "url": "https://piston-meta.mojang.com/v1/packages/ccc/rd-132211.json", "releaseTime": "2009-05-13T20:11:00+00:00"}
+		]
+	}`
+	versionJSONs := map[string]string{
+		"/v1/packages/aaa/24w14a.json": `{
+			"downloads": {"server": {"sha1": "1111111111111111111111111111111111111111", "size": 1, "url": "https://piston-data.mojang.com/v1/objects/111/server.jar"}},
+			"javaVersion": {"component": "java-runtime-delta", "majorVersion": 21}
+		}`,
+		"/v1/packages/bbb/1.20.6.json": `{
+			"downloads": {"server": {"sha1": "2222222222222222222222222222222222222222", "size": 1, "url": "https://piston-data.mojang.com/v1/objects/222/server.jar"}},
+			"javaVersion": {"component": "java-runtime-delta", "majorVersion": 21}
+		}`,
+		"/v1/packages/ccc/rd-132211.json": `{"downloads": {"client": {}}}`,
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/mirror"+manifestPath {
+			fmt.Fprint(w, manifest)
+			return
+		}
+		if body, ok := versionJSONs[r.URL.Path[len("/mirror"):]]; ok {
+			versionRequests.Add(1)
+			fmt.Fprint(w, body)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestCatalogRefresh(t *testing.T) {
+	t.Parallel()
+
+	var versionRequests atomic.Int32
+	ts := fakeManifestMirror(t, &versionRequests)
+	catalog := NewCatalog(ts.URL + "/mirror/")
+
+	versions, err := catalog.Refresh(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+
+	want := VersionMap{
+		"24w14a": {
+			Link:        ts.URL + "/mirror/v1/objects/111/server.jar",
+			SHA1:        "1111111111111111111111111111111111111111",
+			Type:        api.Snapshot,
+			ReleaseTime: ref(time.Date(2024, 4, 3, 12, 0, 0, 0, time.UTC)),
+			JavaVersion: 21,
+		},
+		"1.20.6": {
+			Link:        ts.URL + "/mirror/v1/objects/222/server.jar",
+			SHA1:        "2222222222222222222222222222222222222222",
+			Type:        api.Release,
+			ReleaseTime: ref(time.Date(2024, 4, 29, 12, 28, 6, 0, time.UTC)),
+			JavaVersion: 21,
+		},
+	}
+	for version, info := range versions {
+		// compare release times by instant since their locations differ
+		if w, ok := want[version]; ok && info.ReleaseTime.Equal(*w.ReleaseTime) {
+			info.ReleaseTime = w.ReleaseTime
+			versions[version] = info
+		}
+	}
+	if !reflect.DeepEqual(want, versions) {
+		t.Fatalf("expected versions `%+v`, got `%+v`", want, versions)
+	}
+	if n := versionRequests.Load(); n != 3 {
+		t.Fatalf("expected 3 version JSON requests, got %d", n)
+	}
+
+	// known versions aren't fetched again
+	if _, err := catalog.Refresh(context.Background(), versions); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if n := versionRequests.Load(); n != 4 {
+		t.Fatalf("expected 1 more version JSON request, got %d", n-3)
+	}
+
+	if got := versions.OfType(api.Snapshot).Versions(); !reflect.DeepEqual([]string{"24w14a"}, got) {
+		t.Fatalf("expected snapshots `[24w14a]`, got `%v`", got)
+	}
+
+	if _, err := NewCatalog(ts.URL+"/missing").Refresh(context.Background(), nil); err == nil {
+		t.Fatal("expected an error refreshing from a missing manifest")
+	}
+}
@@ -0,0 +1,144 @@
+package minecraft
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+var (
+	ErrCommandTimeout = api.ErrCommandTimeout
+	ErrPlayerNotFound = api.ErrPlayerNotFound
+	ErrUnknownCommand = errors.New("unknown or incomplete command")
+)
+
+// commandResponse matches a message the Minecraft server logs in response to
+// a command. A nil err means the command succeeded.
+type commandResponse struct {
+	pattern *regexp.Regexp
+	err     error
+}
+
+func response(pattern string, err error) commandResponse {
+	return commandResponse{regexp.MustCompile("^" + pattern + "$"), err}
+}
+
+// commandResponses holds the responses of the vanilla Minecraft server to the
+// commands the server controller sends, keyed by the command's name.
+var commandResponses = map[string][]commandResponse{
+	"whitelist add": {
+		response(`Added \S+ to the whitelist`, nil),
+		response(`Player is already whitelisted`, ErrPlayerAlreadyAllowed),
+	},
+	"whitelist remove": {
+		response(`Removed \S+ from the whitelist`, nil),
+		response(`Player is not whitelisted`, ErrPlayerNotInAllowlist),
+	},
+	"whitelist on": {
+		response(`Whitelist is now turned on`, nil),
+		response(`Whitelist is already turned on`, nil),
+	},
+	"whitelist off": {
+		response(`Whitelist is now turned off`, nil),
+		response(`Whitelist is already turned off`, nil),
+	},
+	"op": {
+		response(`Made \S+ a server operator`, nil),
+		response(`Nothing changed\. The player already is an operator`, ErrAlreadyOp),
+	},
+	"deop": {
+		response(`Made \S+ no longer a server operator`, nil),
+		response(`Nothing changed\. The player is not an operator`, ErrNotInOps),
+	},
+	"ban": {
+		response(`Banned \S+: .*`, nil),
+		response(`Nothing changed\. The player is already banned`, ErrPlayerAlreadyBanned),
+	},
+	"pardon": {
+		response(`Unbanned \S+`, nil),
+		response(`Nothing changed\. The player isn't banned`, ErrNotInBannedPlayers),
+	},
+	"ban-ip": {
+		response(`Banned IP \S+: .*`, nil),
+		response(`Nothing changed\. That IP is already banned`, ErrIPAlreadyBanned),
+		response(`Invalid IP address or unknown player`, ErrInvalidIP),
+	},
+	"pardon-ip": {
+		response(`Unbanned IP \S+`, nil),
+		response(`Nothing changed\. That IP isn't banned`, ErrNotInBannedIPs),
+		response(`Invalid IP address`, ErrInvalidIP),
+	},
+	"difficulty": {
+		response(`The difficulty has been set to \S+`, nil),
+		response(`The difficulty did not change; it is already set to \S+`, nil),
+	},
+	"defaultgamemode": {
+		response(`The default game mode is now .+`, nil),
+	},
+	"save-all": {
+		response(`Saved the game`, nil),
+	},
+	"save-off": {
+		response(`Automatic saving is now disabled`, nil),
+		response(`Saving is already turned off`, nil),
+	},
+	"save-on": {
+		response(`Automatic saving is now enabled`, nil),
+		response(`Saving is already turned on`, nil),
+	},
+}
+
+// genericResponses are responses that any command can produce.
+var genericResponses = []commandResponse{
+	response(`Unknown or incomplete command.*`, ErrUnknownCommand),
+	response(`That player does not exist`, ErrPlayerNotFound),
+	response(`No player was found`, ErrPlayerNotFound),
+}
+
+// logPrefix matches the prefix the Minecraft server puts in front of every
+// line it logs, e.g. `[12:00:00] [Server thread/INFO]: `.
+var logPrefix = regexp.MustCompile(`^(?:\[[^\]]*\] )*\[[^\]]*\]: `)
+
+// consoleMessage strips the log prefix from a line of console output.
+func consoleMessage(line string) string {
+	return logPrefix.ReplaceAllString(line, "")
+}
+
+// commandName returns the key of cmd in commandResponses.
+func commandName(cmd string) string {
+	fields := strings.Fields(strings.TrimPrefix(cmd, "/"))
+	if len(fields) == 0 {
+		return ""
+	}
+
+	if fields[0] == "whitelist" && len(fields) > 1 {
+		return fields[0] + " " + fields[1]
+	}
+
+	return fields[0]
+}
+
+// responsesFor returns the known responses to cmd, or nil if the response to
+// the command is unknown.
+func responsesFor(cmd string) []commandResponse {
+	return commandResponses[commandName(cmd)]
+}
+
+// matchResponse reports whether message is one of responses or a generic
+// response, along with the error the response corresponds to.
+func matchResponse(responses []commandResponse, message string) (bool, error) {
+	for _, r := range responses {
+		if r.pattern.MatchString(message) {
+			return true, r.err
+		}
+	}
+	for _, r := range genericResponses {
+		if r.pattern.MatchString(message) {
+			return true, r.err
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,281 @@
+package minecraft
+
+import (
+	"context"
+	"os/exec"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+// fakeVanillaConsole responds to whitelist commands the same way the vanilla
+// Minecraft server does, keeping track of its own allowlist, and to the
+// commands that change properties. The player `ghost` doesn't exist and `list`
+// produces a response that isn't matched.
+const fakeVanillaConsole = `
+allowed=" "
+log() { echo "[12:00:00] [Server thread/INFO]: $1"; }
+while read line; do
+	line=${line%$(printf '\r')}
+	set -- $line
+	case "$1 $2" in
+	"/whitelist add")
+		if [ "$3" = ghost ]; then log "That player does not exist"
+		elif case "$allowed" in *" $3 "*) true;; *) false;; esac; then log "Player is already whitelisted"
+		else allowed="$allowed$3 "; log "Added $3 to the whitelist"; fi;;
+	"/whitelist remove")
+		case "$allowed" in
+		*" $3 "*) allowed=$(echo "$allowed" | sed "s/ $3 / /"); log "Removed $3 from the whitelist";;
+		*) log "Player is not whitelisted";;
+		esac;;
+	"/whitelist on") log "Whitelist is now turned on";;
+	"/whitelist off") log "Whitelist is now turned off";;
+	"/difficulty "*) log "The difficulty has been set to $2";;
+	"/defaultgamemode "*) log "The default game mode is now $2";;
+	"list ") log "There are 0 of a max of 20 players online: ";;
+	"save-all ") ;;
+	*) log "Unknown or incomplete command, see below for error";;
+	esac
+done
+`
+
+func attachFakeConsole(t *testing.T, script string) *Console {
+	t.Helper()
+
+	console := NewConsole()
+	cmd := exec.Command("sh", "-c", script)
+	if err := console.Attach(cmd); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	go console.Pump(nil)
+
+	t.Cleanup(func() {
+		console.Detach()
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	return console
+}
+
+func TestConsoleMessage(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		line string
+		want string
+	}{
+		{
+			line: "[12:00:00] [Server thread/INFO]: Added player1 to the whitelist",
+			want: "Added player1 to the whitelist",
+		},
+		{
+			line: "[16Oct2026 12:00:00.000] [Server thread/INFO] [minecraft/DedicatedServer]: Saved the game",
+			want: "Saved the game",
+		},
+		{
+			line: "Saved the game",
+			want: "Saved the game",
+		},
+	}
+
+	for _, tc := range testCases {
+		if got := consoleMessage(tc.line); got != tc.want {
+			t.Errorf("expected message `%s`, got `%s`", tc.want, got)
+		}
+	}
+}
+
+func TestMatchResponse(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		cmd         string
+		message     string
+		wantMatched bool
+		wantErr     error
+	}{
+		{
+			name:        "success",
+			cmd:         "/whitelist add player1",
+			message:     "Added player1 to the whitelist",
+			wantMatched: true,
+		},
+		{
+			name:        "failure",
+			cmd:         "whitelist add player1",
+			message:     "Player is already whitelisted",
+			wantMatched: true,
+			wantErr:     ErrPlayerAlreadyAllowed,
+		},
+		{
+			name:        "generic failure",
+			cmd:         "/ban ghost",
+			message:     "That player does not exist",
+			wantMatched: true,
+			wantErr:     ErrPlayerNotFound,
+		},
+		{
+			name:        "response to another command",
+			cmd:         "/op player1",
+			message:     "Added player1 to the whitelist",
+			wantMatched: false,
+		},
+		{
+			name:        "unknown command",
+			cmd:         "/foo",
+			message:     "Unknown or incomplete command, see below for error",
+			wantMatched: true,
+			wantErr:     ErrUnknownCommand,
+		},
+	}
+
+	for _, tc := range testCases {
+		matched, err := matchResponse(responsesFor(tc.cmd), tc.message)
+		if matched != tc.wantMatched {
+			t.Errorf("%s: expected matched to be `%t`, got `%t`", tc.name, tc.wantMatched, matched)
+		}
+		if err != tc.wantErr {
+			t.Errorf("%s: expected error `%v`, got `%v`", tc.name, tc.wantErr, err)
+		}
+	}
+}
+
+func TestExecute(t *testing.T) {
+	t.Parallel()
+
+	console := attachFakeConsole(t, fakeVanillaConsole)
+
+	testCases := []struct {
+		cmd        string
+		timeout    time.Duration
+		wantOutput []string
+		wantErr    error
+	}{
+		{
+			cmd:        "/whitelist add player1",
+			timeout:    5 * time.Second,
+			wantOutput: []string{"Added player1 to the whitelist"},
+		},
+		{
+			cmd:        "/whitelist add player1",
+			timeout:    5 * time.Second,
+			wantOutput: []string{"Player is already whitelisted"},
+			wantErr:    ErrPlayerAlreadyAllowed,
+		},
+		{
+			cmd:        "list",
+			timeout:    5 * time.Second,
+			wantOutput: []string{"There are 0 of a max of 20 players online: "},
+		},
+		{
+			cmd:        "/foo",
+			timeout:    5 * time.Second,
+			wantOutput: []string{"Unknown or incomplete command, see below for error"},
+			wantErr:    ErrUnknownCommand,
+		},
+		{
+			cmd:        "save-all",
+			timeout:    100 * time.Millisecond,
+			wantOutput: []string{},
+			wantErr:    ErrCommandTimeout,
+		},
+	}
+
+	for _, tc := range testCases {
+		ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+		output, err := console.Execute(ctx, tc.cmd)
+		cancel()
+
+		if err != tc.wantErr {
+			t.Errorf("%s: expected error `%v`, got `%v`", tc.cmd, tc.wantErr, err)
+		}
+		if !reflect.DeepEqual(tc.wantOutput, output) {
+			t.Errorf("%s: expected output `%v`, got `%v`", tc.cmd, tc.wantOutput, output)
+		}
+	}
+}
+
+func TestAllowlistWithAttachedConsole(t *testing.T) {
+	t.Parallel()
+
+	server := JavaMinecraftServer{
+		console: attachFakeConsole(t, fakeVanillaConsole),
+		// player2 is in the allowlist file but the server doesn't know them
+		allowlist: &api.Allowlist{{Name: ref("player2")}},
+	}
+
+	steps := []struct {
+		allow   bool
+		name    string
+		wantErr error
+		want    []string
+	}{
+		{allow: true, name: "player1", want: []string{"player2", "player1"}},
+		{allow: true, name: "player1", wantErr: ErrPlayerAlreadyAllowed, want: []string{"player2", "player1"}},
+		{allow: true, name: "ghost", wantErr: ErrPlayerNotFound, want: []string{"player2", "player1"}},
+		{allow: false, name: "player2", wantErr: ErrPlayerNotInAllowlist, want: []string{"player1"}},
+		{allow: false, name: "player1", want: []string{}},
+	}
+
+	for _, step := range steps {
+		var err error
+		if step.allow {
+			err = server.AllowPlayer(&api.PlayerInfo{Name: ref(step.name)})
+		} else {
+			err = server.DisallowPlayer(&api.PlayerInfo{Name: ref(step.name)})
+		}
+		if err != step.wantErr {
+			t.Errorf("%s: expected error `%v`, got `%v`", step.name, step.wantErr, err)
+		}
+
+		names := make([]string, 0)
+		for _, p := range *server.allowlist {
+			names = append(names, *p.Name)
+		}
+		if !reflect.DeepEqual(step.want, names) {
+			t.Errorf("%s: expected allowlist `%v`, got `%v`", step.name, step.want, names)
+		}
+	}
+}
+
+func TestUpdatePropertiesWithAttachedConsole(t *testing.T) {
+	t.Parallel()
+
+	server := JavaMinecraftServer{
+		console:    attachFakeConsole(t, fakeVanillaConsole),
+		properties: NewServerProperties(),
+	}
+
+	props := api.ServerProperties{
+		Difficulty: ref(api.Hard),
+		Gamemode:   ref(api.Hardcore),
+		LevelSeed:  ref("1234"),
+		MOTD:       ref("A Minecraft Server"),
+		ServerPort: ref(25566),
+		Whitelist:  ref(true),
+	}
+	result, err := server.UpdateProperties(&props)
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+
+	want := api.PropertiesUpdate{
+		Changed:        []string{"difficulty", "gamemode", "levelSeed", "serverPort", "whitelist"},
+		Applied:        []string{"difficulty", "whitelist"},
+		PendingRestart: []string{"gamemode", "levelSeed", "serverPort"},
+		Ignored:        []string{},
+	}
+	if !reflect.DeepEqual(want, *result) {
+		t.Fatalf("expected result `%+v`, got `%+v`", want, *result)
+	}
+	if *server.properties.Difficulty != api.Hard || *server.properties.ServerPort != 25566 || *server.properties.ViewDistance != 10 {
+		t.Fatalf("expected the properties to be updated, got `%+v`", *server.properties)
+	}
+}
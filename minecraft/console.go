@@ -2,18 +2,65 @@ package minecraft
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raian621/go-mcsc/api"
 )
 
+const (
+	// DefaultConsoleHistorySize is how many lines of output a Console keeps
+	// in its history.
+	DefaultConsoleHistorySize = 1000
+
+	// consoleSubscriberBuffer is how many lines a console subscriber can fall
+	// behind by before lines start getting dropped for it.
+	consoleSubscriberBuffer = 256
+
+	// CommandTimeout is how long the server controller waits for the
+	// Minecraft server to respond to the commands it sends.
+	CommandTimeout = 10 * time.Second
+
+	// commandQuietPeriod is how long Execute waits for more output from a
+	// command whose response is unknown before it considers it done.
+	commandQuietPeriod = 250 * time.Millisecond
+)
+
+var ErrConsoleNotAttached = api.ErrConsoleNotAttached
+
+// Console is the Minecraft server console.
+//
+// A Console outlives any single run of the Minecraft server process: the
+// process is attached to the console when it starts and detached when it
+// exits. A Minecraft server the controller didn't launch can be attached over
+// RCON instead. Every line of output goes through the console's history, which
+// keeps the most recent lines, and is then sent to the console's subscribers.
 type Console struct {
-	stdin  *bufio.Writer
-	stdout *bufio.Reader
-	stderr *bufio.Reader
+	mutex       sync.Mutex
+	execMutex   sync.Mutex
+	transport   commandTransport
+	stdout      io.Reader
+	stderr      io.Reader
+	history     consoleHistory
+	subscribers map[chan api.ConsoleLine]struct{}
+}
+
+func NewConsole() *Console {
+	return &Console{
+		history:     newConsoleHistory(DefaultConsoleHistorySize),
+		subscribers: make(map[chan api.ConsoleLine]struct{}),
+	}
 }
 
-func NewConsole(cmd *exec.Cmd) (*Console, error) {
+// Attach connects the console to the standard streams of cmd. Attach must be
+// called before cmd is started.
+func (c *Console) Attach(cmd *exec.Cmd) error {
 	var (
 		stdin  io.WriteCloser
 		stdout io.ReadCloser
@@ -22,24 +69,160 @@ func NewConsole(cmd *exec.Cmd) (*Console, error) {
 	)
 
 	if stdin, err = cmd.StdinPipe(); err != nil {
-		return nil, err
+		return err
 	}
 	if stdout, err = cmd.StdoutPipe(); err != nil {
-		return nil, err
+		return err
 	}
-	if stderr, err = cmd.StdoutPipe(); err != nil {
-		return nil, err
+	if stderr, err = cmd.StderrPipe(); err != nil {
+		return err
 	}
 
-	return &Console{
-		stdin:  bufio.NewWriter(stdin),
-		stdout: bufio.NewReader(stdout),
-		stderr: bufio.NewReader(stderr),
-	}, nil
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.transport = &stdinTransport{stdin: bufio.NewWriter(stdin)}
+	c.stdout = stdout
+	c.stderr = stderr
+
+	return nil
+}
+
+// AttachRCON connects the console to a Minecraft server through client. The
+// responses to commands sent over RCON are written to the console as output.
+// If the connection is lost while running a command, the console is detached
+// and lost is called with the error in a goroutine of its own.
+func (c *Console) AttachRCON(client *RCONClient, lost func(err error)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.transport = &rconTransport{client: client, console: c, lost: lost}
+}
+
+// Attached reports whether a Minecraft server is attached to the console. It
+// is safe to call on a nil Console.
+func (c *Console) Attached() bool {
+	if c == nil {
+		return false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.transport != nil
 }
 
-func (c Console) SendCommand(cmd string) error {
-	_, err := c.stdin.WriteString(
+// AttachedOverRCON reports whether a Minecraft server is attached to the
+// console over RCON. It is safe to call on a nil Console.
+func (c *Console) AttachedOverRCON() bool {
+	if c == nil {
+		return false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	_, ok := c.transport.(*rconTransport)
+
+	return ok
+}
+
+// Detach disconnects the console from the attached Minecraft server.
+func (c *Console) Detach() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.transport != nil {
+		_ = c.transport.close()
+	}
+	c.transport = nil
+	c.stdout = nil
+	c.stderr = nil
+}
+
+// detachTransport detaches the console from the Minecraft server attached
+// through t, unless another one has been attached since.
+func (c *Console) detachTransport(t commandTransport) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.transport != t {
+		return
+	}
+	_ = t.close()
+	c.transport = nil
+}
+
+// Pump reads the output of the attached process until both its standard
+// output and standard error are closed, publishing every line to the
+// console's history and subscribers. handle is called with every line once it
+// has been published.
+func (c *Console) Pump(handle func(line api.ConsoleLine)) {
+	c.mutex.Lock()
+	stdout, stderr := c.stdout, c.stderr
+	c.mutex.Unlock()
+
+	var wg sync.WaitGroup
+	pump := func(r io.Reader, stream api.ConsoleLineStream) {
+		defer wg.Done()
+
+		reader := bufio.NewReader(r)
+		for {
+			text, err := reader.ReadString('\n')
+			if len(text) > 0 {
+				line := c.publish(stream, strings.TrimRight(text, "\r\n"))
+				if handle != nil {
+					handle(line)
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	if stdout != nil {
+		wg.Add(1)
+		go pump(stdout, api.Stdout)
+	}
+	if stderr != nil {
+		wg.Add(1)
+		go pump(stderr, api.Stderr)
+	}
+
+	wg.Wait()
+}
+
+func (c *Console) SendCommand(cmd string) error {
+	c.mutex.Lock()
+	transport := c.transport
+	c.mutex.Unlock()
+
+	if transport == nil {
+		return ErrConsoleNotAttached
+	}
+
+	return transport.send(cmd)
+}
+
+// commandTransport carries commands to the attached Minecraft server.
+type commandTransport interface {
+	send(cmd string) error
+	close() error
+}
+
+// stdinTransport writes commands to the standard input of the Minecraft
+// server process.
+type stdinTransport struct {
+	mutex sync.Mutex
+	stdin *bufio.Writer
+}
+
+func (t *stdinTransport) send(cmd string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	_, err := t.stdin.WriteString(
 		fmt.Sprintf("%s\r\n", cmd),
 	)
 
@@ -47,13 +230,275 @@ func (c Console) SendCommand(cmd string) error {
 		return err
 	}
 
-	return c.stdin.Flush()
+	return t.stdin.Flush()
 }
 
-func (c Console) ReadLine() (string, error) {
-	return c.stdout.ReadString('\n')
+// close is a no-op, the standard input of the process is closed when the
+// process exits.
+func (t *stdinTransport) close() error { return nil }
+
+// rconTransport runs commands over RCON and writes their responses to the
+// console.
+type rconTransport struct {
+	client  *RCONClient
+	console *Console
+	lost    func(err error)
+}
+
+func (t *rconTransport) send(cmd string) error {
+	// slash prefixes are only understood by the console
+	response, err := t.client.Command(strings.TrimPrefix(cmd, "/"))
+	if isConnectionLost(err) {
+		t.console.detachTransport(t)
+		if t.lost != nil {
+			go t.lost(err)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, text := range strings.Split(response, "\n") {
+		if len(text) > 0 {
+			t.console.publish(api.Rcon, text)
+		}
+	}
+
+	return nil
 }
 
-func (c Console) ReadError() (string, error) {
-	return c.stderr.ReadString('\n')
+func (t *rconTransport) close() error { return t.client.Close() }
+
+// Execute sends cmd to the Minecraft server console and waits for the server
+// to respond to it, returning the messages logged in the meantime without
+// their log prefixes.
+//
+// If the response of the vanilla Minecraft server to the command is known,
+// Execute returns as soon as it is logged, along with an error if the
+// response means the command failed. Otherwise Execute returns once the
+// server has stopped logging messages for a short while.
+func (c *Console) Execute(ctx context.Context, cmd string) ([]string, error) {
+	// commands are executed one at a time so their responses don't get mixed
+	// up with each other
+	c.execMutex.Lock()
+	defer c.execMutex.Unlock()
+
+	lines, cancel := c.Subscribe()
+	defer cancel()
+
+	if err := c.SendCommand(cmd); err != nil {
+		return nil, err
+	}
+
+	responses := responsesFor(cmd)
+	output := make([]string, 0)
+
+	var quiet <-chan time.Time
+	if responses == nil {
+		quiet = time.After(4 * commandQuietPeriod)
+	}
+
+	for {
+		select {
+		case line := <-lines:
+			message := consoleMessage(line.Text)
+			output = append(output, message)
+
+			if matched, err := matchResponse(responses, message); matched {
+				return output, err
+			}
+			if responses == nil {
+				quiet = time.After(commandQuietPeriod)
+			}
+		case <-quiet:
+			return output, nil
+		case <-ctx.Done():
+			if responses != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return output, ErrCommandTimeout
+			}
+			return output, ctx.Err()
+		}
+	}
+}
+
+// History returns the lines in the console history with a sequence number
+// greater than since, oldest first. If since is 0, the most recent lines are
+// returned instead. At most limit lines are returned.
+func (c *Console) History(since int64, limit int) []api.ConsoleLine {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.history.lines(since, limit)
+}
+
+// Subscribe returns a channel that receives every line of output written to
+// the console from now on, and a function that cancels the subscription and
+// closes the channel. Lines are dropped for subscribers that fall too far
+// behind; they can be recovered from the console history by their sequence
+// numbers.
+func (c *Console) Subscribe() (<-chan api.ConsoleLine, func()) {
+	ch := make(chan api.ConsoleLine, consoleSubscriberBuffer)
+
+	c.mutex.Lock()
+	c.subscribers[ch] = struct{}{}
+	c.mutex.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			c.mutex.Lock()
+			defer c.mutex.Unlock()
+
+			delete(c.subscribers, ch)
+			close(ch)
+		})
+	}
+
+	return ch, cancel
+}
+
+// publish records a line of output in the console history and sends it to
+// the console's subscribers.
+func (c *Console) publish(stream api.ConsoleLineStream, text string) api.ConsoleLine {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	line := c.history.append(stream, text)
+	for ch := range c.subscribers {
+		select {
+		case ch <- line:
+		default:
+		}
+	}
+
+	return line
+}
+
+// consoleHistory is a bounded ring buffer of console output.
+type consoleHistory struct {
+	buffer  []api.ConsoleLine
+	start   int
+	size    int
+	nextSeq int64
+}
+
+func newConsoleHistory(capacity int) consoleHistory {
+	return consoleHistory{
+		buffer:  make([]api.ConsoleLine, capacity),
+		nextSeq: 1,
+	}
+}
+
+// append adds a line to the history, overwriting the oldest line if the
+// history is full, and returns it with its sequence number and timestamp.
+func (h *consoleHistory) append(stream api.ConsoleLineStream, text string) api.ConsoleLine {
+	line := api.ConsoleLine{
+		Seq:    h.nextSeq,
+		Time:   time.Now(),
+		Stream: stream,
+		Text:   text,
+	}
+	h.nextSeq++
+
+	if len(h.buffer) == 0 {
+		return line
+	}
+
+	if h.size < len(h.buffer) {
+		h.buffer[(h.start+h.size)%len(h.buffer)] = line
+		h.size++
+	} else {
+		h.buffer[h.start] = line
+		h.start = (h.start + 1) % len(h.buffer)
+	}
+
+	return line
+}
+
+func (h *consoleHistory) at(i int) api.ConsoleLine {
+	return h.buffer[(h.start+i)%len(h.buffer)]
+}
+
+func (h *consoleHistory) lines(since int64, limit int) []api.ConsoleLine {
+	if limit <= 0 || limit > h.size {
+		limit = h.size
+	}
+
+	// index of the first line to return
+	first := h.size - limit
+	if since > 0 {
+		first = 0
+		if h.size > 0 {
+			// sequence numbers in the buffer are contiguous
+			first = int(since - h.at(0).Seq + 1)
+		}
+		if first < 0 {
+			first = 0
+		}
+	}
+
+	last := first + limit
+	if last > h.size {
+		last = h.size
+	}
+	if first >= last {
+		return []api.ConsoleLine{}
+	}
+
+	lines := make([]api.ConsoleLine, 0, last-first)
+	for i := first; i < last; i++ {
+		lines = append(lines, h.at(i))
+	}
+
+	return lines
+}
+
+// SendCommand implements api.MinecraftServerInterface.
+func (m *JavaMinecraftServer) SendCommand(cmd string) error {
+	m.Lock()
+	console := m.console
+	m.Unlock()
+
+	if !console.Attached() {
+		return ErrServerNotRunning
+	}
+
+	return console.SendCommand(cmd)
+}
+
+// execute runs cmd on the Minecraft server console and waits for the server's
+// response to it. m must be locked and its console attached. m is unlocked
+// while waiting, since the server can take up to CommandTimeout to respond, so
+// whatever was read from m before must be read again afterwards.
+func (m *JavaMinecraftServer) execute(cmd string) error {
+	console := m.console
+	m.Unlock()
+	defer m.Lock()
+
+	return executeWithin(console, cmd, CommandTimeout)
+}
+
+// ConsoleHistory implements api.MinecraftServerInterface.
+func (m *JavaMinecraftServer) ConsoleHistory(since int64, limit int) []api.ConsoleLine {
+	m.Lock()
+	console := m.console
+	m.Unlock()
+
+	if console == nil {
+		return []api.ConsoleLine{}
+	}
+
+	return console.History(since, limit)
+}
+
+// SubscribeConsole implements api.MinecraftServerInterface.
+func (m *JavaMinecraftServer) SubscribeConsole() (<-chan api.ConsoleLine, func()) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.console == nil {
+		m.console = NewConsole()
+	}
+
+	return m.console.Subscribe()
 }
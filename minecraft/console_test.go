@@ -0,0 +1,148 @@
+package minecraft
+
+import (
+	"os/exec"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+func receiveLine(t *testing.T, lines <-chan api.ConsoleLine) api.ConsoleLine {
+	t.Helper()
+
+	select {
+	case line, ok := <-lines:
+		if !ok {
+			t.Fatal("console subscription closed unexpectedly")
+		}
+		return line
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for console line")
+	}
+
+	return api.ConsoleLine{}
+}
+
+func TestConsoleFanOut(t *testing.T) {
+	t.Parallel()
+
+	console := NewConsole()
+	if console.Attached() {
+		t.Fatal("expected console not to be attached")
+	}
+	if err := console.SendCommand("list"); err != ErrConsoleNotAttached {
+		t.Fatalf("expected error `%v`, got `%v`", ErrConsoleNotAttached, err)
+	}
+
+	// echo every command back on stdout and stderr
+	cmd := exec.Command("sh", "-c", `while read line; do echo "out $line"; echo "err $line" >&2; done`)
+	if err := console.Attach(cmd); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	first, cancelFirst := console.Subscribe()
+	second, cancelSecond := console.Subscribe()
+	defer cancelSecond()
+
+	pumped := make(chan struct{})
+	go func() {
+		console.Pump(nil)
+		close(pumped)
+	}()
+
+	if err := console.SendCommand("list"); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+
+	for _, lines := range []<-chan api.ConsoleLine{first, second} {
+		got := map[api.ConsoleLineStream]string{}
+		for i := 0; i < 2; i++ {
+			line := receiveLine(t, lines)
+			got[line.Stream] = line.Text
+		}
+		if got[api.Stdout] != "out list" {
+			t.Errorf("expected stdout line `out list`, got `%s`", got[api.Stdout])
+		}
+		if got[api.Stderr] != "err list" {
+			t.Errorf("expected stderr line `err list`, got `%s`", got[api.Stderr])
+		}
+	}
+
+	cancelFirst()
+	if _, ok := <-first; ok {
+		t.Error("expected cancelled subscription to be closed")
+	}
+	// cancelling twice should be harmless
+	cancelFirst()
+
+	if err := console.SendCommand("seed"); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	receiveLine(t, second)
+
+	console.Detach()
+	if console.Attached() {
+		t.Fatal("expected console not to be attached")
+	}
+	_ = cmd.Process.Kill()
+	<-pumped
+	_ = cmd.Wait()
+}
+
+func TestNilConsoleAttached(t *testing.T) {
+	t.Parallel()
+
+	var console *Console
+	if console.Attached() {
+		t.Fatal("expected nil console not to be attached")
+	}
+}
+
+func TestConsoleHistory(t *testing.T) {
+	t.Parallel()
+
+	history := newConsoleHistory(3)
+	if lines := history.lines(0, 10); len(lines) != 0 {
+		t.Fatalf("expected no lines, got `%v`", lines)
+	}
+
+	for _, text := range []string{"one", "two", "three", "four", "five"} {
+		history.append(api.Stdout, text)
+	}
+
+	testCases := []struct {
+		name     string
+		since    int64
+		limit    int
+		wantSeqs []int64
+	}{
+		{name: "everything", since: 0, limit: 0, wantSeqs: []int64{3, 4, 5}},
+		{name: "last lines", since: 0, limit: 2, wantSeqs: []int64{4, 5}},
+		{name: "since evicted line", since: 1, limit: 0, wantSeqs: []int64{3, 4, 5}},
+		{name: "since", since: 3, limit: 0, wantSeqs: []int64{4, 5}},
+		{name: "since with limit", since: 3, limit: 1, wantSeqs: []int64{4}},
+		{name: "since latest line", since: 5, limit: 0, wantSeqs: []int64{}},
+		{name: "since future line", since: 10, limit: 0, wantSeqs: []int64{}},
+	}
+
+	for _, tc := range testCases {
+		lines := history.lines(tc.since, tc.limit)
+		gotSeqs := make([]int64, 0, len(lines))
+		for _, line := range lines {
+			gotSeqs = append(gotSeqs, line.Seq)
+		}
+
+		if !reflect.DeepEqual(tc.wantSeqs, gotSeqs) {
+			t.Errorf("%s: expected lines `%v`, got `%v`", tc.name, tc.wantSeqs, gotSeqs)
+		}
+	}
+
+	if lines := history.lines(4, 0); lines[0].Text != "five" {
+		t.Errorf("expected line `five`, got `%s`", lines[0].Text)
+	}
+}
@@ -0,0 +1,75 @@
+package minecraft
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		expr    string
+		wantErr string
+	}{
+		{expr: "0 */6 * * *"},
+		{expr: "30 4 1,15 * mon-fri"},
+		{expr: "0 0 * JAN-MAR 7"},
+		{expr: "5/15 * * * *"},
+		{expr: "@daily"},
+		{expr: "0 0 * *", wantErr: "must have 5 fields, got 4"},
+		{expr: "60 * * * *", wantErr: "has `60` in the minute field, which must be between 0 and 59"},
+		{expr: "* * 0 * *", wantErr: "has `0` in the day of month field, which must be between 1 and 31"},
+		{expr: "*/0 * * * *", wantErr: "has an invalid step `0` in the minute field"},
+		{expr: "* 10-2 * * *", wantErr: "has an empty range `10-2` in the hour field"},
+		{expr: "* * * foo *", wantErr: "has `foo` in the month field, which must be between 1 and 12"},
+		{expr: "@often", wantErr: "must have 5 fields, got 1"},
+	}
+
+	for _, tc := range testCases {
+		_, err := parseCron(tc.expr)
+		if len(tc.wantErr) == 0 && err != nil {
+			t.Errorf("expected `%s` to parse, got error `%v`", tc.expr, err)
+		}
+		if len(tc.wantErr) > 0 && (err == nil || err.Error() != tc.wantErr) {
+			t.Errorf("expected error `%s` parsing `%s`, got `%v`", tc.wantErr, tc.expr, err)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	t.Parallel()
+
+	// a Wednesday
+	from := time.Date(2026, 10, 14, 10, 17, 30, 0, time.UTC)
+
+	testCases := []struct {
+		expr string
+		want time.Time
+	}{
+		{expr: "* * * * *", want: time.Date(2026, 10, 14, 10, 18, 0, 0, time.UTC)},
+		{expr: "0 */6 * * *", want: time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)},
+		{expr: "15 10 * * *", want: time.Date(2026, 10, 15, 10, 15, 0, 0, time.UTC)},
+		{expr: "@hourly", want: time.Date(2026, 10, 14, 11, 0, 0, 0, time.UTC)},
+		{expr: "@weekly", want: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 3 * * mon-fri", want: time.Date(2026, 10, 15, 3, 0, 0, 0, time.UTC)},
+		{expr: "0 0 1 * *", want: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 1 jan *", want: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// either day field matches when both are restricted
+		{expr: "0 0 20 * 5", want: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)},
+		// both must match when either starts with `*`
+		{expr: "0 0 */2 * 5", want: time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 29 2 *", want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 30 2 *", want: time.Time{}},
+	}
+
+	for _, tc := range testCases {
+		cron, err := parseCron(tc.expr)
+		if err != nil {
+			t.Fatalf("expected no error, got `%v`", err)
+		}
+		if got := cron.next(from); !got.Equal(tc.want) {
+			t.Errorf("expected `%s` to be next due at %v, got %v", tc.expr, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package minecraft
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+var (
+	ErrChecksumMismatch = api.ErrChecksumMismatch
+	ErrUnknownChecksum  = errors.New("unknown checksum algorithm")
+	ErrDownloadFailed   = api.ErrDownloadFailed
+)
+
+// DownloadProgress is called as a server jar is downloaded with the number of
+// bytes downloaded so far and the size of the jar, which is -1 if unknown.
+type DownloadProgress func(version string, downloaded, total int64)
+
+// JarCache keeps Minecraft server jars in a directory as
+// `server-<version>.jar`, downloading them as they are needed. A JarCache can
+// be shared by Minecraft servers, which each get the jars they run installed
+// in their server directories.
+type JarCache struct {
+	Client   *http.Client
+	Progress DownloadProgress
+
+	dir   string
+	mutex sync.Mutex
+}
+
+func NewJarCache(dir string) *JarCache {
+	return &JarCache{
+		Client:   http.DefaultClient,
+		Progress: logDownloadProgress(),
+		dir:      dir,
+	}
+}
+
+// JarName returns the name of the server jar of version.
+func JarName(version string) string {
+	return fmt.Sprintf("server-%s.jar", version)
+}
+
+// Path returns the path of the server jar of version in the cache.
+func (c *JarCache) Path(version string) string {
+	return path.Join(c.dir, JarName(version))
+}
+
+// Ensure makes sure the server jar of version is in the cache and matches the
+// checksum in info, downloading it from the link in info if it isn't. The jar
+// is written to the cache atomically, so the cache never contains a partially
+// downloaded jar. Ensure returns the path of the jar.
+func (c *JarCache) Ensure(ctx context.Context, version string, info VersionInfo) (string, error) {
+	// jars are downloaded one at a time so the same jar isn't downloaded twice
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.ensure(ctx, version, info)
+}
+
+// Install makes sure the server jar of version is in the cache, like Ensure,
+// and installs it in dir. The jar in dir is a hard link to the cached jar, or
+// a copy of it if it can't be linked, like when dir is on another file system.
+func (c *JarCache) Install(ctx context.Context, version string, info VersionInfo, dir string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	jarPath, err := c.ensure(ctx, version, info)
+	if err != nil {
+		return err
+	}
+
+	return linkJar(jarPath, path.Join(dir, JarName(version)))
+}
+
+// ensure does the work of Ensure. c must be locked.
+func (c *JarCache) ensure(ctx context.Context, version string, info VersionInfo) (string, error) {
+	jarPath := c.Path(version)
+
+	if ok, err := c.verify(jarPath, info.checksum()); err != nil {
+		return "", err
+	} else if ok {
+		return jarPath, nil
+	}
+
+	if err := c.download(ctx, version, info, jarPath); err != nil {
+		return "", err
+	}
+
+	return jarPath, nil
+}
+
+// verify reports whether the file at jarPath exists and matches sum.
+func (c *JarCache) verify(jarPath, sum string) (bool, error) {
+	file, err := os.Open(jarPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	defer closeFile(file)
+
+	h, err := newChecksumHash(sum)
+	if err != nil {
+		return false, err
+	}
+	if _, err := io.Copy(h, file); err != nil {
+		return false, err
+	}
+
+	if !checksumMatches(h, sum) {
+		log.Printf("checksum of %s does not match, downloading it again", jarPath)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (c *JarCache) download(ctx context.Context, version string, info VersionInfo, jarPath string) error {
+	h, err := newChecksumHash(info.checksum())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, info.Link, nil)
+	if err != nil {
+		return err
+	}
+	res, err := c.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrDownloadFailed, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s responded with %s", ErrDownloadFailed, info.Link, res.Status)
+	}
+
+	if err := os.MkdirAll(c.dir, os.ModePerm); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, JarName(version)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// does nothing once the jar has been renamed
+		_ = os.Remove(tmp.Name())
+	}()
+	defer closeFile(tmp)
+
+	progress := &progressWriter{
+		version:  version,
+		total:    res.ContentLength,
+		progress: c.Progress,
+	}
+	if _, err := io.Copy(io.MultiWriter(tmp, h, progress), res.Body); err != nil {
+		return err
+	}
+
+	if !checksumMatches(h, info.checksum()) {
+		return fmt.Errorf("%w: %s", ErrChecksumMismatch, info.Link)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), jarPath)
+}
+
+// linkJar links the jar at dst to the jar at src, replacing dst unless it's
+// the same file already. If the jar can't be linked, it's copied instead.
+func linkJar(src, dst string) error {
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if dstInfo, err := os.Stat(dst); err == nil && os.SameFile(srcInfo, dstInfo) {
+		return nil
+	}
+
+	tmp := dst + ".tmp"
+	_ = os.Remove(tmp)
+	if err := os.Link(src, tmp); err != nil {
+		return api.WriteAtomic(dst, srcInfo.Mode().Perm(), func(file io.Writer) error {
+			in, err := os.Open(src)
+			if err != nil {
+				return err
+			}
+			defer closeFile(in)
+
+			_, err = io.Copy(file, in)
+
+			return err
+		})
+	}
+
+	return os.Rename(tmp, dst)
+}
+
+// newChecksumHash returns the hash sum was computed with, going by its
+// length. An empty sum can't be verified.
+func newChecksumHash(sum string) (hash.Hash, error) {
+	switch len(sum) {
+	case hex.EncodedLen(md5.Size):
+		return md5.New(), nil
+	case hex.EncodedLen(sha1.Size):
+		return sha1.New(), nil
+	case hex.EncodedLen(sha256.Size):
+		return sha256.New(), nil
+	}
+
+	return nil, ErrUnknownChecksum
+}
+
+func checksumMatches(h hash.Hash, sum string) bool {
+	return hex.EncodeToString(h.Sum(nil)) == strings.ToLower(sum)
+}
+
+// progressWriter reports the progress of a download as it's written.
+type progressWriter struct {
+	version    string
+	downloaded int64
+	total      int64
+	progress   DownloadProgress
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.downloaded += int64(len(p))
+	if w.progress != nil {
+		w.progress(w.version, w.downloaded, w.total)
+	}
+
+	return len(p), nil
+}
+
+// logDownloadProgress returns a DownloadProgress that logs every 10% of a
+// download.
+func logDownloadProgress() DownloadProgress {
+	var mutex sync.Mutex
+	logged := make(map[string]int64)
+
+	return func(version string, downloaded, total int64) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if total <= 0 {
+			// log every 10MB if the size is unknown
+			if step := downloaded / (10 << 20); step > logged[version] {
+				logged[version] = step
+				log.Printf("downloading server-%s.jar: %d MB", version, downloaded>>20)
+			}
+			return
+		}
+
+		step := downloaded * 10 / total
+		if step > logged[version] || downloaded == total {
+			logged[version] = step
+			log.Printf("downloading server-%s.jar: %d%%", version, downloaded*100/total)
+		}
+		if downloaded >= total {
+			delete(logged, version)
+		}
+	}
+}
+
+// ensureJar makes sure the server jar of version is in the server directory,
+// installing it from the jar cache the server shares with the others in its
+// registry. Without a registry, the server directory is the jar cache.
+func (m *JavaMinecraftServer) ensureJar(ctx context.Context, version string) error {
+	m.Lock()
+	if m.versions == nil {
+		m.Unlock()
+		return ErrNilConfig
+	}
+	info, ok := m.versions[version]
+	if !ok {
+		m.Unlock()
+		return ErrVersionUnsupported
+	}
+	if m.jars == nil {
+		m.jars = NewJarCache(m.serverDir())
+	}
+	jars := m.jars
+	serverDir := m.serverDir()
+	m.Unlock()
+
+	return jars.Install(ctx, version, info, serverDir)
+}
@@ -0,0 +1,186 @@
+package minecraft
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"sync/atomic"
+	"testing"
+)
+
+func TestJarCache(t *testing.T) {
+	t.Parallel()
+
+	jar := []byte("fake server jar")
+	sum := sha1.Sum(jar)
+
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/server.jar" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(jar)
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	cache := NewJarCache(dir)
+
+	var lastDownloaded, lastTotal int64
+	cache.Progress = func(version string, downloaded, total int64) {
+		lastDownloaded, lastTotal = downloaded, total
+	}
+
+	info := VersionInfo{Link: ts.URL + "/server.jar", Sum: hex.EncodeToString(sum[:])}
+
+	jarPath, err := cache.Ensure(context.Background(), "1.20.6", info)
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if jarPath != path.Join(dir, "server-1.20.6.jar") {
+		t.Fatalf("expected jar path `%s`, got `%s`", path.Join(dir, "server-1.20.6.jar"), jarPath)
+	}
+	if got, _ := os.ReadFile(jarPath); string(got) != string(jar) {
+		t.Fatalf("expected jar contents `%s`, got `%s`", jar, got)
+	}
+	if lastDownloaded != int64(len(jar)) || lastTotal != int64(len(jar)) {
+		t.Fatalf("expected progress %d/%d, got %d/%d", len(jar), len(jar), lastDownloaded, lastTotal)
+	}
+
+	// the cached jar is used
+	if _, err := cache.Ensure(context.Background(), "1.20.6", info); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Fatalf("expected 1 request, got %d", n)
+	}
+
+	// a corrupted jar is downloaded again
+	if err := os.WriteFile(jarPath, []byte("corrupted"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Ensure(context.Background(), "1.20.6", info); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Fatalf("expected 2 requests, got %d", n)
+	}
+
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	testCases := []struct {
+		name    string
+		version string
+		info    VersionInfo
+		wantErr error
+	}{
+		{
+			name:    "checksum mismatch",
+			version: "1.20.5",
+			info:    VersionInfo{Link: ts.URL + "/server.jar", Sum: "5f078323c2d661b8d9773c8242d912c3"},
+			wantErr: ErrChecksumMismatch,
+		},
+		{
+			name:    "not found",
+			version: "1.20.4",
+			info:    VersionInfo{Link: ts.URL + "/missing.jar", Sum: hex.EncodeToString(sum[:])},
+			wantErr: ErrDownloadFailed,
+		},
+		{
+			name:    "unreachable",
+			version: "1.20.2",
+			info:    VersionInfo{Link: unreachable.URL + "/server.jar", Sum: hex.EncodeToString(sum[:])},
+			wantErr: ErrDownloadFailed,
+		},
+		{
+			name:    "unknown checksum",
+			version: "1.20.3",
+			info:    VersionInfo{Link: ts.URL + "/server.jar", Sum: "abc"},
+			wantErr: ErrUnknownChecksum,
+		},
+	}
+
+	for _, tc := range testCases {
+		if _, err := cache.Ensure(context.Background(), tc.version, tc.info); !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: expected error `%v`, got `%v`", tc.name, tc.wantErr, err)
+		}
+	}
+
+	// failed downloads leave nothing behind
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected only the downloaded jar in the cache, got %d files", len(entries))
+	}
+}
+
+func TestJarCacheInstall(t *testing.T) {
+	t.Parallel()
+
+	jar := []byte("fake server jar")
+	sum := sha1.Sum(jar)
+
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write(jar)
+	}))
+	defer ts.Close()
+
+	cache := NewJarCache(t.TempDir())
+	cache.Progress = nil
+	info := VersionInfo{Link: ts.URL + "/server.jar", Sum: hex.EncodeToString(sum[:])}
+
+	// servers share the cached jar
+	serverDirs := []string{t.TempDir(), t.TempDir()}
+	for _, dir := range serverDirs {
+		if err := cache.Install(context.Background(), "1.20.6", info, dir); err != nil {
+			t.Fatalf("expected no error, got `%v`", err)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Fatalf("expected 1 request, got %d", n)
+	}
+
+	cached, err := os.Stat(cache.Path("1.20.6"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range serverDirs {
+		installed, err := os.Stat(path.Join(dir, "server-1.20.6.jar"))
+		if err != nil {
+			t.Fatalf("expected no error, got `%v`", err)
+		}
+		if !os.SameFile(cached, installed) {
+			t.Fatalf("expected the jar in %s to be linked to the cached jar", dir)
+		}
+	}
+
+	// a jar that isn't the cached one is replaced
+	installed := path.Join(serverDirs[0], "server-1.20.6.jar")
+	if err := os.Remove(installed); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(installed, []byte("stale"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Install(context.Background(), "1.20.6", info, serverDirs[0]); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if got, _ := os.ReadFile(installed); string(got) != string(jar) {
+		t.Fatalf("expected jar contents `%s`, got `%s`", jar, got)
+	}
+	if entries, _ := os.ReadDir(serverDirs[0]); len(entries) != 1 {
+		t.Fatalf("expected only the jar in the server directory, got %d files", len(entries))
+	}
+}
@@ -0,0 +1,320 @@
+package minecraft
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+// JavaVersionTimeout is how long a Java runtime gets to report its version.
+const JavaVersionTimeout = 10 * time.Second
+
+var ErrNoJavaRuntime = api.ErrNoJavaRuntime
+
+// DefaultJavaDirs are the directories Java runtimes are usually installed in.
+var DefaultJavaDirs = []string{
+	"/usr/lib/jvm",
+	"/usr/java",
+	"/opt/java",
+	"/Library/Java/JavaVirtualMachines",
+}
+
+var javaVersionPattern = regexp.MustCompile(`version "([^"]+)"`)
+
+// JavaRegistry finds the Java runtimes installed on the host.
+type JavaRegistry struct {
+	// Dirs are searched for Java runtimes, along with `JAVA_HOME` and the
+	// `java` on the PATH. A directory can be a Java home or contain Java
+	// homes, like `/usr/lib/jvm`.
+	Dirs []string
+
+	runtimes []api.JavaRuntime
+	scanned  bool
+	mutex    sync.Mutex
+}
+
+func NewJavaRegistry(dirs ...string) *JavaRegistry {
+	return &JavaRegistry{Dirs: dirs}
+}
+
+// Runtimes returns the Java runtimes on the host, newest first. The host is
+// only scanned the first time Runtimes is called.
+func (r *JavaRegistry) Runtimes() []api.JavaRuntime {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !r.scanned {
+		r.runtimes = r.scan()
+		r.scanned = true
+	}
+
+	return slices.Clone(r.runtimes)
+}
+
+// Lookup returns the Java runtime on the host whose `java` executable is java,
+// an absolute path that may be a symbolic link to it. Lookup can be called on
+// a nil registry, which has no runtimes.
+func (r *JavaRegistry) Lookup(java string) (api.JavaRuntime, bool) {
+	if r == nil || !filepath.IsAbs(java) {
+		return api.JavaRuntime{}, false
+	}
+	resolved, err := filepath.EvalSymlinks(java)
+	if err != nil || !isExecutable(resolved) {
+		return api.JavaRuntime{}, false
+	}
+
+	for _, runtime := range r.Runtimes() {
+		if path, err := filepath.EvalSymlinks(runtime.Path); err == nil && path == resolved {
+			return runtime, true
+		}
+	}
+
+	return api.JavaRuntime{}, false
+}
+
+// Rescan forgets the Java runtimes found so far, so the host is scanned again
+// the next time Runtimes is called.
+func (r *JavaRegistry) Rescan() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.runtimes = nil
+	r.scanned = false
+}
+
+// Select returns the oldest Java runtime with at least the given major
+// version, since old Minecraft versions don't always run on newer Java
+// versions. If majorVersion is 0, the newest runtime is returned.
+func (r *JavaRegistry) Select(majorVersion int) (api.JavaRuntime, error) {
+	runtimes := r.Runtimes()
+	if len(runtimes) == 0 {
+		return api.JavaRuntime{}, ErrNoJavaRuntime
+	}
+	if majorVersion <= 0 {
+		return runtimes[0], nil
+	}
+
+	for i := len(runtimes) - 1; i >= 0; i-- {
+		if runtimes[i].MajorVersion >= majorVersion {
+			return runtimes[i], nil
+		}
+	}
+
+	return api.JavaRuntime{}, fmt.Errorf("%w: java %d or newer is required", ErrNoJavaRuntime, majorVersion)
+}
+
+// scan runs every `java` executable found in the registry's directories,
+// `JAVA_HOME` and the PATH to learn its version. r must be locked.
+func (r *JavaRegistry) scan() []api.JavaRuntime {
+	var candidates []string
+	for _, dir := range r.Dirs {
+		candidates = append(candidates, javaExecutables(dir)...)
+	}
+	if javaHome := os.Getenv("JAVA_HOME"); len(javaHome) > 0 {
+		candidates = append(candidates, javaExecutable(javaHome))
+	}
+	if java, err := exec.LookPath("java"); err == nil {
+		candidates = append(candidates, java)
+	}
+
+	runtimes := make([]api.JavaRuntime, 0, len(candidates))
+	seen := make(map[string]bool)
+
+	for _, candidate := range candidates {
+		// the same runtime is often linked to from several places
+		resolved, err := filepath.EvalSymlinks(candidate)
+		if err != nil || seen[resolved] {
+			continue
+		}
+		seen[resolved] = true
+
+		version, err := javaVersion(candidate)
+		if err != nil {
+			log.Printf("skipping java runtime %s: %v", candidate, err)
+			continue
+		}
+		major, err := javaMajorVersion(version)
+		if err != nil {
+			log.Printf("skipping java runtime %s: %v", candidate, err)
+			continue
+		}
+
+		runtimes = append(runtimes, api.JavaRuntime{
+			Path:         candidate,
+			Version:      version,
+			MajorVersion: major,
+		})
+	}
+
+	slices.SortStableFunc(runtimes, func(a, b api.JavaRuntime) int {
+		return b.MajorVersion - a.MajorVersion
+	})
+
+	return runtimes
+}
+
+// javaExecutables returns the `java` executables of dir, if it is a Java
+// home, or of the Java homes in dir.
+func javaExecutables(dir string) []string {
+	if java := javaExecutable(dir); isExecutable(java) {
+		return []string{java}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var executables []string
+	for _, entry := range entries {
+		home := filepath.Join(dir, entry.Name())
+		for _, java := range []string{
+			javaExecutable(home),
+			// macOS keeps the Java home inside a bundle
+			javaExecutable(filepath.Join(home, "Contents", "Home")),
+		} {
+			if isExecutable(java) {
+				executables = append(executables, java)
+				break
+			}
+		}
+	}
+
+	return executables
+}
+
+func javaExecutable(javaHome string) string {
+	return filepath.Join(javaHome, "bin", "java")
+}
+
+func isExecutable(name string) bool {
+	info, err := os.Stat(name)
+	return err == nil && !info.IsDir() && info.Mode().Perm()&0o111 != 0
+}
+
+// javaVersion runs `java -version` and returns the version it reports, e.g.
+//
+//	openjdk version "21.0.2" 2024-01-16
+func javaVersion(java string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), JavaVersionTimeout)
+	defer cancel()
+
+	// the version is printed to stderr
+	output, err := exec.CommandContext(ctx, java, "-version").CombinedOutput()
+	if err != nil {
+		return "", err
+	}
+
+	match := javaVersionPattern.FindSubmatch(output)
+	if match == nil {
+		return "", fmt.Errorf("unrecognized output from %s -version", java)
+	}
+
+	return string(match[1]), nil
+}
+
+// javaMajorVersion returns the major version of a Java version string, which
+// is the second component for Java 8 and older, e.g. `1.8.0_392`.
+func javaMajorVersion(version string) (int, error) {
+	components := strings.FieldsFunc(version, func(r rune) bool {
+		return r == '.' || r == '_' || r == '-' || r == '+'
+	})
+	if len(components) > 1 && components[0] == "1" {
+		components = components[1:]
+	}
+	if len(components) == 0 {
+		return 0, fmt.Errorf("invalid java version `%s`", version)
+	}
+
+	major, err := strconv.Atoi(components[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid java version `%s`", version)
+	}
+
+	return major, nil
+}
+
+// requiredJavaVersion returns the oldest Java major version version of the
+// Minecraft server runs on. The catalog knows it for versions it has
+// refreshed; for others it's worked out from the version.
+func requiredJavaVersion(version string, info VersionInfo) int {
+	if info.JavaVersion > 0 {
+		return info.JavaVersion
+	}
+
+	v := ParseVersion(version)
+	switch {
+	case v.group() != 0:
+		// snapshots are only ever run with the Java version from the catalog
+		return 0
+	case v.Compare(ParseVersion("1.20.5")) >= 0:
+		return 21
+	case v.Compare(ParseVersion("1.18")) >= 0:
+		return 17
+	case v.Compare(ParseVersion("1.17")) >= 0:
+		return 16
+	}
+
+	return 8
+}
+
+// javaPath returns the `java` executable to run version of the Minecraft
+// server with: the configured Java path if there is one, otherwise a
+// compatible Java runtime from the registry. Without a registry the `java` on
+// the PATH is used.
+func (m *JavaMinecraftServer) javaPath(version string) (string, error) {
+	m.Lock()
+	if m.args != nil && m.args.JavaPath != nil && len(*m.args.JavaPath) > 0 {
+		defer m.Unlock()
+		return *m.args.JavaPath, nil
+	}
+	registry := m.java
+	info := m.versions[version]
+	m.Unlock()
+
+	if registry == nil {
+		return "java", nil
+	}
+
+	runtime, err := registry.Select(requiredJavaVersion(version, info))
+	if err != nil {
+		return "", err
+	}
+
+	return runtime.Path, nil
+}
+
+// JavaRuntimes implements api.MinecraftServerInterface.
+func (m *JavaMinecraftServer) JavaRuntimes() *[]api.JavaRuntime {
+	m.Lock()
+	registry := m.java
+	m.Unlock()
+
+	if registry == nil {
+		return &[]api.JavaRuntime{}
+	}
+
+	return ref(registry.Runtimes())
+}
+
+// SetJavaDirs implements api.MinecraftServerInterface.
+//
+// SetJavaDirs sets the directories searched for Java runtimes, in addition to
+// `JAVA_HOME` and the PATH.
+func (m *JavaMinecraftServer) SetJavaDirs(dirs []string) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.java = NewJavaRegistry(dirs...)
+}
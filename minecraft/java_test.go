@@ -0,0 +1,231 @@
+package minecraft
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+// fakeJavaRuntime reports its version like `java -version` does and otherwise
+// behaves like fakeJava.
+func fakeJavaRuntime(version string) string {
+	return fmt.Sprintf(`#!/bin/sh
+if [ "$1" = "-version" ]; then
+	echo 'openjdk version "%s" 2024-01-16' >&2
+	exit 0
+fi
+`, version) + fakeJava[len("#!/bin/sh\n"):]
+}
+
+// installFakeJava installs a fake Java runtime in dir/name, as a Java home.
+func installFakeJava(t *testing.T, dir, name, version string) string {
+	t.Helper()
+
+	binDir := path.Join(dir, name, "bin")
+	if err := os.MkdirAll(binDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	java := path.Join(binDir, "java")
+	if err := os.WriteFile(java, []byte(fakeJavaRuntime(version)), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	return java
+}
+
+func TestJavaMajorVersion(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		version string
+		want    int
+		wantErr bool
+	}{
+		{version: "21.0.2", want: 21},
+		{version: "17", want: 17},
+		{version: "1.8.0_392", want: 8},
+		{version: "22-ea", want: 22},
+		{version: "11.0.22+7", want: 11},
+		{version: "", wantErr: true},
+		{version: "abc", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		got, err := javaMajorVersion(tc.version)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%q: expected error %v, got `%v`", tc.version, tc.wantErr, err)
+		}
+		if got != tc.want {
+			t.Errorf("%q: expected major version %d, got %d", tc.version, tc.want, got)
+		}
+	}
+}
+
+func TestRequiredJavaVersion(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		version string
+		info    VersionInfo
+		want    int
+	}{
+		{version: "1.7.10", want: 8},
+		{version: "1.16.5", want: 8},
+		{version: "1.17.1", want: 16},
+		{version: "1.18", want: 17},
+		{version: "1.20.4", want: 17},
+		{version: "1.20.5-pre1", want: 17},
+		{version: "1.20.5", want: 21},
+		{version: "24w14a", want: 0},
+		{version: "24w14a", info: VersionInfo{JavaVersion: 21}, want: 21},
+	}
+
+	for _, tc := range testCases {
+		if got := requiredJavaVersion(tc.version, tc.info); got != tc.want {
+			t.Errorf("%s: expected java %d, got %d", tc.version, tc.want, got)
+		}
+	}
+}
+
+func TestJavaRegistry(t *testing.T) {
+	jvmDir := t.TempDir()
+	java8 := installFakeJava(t, jvmDir, "java-8-openjdk", "1.8.0_392")
+	java17 := installFakeJava(t, jvmDir, "java-17-openjdk", "17.0.10")
+	java21 := installFakeJava(t, t.TempDir(), "jdk-21", "21.0.2")
+
+	t.Setenv("JAVA_HOME", path.Dir(path.Dir(java21)))
+	// the same runtime on the PATH isn't listed twice
+	t.Setenv("PATH", path.Dir(java17))
+
+	registry := NewJavaRegistry(jvmDir, path.Join(jvmDir, "missing"))
+
+	want := []api.JavaRuntime{
+		{Path: java21, Version: "21.0.2", MajorVersion: 21},
+		{Path: java17, Version: "17.0.10", MajorVersion: 17},
+		{Path: java8, Version: "1.8.0_392", MajorVersion: 8},
+	}
+	if got := registry.Runtimes(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected runtimes `%+v`, got `%+v`", want, got)
+	}
+
+	testCases := []struct {
+		majorVersion int
+		want         string
+		wantErr      error
+	}{
+		{majorVersion: 8, want: java8},
+		{majorVersion: 16, want: java17},
+		{majorVersion: 21, want: java21},
+		{majorVersion: 0, want: java21},
+		{majorVersion: 25, wantErr: ErrNoJavaRuntime},
+	}
+
+	for _, tc := range testCases {
+		runtime, err := registry.Select(tc.majorVersion)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("java %d: expected error `%v`, got `%v`", tc.majorVersion, tc.wantErr, err)
+		}
+		if runtime.Path != tc.want {
+			t.Errorf("java %d: expected `%s`, got `%s`", tc.majorVersion, tc.want, runtime.Path)
+		}
+	}
+
+	t.Setenv("JAVA_HOME", "")
+	t.Setenv("PATH", t.TempDir())
+	if _, err := NewJavaRegistry().Select(8); !errors.Is(err, ErrNoJavaRuntime) {
+		t.Errorf("expected error `%v`, got `%v`", ErrNoJavaRuntime, err)
+	}
+}
+
+func TestStartSelectsJavaRuntime(t *testing.T) {
+	server := newFakeJavaServer(t, fakeJava)
+
+	jvmDir := t.TempDir()
+	installFakeJava(t, jvmDir, "java-8-openjdk", "1.8.0_392")
+	java21 := installFakeJava(t, jvmDir, "java-21-openjdk", "21.0.2")
+	t.Setenv("JAVA_HOME", "")
+
+	server.SetJavaDirs([]string{jvmDir})
+	server.versions[server.config.Version] = VersionInfo{
+		Sum:         server.versions[server.config.Version].Sum,
+		JavaVersion: 21,
+	}
+
+	if java, err := server.javaPath(server.config.Version); err != nil || java != java21 {
+		t.Fatalf("expected java `%s`, got `%s` with error `%v`", java21, java, err)
+	}
+
+	if err := server.Start(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	waitForState(t, server, ProcessRunning)
+	if err := server.Stop(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+
+	// an explicit java path is used as is
+	server.args.JavaPath = ref("/opt/java/bin/java")
+	if java, err := server.javaPath(server.config.Version); err != nil || java != "/opt/java/bin/java" {
+		t.Fatalf("expected java `/opt/java/bin/java`, got `%s` with error `%v`", java, err)
+	}
+
+	// no runtime is new enough
+	server.args.JavaPath = nil
+	server.versions[server.config.Version] = VersionInfo{
+		Sum:         server.versions[server.config.Version].Sum,
+		JavaVersion: 25,
+	}
+	if err := server.Start(); !errors.Is(err, ErrNoJavaRuntime) {
+		t.Fatalf("expected error `%v`, got `%v`", ErrNoJavaRuntime, err)
+	}
+}
+
+func TestSetArgsJavaPath(t *testing.T) {
+	jvmDir := t.TempDir()
+	java21 := installFakeJava(t, jvmDir, "java-21-openjdk", "21.0.2")
+	t.Setenv("JAVA_HOME", "")
+	t.Setenv("PATH", t.TempDir())
+
+	// the runtime linked to from elsewhere is the same runtime
+	link := path.Join(t.TempDir(), "java")
+	if err := os.Symlink(java21, link); err != nil {
+		t.Fatal(err)
+	}
+	other := installFakeJava(t, t.TempDir(), "jdk-17", "17.0.10")
+
+	server := JavaMinecraftServer{}
+	server.SetJavaDirs([]string{jvmDir})
+
+	testCases := []struct {
+		javaPath string
+		wantErr  error
+	}{
+		{javaPath: ""},
+		{javaPath: java21},
+		{javaPath: link},
+		{javaPath: other, wantErr: ErrInvalidArgument},
+		{javaPath: "java", wantErr: ErrInvalidArgument},
+		{javaPath: path.Join(jvmDir, "missing"), wantErr: ErrInvalidArgument},
+	}
+
+	for _, tc := range testCases {
+		args := NewServerArgs()
+		args.JavaPath = ref(tc.javaPath)
+		if err := server.SetArgs(args); !errors.Is(err, tc.wantErr) {
+			t.Errorf("`%s`: expected error `%v`, got `%v`", tc.javaPath, tc.wantErr, err)
+		}
+	}
+
+	// a Java path set in the arguments file already can be kept
+	server.args.JavaPath = ref(other)
+	args := NewServerArgs()
+	args.JavaPath = ref(other)
+	if err := server.SetArgs(args); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+}
@@ -3,14 +3,23 @@ package minecraft
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 
 	"github.com/raian621/go-mcsc/api"
 )
 
-var ErrNotInOps = errors.New("player not in server operator list")
+var (
+	ErrAlreadyOp = api.ErrAlreadyOp
+	ErrNotInOps  = api.ErrNotInOps
+)
 
 // Deop implements api.MinecraftServerInterface.
+//
+// If the Minecraft server process is currently running, the command `/deop
+// <playername>` is sent to the Minecraft server console and the player is
+// removed from the in-memory operator list once the Minecraft server has
+// removed them from its operator list.
 func (m *JavaMinecraftServer) Deop(p *api.PlayerInfo) error {
 	m.Lock()
 	defer m.Unlock()
@@ -19,14 +28,25 @@ func (m *JavaMinecraftServer) Deop(p *api.PlayerInfo) error {
 		return ErrNilConfig
 	}
 
-	idx := -1
-	for i, op := range *m.ops {
-		if op.Name == *p.Name && op.Uuid == *p.Uuid {
-			idx = i
-			break
+	if m.console.Attached() {
+		err := m.execute(fmt.Sprintf("/deop %s", playerName(*p)))
+		if err != nil && !errors.Is(err, ErrNotInOps) {
+			return err
+		}
+		// the list may have been replaced while the server was unlocked
+		if m.ops == nil {
+			return ErrNilConfig
 		}
+
+		// the player isn't a server operator either way
+		if idx := m.opsIndex(*p); idx != -1 {
+			*m.ops = append((*m.ops)[:idx], (*m.ops)[idx+1:]...)
+		}
+
+		return err
 	}
 
+	idx := m.opsIndex(*p)
 	if idx == -1 {
 		return ErrNotInOps
 	}
@@ -36,6 +56,11 @@ func (m *JavaMinecraftServer) Deop(p *api.PlayerInfo) error {
 }
 
 // Op implements api.MinecraftServerInterface.
+//
+// If the Minecraft server process is currently running, the command `/op
+// <playername>` is sent to the Minecraft server console and the player is
+// added to the in-memory operator list once the Minecraft server has made them
+// a server operator.
 func (m *JavaMinecraftServer) Op(op *api.ServerOperator) error {
 	m.Lock()
 	defer m.Unlock()
@@ -44,11 +69,47 @@ func (m *JavaMinecraftServer) Op(op *api.ServerOperator) error {
 		return ErrNilConfig
 	}
 
+	p := api.PlayerInfo{Name: &op.Name, Uuid: &op.Uuid}
+
+	if m.console.Attached() {
+		err := m.execute(fmt.Sprintf("/op %s", playerName(p)))
+		if err != nil && !errors.Is(err, ErrAlreadyOp) {
+			return err
+		}
+		// the list may have been replaced while the server was unlocked
+		if m.ops == nil {
+			return ErrNilConfig
+		}
+
+		// the player is a server operator either way
+		if idx := m.opsIndex(p); idx == -1 {
+			*m.ops = append(*m.ops, *op)
+		}
+
+		return err
+	}
+
+	if idx := m.opsIndex(p); idx != -1 {
+		return ErrAlreadyOp
+	}
 	*m.ops = append(*m.ops, *op)
 
 	return nil
 }
 
+// opsIndex returns the index of p in the in-memory operator list, or -1 if p
+// isn't in it. m must be locked.
+func (m *JavaMinecraftServer) opsIndex(p api.PlayerInfo) int {
+	for i := range *m.ops {
+		op := &(*m.ops)[i]
+		if samePlayer(api.PlayerInfo{Name: &op.Name, Uuid: &op.Uuid}, p) {
+			return i
+		}
+	}
+
+	return -1
+}
+
 // Ops implements api.MinecraftServerInterface.
 func (m *JavaMinecraftServer) Ops() *api.ServerOperatorList {
 	m.Lock()
@@ -59,7 +120,7 @@ func (m *JavaMinecraftServer) Ops() *api.ServerOperatorList {
 	}
 
 	serverOperatorsCpy := make(api.ServerOperatorList, len(*m.ops))
-	copy(*m.ops, serverOperatorsCpy)
+	copy(serverOperatorsCpy, *m.ops)
 
 	return &serverOperatorsCpy
 }
@@ -2,7 +2,9 @@ package minecraft
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/raian621/go-mcsc/api"
@@ -25,6 +27,12 @@ func TestOperatorsGetAndSet(t *testing.T) {
 	if &operators == serverOperators {
 		t.Error("memory addresses for original operators and serverOperators should have been different")
 	}
+
+	operators = append(operators, api.ServerOperator{Name: "steve", Level: 4})
+	server.SetOperators(&operators)
+	if serverOperators := server.Ops(); !reflect.DeepEqual(operators, *serverOperators) {
+		t.Errorf("expected operators `%+v`, got `%+v`", operators, *serverOperators)
+	}
 }
 
 func TestOpAndDeop(t *testing.T) {
@@ -188,3 +196,42 @@ func TestSaveAndLoadOps(t *testing.T) {
 		t.Errorf("expected operator list to be length `%d`, got `%d`", len(ops), len(*server.ops))
 	}
 }
+
+func TestOpWithAttachedConsole(t *testing.T) {
+	t.Parallel()
+
+	// the Minecraft server takes a while to respond
+	server := JavaMinecraftServer{
+		console: attachFakeConsole(t, `
+while read line; do
+	set -- ${line%$(printf '\r')}
+	sleep 1
+	echo "[12:00:00] [Server thread/INFO]: Made $2 a server operator"
+done
+`),
+		ops: ref(make(api.ServerOperatorList, 0)),
+	}
+
+	opped := make(chan error, 1)
+	go func() { opped <- server.Op(&api.ServerOperator{Name: "player1"}) }()
+	time.Sleep(100 * time.Millisecond)
+
+	// the server isn't locked while waiting for the response
+	listed := make(chan struct{})
+	go func() {
+		server.Ops()
+		close(listed)
+	}()
+	select {
+	case <-listed:
+	case err := <-opped:
+		t.Fatalf("expected the operator list to be read while waiting for the response, got error `%v` first", err)
+	}
+
+	if err := <-opped; err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if ops := server.Ops(); len(*ops) != 1 || (*ops)[0].Name != "player1" {
+		t.Fatalf("expected player1 to be a server operator, got `%+v`", *ops)
+	}
+}
@@ -0,0 +1,106 @@
+package minecraft
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+const (
+	// backupSuffix is appended to the path of a config file to get the path
+	// its previous version is kept at.
+	backupSuffix = ".bak"
+	// damagedSuffix is appended to the path of a config file that couldn't be
+	// read to get the path it's set aside at.
+	damagedSuffix = ".damaged"
+
+	configFileMode fs.FileMode = 0o644
+)
+
+// loadJSON loads the JSON config file at filepath with loadFn. If there is no
+// config file, it's created with createFn and saved with saveFn. A config file
+// that's damaged, like one truncated by a crash, is restored from its backup,
+// or created again if the backup is damaged too.
+func loadJSON(
+	loadFn func(file io.Reader) error,
+	saveFn func(file io.Writer) error,
+	createFn func(),
+	filepath string,
+) error {
+	data, err := readJSON(filepath)
+	if errors.Is(err, fs.ErrNotExist) {
+		createFn()
+		return saveJSON(saveFn, filepath)
+	} else if err != nil {
+		return err
+	}
+
+	createFn()
+	return loadFn(bytes.NewReader(data))
+}
+
+// saveJSON saves a config file with saveFn. The file is replaced atomically,
+// and its previous version is kept as a backup.
+func saveJSON(saveFn func(file io.Writer) error, filepath string) error {
+	var buf bytes.Buffer
+	if err := saveFn(&buf); err != nil {
+		return err
+	}
+
+	current, err := os.ReadFile(filepath)
+	if err == nil && bytes.Equal(current, buf.Bytes()) {
+		return nil
+	} else if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	// a damaged file would replace a backup that's still good
+	if err == nil && json.Valid(current) {
+		if err := api.WriteFileAtomic(filepath+backupSuffix, current, configFileMode); err != nil {
+			return err
+		}
+	}
+
+	return api.WriteFileAtomic(filepath, buf.Bytes(), configFileMode)
+}
+
+// readJSON reads the JSON config file at filepath. If it isn't valid JSON,
+// it's set aside and restored from its backup. If the backup isn't valid
+// either, readJSON reports the file as not existing so that it's created
+// again.
+func readJSON(filepath string) ([]byte, error) {
+	data, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+	if json.Valid(data) {
+		return data, nil
+	}
+
+	damaged := filepath + damagedSuffix
+	if err := os.Rename(filepath, damaged); err != nil {
+		return nil, err
+	}
+
+	backup, err := os.ReadFile(filepath + backupSuffix)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err != nil || !json.Valid(backup) {
+		log.Printf("%s is damaged and has no usable backup, creating it again; the damaged file is kept as %s", filepath, damaged)
+		return nil, fmt.Errorf("%s: %w", filepath, fs.ErrNotExist)
+	}
+
+	log.Printf("%s is damaged, restoring it from its backup; the damaged file is kept as %s", filepath, damaged)
+	if err := api.WriteFileAtomic(filepath, backup, configFileMode); err != nil {
+		return nil, err
+	}
+
+	return backup, nil
+}
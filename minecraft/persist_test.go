@@ -0,0 +1,138 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+func TestSaveJSON(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	filepath := path.Join(dir, "ops.json")
+	save := func(v any) func(file io.Writer) error {
+		return func(file io.Writer) error { return json.NewEncoder(file).Encode(v) }
+	}
+
+	steps := []struct {
+		name       string
+		value      any
+		wantFile   string
+		wantBackup string
+	}{
+		{name: "create", value: []string{"steve"}, wantFile: `["steve"]`},
+		{name: "change", value: []string{"alex"}, wantFile: `["alex"]`, wantBackup: `["steve"]`},
+		{name: "unchanged", value: []string{"alex"}, wantFile: `["alex"]`, wantBackup: `["steve"]`},
+		{name: "change again", value: []string{}, wantFile: `[]`, wantBackup: `["alex"]`},
+	}
+
+	// the steps build on each other, so they aren't run in parallel
+	for _, step := range steps {
+		if err := saveJSON(save(step.value), filepath); err != nil {
+			t.Fatalf("%s: expected no error, got `%v`", step.name, err)
+		}
+
+		if data, err := os.ReadFile(filepath); err != nil || string(data) != step.wantFile+"\n" {
+			t.Errorf("%s: expected file `%s`, got `%s` with error `%v`", step.name, step.wantFile, data, err)
+		}
+		data, err := os.ReadFile(filepath + backupSuffix)
+		if len(step.wantBackup) == 0 && !os.IsNotExist(err) {
+			t.Errorf("%s: expected no backup, got `%s` with error `%v`", step.name, data, err)
+		} else if len(step.wantBackup) > 0 && (err != nil || string(data) != step.wantBackup+"\n") {
+			t.Errorf("%s: expected backup `%s`, got `%s` with error `%v`", step.name, step.wantBackup, data, err)
+		}
+	}
+
+	// a damaged file doesn't replace the backup
+	if err := os.WriteFile(filepath, []byte(`["ste`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveJSON(save([]string{"steve"}), filepath); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if data, err := os.ReadFile(filepath + backupSuffix); err != nil || string(data) != "[\"alex\"]\n" {
+		t.Errorf("expected backup `[\"alex\"]`, got `%s` with error `%v`", data, err)
+	}
+
+	// failed saves leave the file alone
+	saveErr := errors.New("disk full")
+	if err := saveJSON(func(file io.Writer) error { return saveErr }, filepath); !errors.Is(err, saveErr) {
+		t.Fatalf("expected error `%v`, got `%v`", saveErr, err)
+	}
+	if data, err := os.ReadFile(filepath); err != nil || string(data) != "[\"steve\"]\n" {
+		t.Errorf("expected file `[\"steve\"]`, got `%s` with error `%v`", data, err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("expected only the file and its backup to be left, got %d files", len(entries))
+	}
+}
+
+func TestLoadJSONRecovery(t *testing.T) {
+	t.Parallel()
+
+	steve := api.ServerOperator{Name: "steve", Level: 4}
+
+	testCases := []struct {
+		name        string
+		file        string
+		backup      string
+		wantOps     api.ServerOperatorList
+		wantDamaged bool
+	}{
+		{name: "valid file", file: `[{"name": "steve", "level": 4}]`, wantOps: api.ServerOperatorList{steve}},
+		{name: "missing file", wantOps: api.ServerOperatorList{}},
+		{name: "truncated file", file: `[{"name": "ste`, backup: `[{"name": "steve", "level": 4}]`, wantOps: api.ServerOperatorList{steve}, wantDamaged: true},
+		{name: "empty file", file: " ", backup: `[{"name": "steve", "level": 4}]`, wantOps: api.ServerOperatorList{steve}, wantDamaged: true},
+		{name: "truncated file without backup", file: `[{"name": "ste`, wantOps: api.ServerOperatorList{}, wantDamaged: true},
+		{name: "truncated file and backup", file: `[{"name": "ste`, backup: `[`, wantOps: api.ServerOperatorList{}, wantDamaged: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filepath := path.Join(t.TempDir(), "ops.json")
+			if len(tc.file) > 0 {
+				if err := os.WriteFile(filepath, []byte(tc.file), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if len(tc.backup) > 0 {
+				if err := os.WriteFile(filepath+backupSuffix, []byte(tc.backup), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			server := JavaMinecraftServer{}
+			if err := loadJSON(server.LoadOperators, server.SaveOperators, server.CreateOperators, filepath); err != nil {
+				t.Fatalf("expected no error, got `%v`", err)
+			}
+			if got := *server.Ops(); !reflect.DeepEqual(tc.wantOps, got) {
+				t.Fatalf("expected operators `%+v`, got `%+v`", tc.wantOps, got)
+			}
+
+			// the recovered file is valid again
+			if data, err := os.ReadFile(filepath); err != nil || !json.Valid(data) {
+				t.Fatalf("expected a valid file, got `%s` with error `%v`", data, err)
+			}
+			if data, err := os.ReadFile(filepath + damagedSuffix); tc.wantDamaged && string(data) != tc.file {
+				t.Fatalf("expected the damaged file to be kept, got `%s` with error `%v`", data, err)
+			} else if !tc.wantDamaged && !os.IsNotExist(err) {
+				t.Fatalf("expected no damaged file, got `%s` with error `%v`", data, err)
+			}
+		})
+	}
+}
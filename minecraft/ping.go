@@ -0,0 +1,310 @@
+package minecraft
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raian621/go-mcsc/api"
+)
+
+// PingTimeout is how long Ping waits on the Minecraft server.
+const PingTimeout = 5 * time.Second
+
+// Server List Ping packet IDs and handshake values, see
+// https://wiki.vg/Server_List_Ping
+const (
+	slpPacketStatus    int32 = 0x00
+	slpPacketPing      int32 = 0x01
+	slpProtocolUnknown int32 = -1
+	slpNextStateStatus int32 = 1
+
+	// slpMaxPacketLength is the longest packet Ping accepts.
+	slpMaxPacketLength = 1 << 21
+)
+
+var ErrMalformedPing = errors.New("malformed server list ping response")
+
+// slpStatus is the status JSON the Minecraft server responds to a Server List
+// Ping with.
+type slpStatus struct {
+	Version struct {
+		Name     string `json:"name"`
+		Protocol int    `json:"protocol"`
+	} `json:"version"`
+	Players struct {
+		Max    int `json:"max"`
+		Online int `json:"online"`
+		Sample []struct {
+			Name string `json:"name"`
+			ID   string `json:"id"`
+		} `json:"sample"`
+	} `json:"players"`
+	Description json.RawMessage `json:"description"`
+}
+
+// chatComponent is a Minecraft text component, used by the Minecraft server
+// for the MOTD.
+type chatComponent struct {
+	Text  string          `json:"text"`
+	Extra []chatComponent `json:"extra"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Text components can also be
+// plain strings.
+func (c *chatComponent) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &c.Text)
+	}
+
+	type component chatComponent
+	return json.Unmarshal(data, (*component)(c))
+}
+
+// String returns the plain text of the component and its children.
+func (c chatComponent) String() string {
+	var sb strings.Builder
+	sb.WriteString(c.Text)
+	for _, extra := range c.Extra {
+		sb.WriteString(extra.String())
+	}
+
+	return sb.String()
+}
+
+// Ping asks the Minecraft server at addr for its status using the Server List
+// Ping protocol.
+func Ping(addr string, timeout time.Duration) (*api.ServerStatus, error) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	r := bufio.NewReader(conn)
+
+	// handshake followed by a status request
+	var handshake bytes.Buffer
+	writeVarInt(&handshake, slpPacketStatus)
+	writeVarInt(&handshake, slpProtocolUnknown)
+	writeString(&handshake, host)
+	_ = binary.Write(&handshake, binary.BigEndian, uint16(port))
+	writeVarInt(&handshake, slpNextStateStatus)
+	if err := writePacket(conn, handshake.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := writePacket(conn, appendVarInt(nil, slpPacketStatus)); err != nil {
+		return nil, err
+	}
+
+	packet, err := readPacket(r, slpPacketStatus)
+	if err != nil {
+		return nil, err
+	}
+	response, err := readString(packet)
+	if err != nil {
+		return nil, err
+	}
+
+	var status slpStatus
+	if err := json.Unmarshal([]byte(response), &status); err != nil {
+		return nil, ErrMalformedPing
+	}
+
+	// measure the latency with a ping, the server echoes the payload back
+	sent := time.Now()
+	ping := appendVarInt(nil, slpPacketPing)
+	ping = binary.BigEndian.AppendUint64(ping, uint64(sent.UnixMilli()))
+	if err := writePacket(conn, ping); err != nil {
+		return nil, err
+	}
+	if _, err := readPacket(r, slpPacketPing); err != nil {
+		return nil, err
+	}
+	latency := int(time.Since(sent).Milliseconds())
+
+	var motd chatComponent
+	if len(status.Description) > 0 {
+		if err := json.Unmarshal(status.Description, &motd); err != nil {
+			return nil, ErrMalformedPing
+		}
+	}
+
+	sample := make([]api.PlayerInfo, 0, len(status.Players.Sample))
+	for _, p := range status.Players.Sample {
+		player := api.PlayerInfo{Name: ref(p.Name)}
+		if id, err := uuid.Parse(p.ID); err == nil {
+			player.Uuid = &id
+		}
+		sample = append(sample, player)
+	}
+
+	return &api.ServerStatus{
+		Online:  true,
+		Motd:    ref(motd.String()),
+		Latency: &latency,
+		Version: &api.ServerStatusVersion{
+			Name:     status.Version.Name,
+			Protocol: status.Version.Protocol,
+		},
+		Players: &api.ServerStatusPlayers{
+			Max:    status.Players.Max,
+			Online: status.Players.Online,
+			Sample: sample,
+		},
+	}, nil
+}
+
+// Status implements api.MinecraftServerInterface.
+//
+// Status reports the state of the Minecraft server process and, if it is
+// running, pings the Minecraft server for its status.
+func (m *JavaMinecraftServer) Status() *api.ServerStatus {
+	m.Lock()
+	state := m.state
+	if state == "" {
+		state = ProcessStopped
+	}
+	var pid int
+	if m.process != nil {
+		pid = m.process.pid()
+	}
+	var addr string
+	if m.properties != nil {
+		addr = pingAddress(m.properties, m.args)
+	}
+	m.Unlock()
+
+	status := &api.ServerStatus{State: api.ServerStatusState(state)}
+	if pid != 0 {
+		status.Pid = &pid
+	}
+
+	if state == ProcessStopped || state == ProcessCrashed || len(addr) == 0 {
+		return status
+	}
+
+	pinged, err := Ping(addr, PingTimeout)
+	if err != nil {
+		log.Println("error pinging minecraft server:", err)
+		return status
+	}
+	pinged.State = status.State
+	pinged.Pid = status.Pid
+
+	return pinged
+}
+
+// pingAddress returns the address the Minecraft server with the given
+// properties and arguments listens on.
+func pingAddress(props *api.ServerProperties, args *api.ServerArguments) string {
+	host := "127.0.0.1"
+	if props.ServerIP != nil && len(*props.ServerIP) > 0 {
+		host = *props.ServerIP
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(listenPort(props, args)))
+}
+
+// writePacket writes data prefixed with its length.
+func writePacket(w io.Writer, data []byte) error {
+	_, err := w.Write(append(appendVarInt(nil, int32(len(data))), data...))
+	return err
+}
+
+// readPacket reads a length prefixed packet and checks that it has the given
+// packet ID. The returned reader reads the rest of the packet.
+func readPacket(r *bufio.Reader, id int32) (*bytes.Reader, error) {
+	length, err := readVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if length < 1 || length > slpMaxPacketLength {
+		return nil, ErrMalformedPing
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	packet := bytes.NewReader(data)
+	if packetID, err := readVarInt(packet); err != nil || packetID != id {
+		return nil, ErrMalformedPing
+	}
+
+	return packet, nil
+}
+
+// appendVarInt appends v to b using the variable length encoding of the
+// Minecraft protocol.
+func appendVarInt(b []byte, v int32) []byte {
+	u := uint32(v)
+	for u >= 0x80 {
+		b = append(b, byte(u)|0x80)
+		u >>= 7
+	}
+
+	return append(b, byte(u))
+}
+
+func writeVarInt(buf *bytes.Buffer, v int32) {
+	buf.Write(appendVarInt(nil, v))
+}
+
+func readVarInt(r io.ByteReader) (int32, error) {
+	var v uint32
+	for i := 0; i < 5; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		v |= uint32(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			return int32(v), nil
+		}
+	}
+
+	return 0, ErrMalformedPing
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	writeVarInt(buf, int32(len(s)))
+	buf.WriteString(s)
+}
+
+func readString(r *bytes.Reader) (string, error) {
+	length, err := readVarInt(r)
+	if err != nil {
+		return "", err
+	}
+	if length < 0 || int(length) > r.Len() {
+		return "", ErrMalformedPing
+	}
+
+	s := make([]byte, length)
+	if _, err := io.ReadFull(r, s); err != nil {
+		return "", err
+	}
+
+	return string(s), nil
+}
@@ -0,0 +1,200 @@
+package minecraft
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/raian621/go-mcsc/api"
+)
+
+// fakeSLPResponder is an in-process stand-in for a Minecraft server that
+// responds to Server List Pings with status.
+func fakeSLPResponder(t *testing.T, status string) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeSLP(conn, status)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func serveFakeSLP(conn net.Conn, status string) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+
+	// handshake
+	if _, err := readPacket(r, slpPacketStatus); err != nil {
+		return
+	}
+	// status request
+	if _, err := readPacket(r, slpPacketStatus); err != nil {
+		return
+	}
+
+	var response bytes.Buffer
+	writeVarInt(&response, slpPacketStatus)
+	writeString(&response, status)
+	if err := writePacket(conn, response.Bytes()); err != nil {
+		return
+	}
+
+	ping, err := readPacket(r, slpPacketPing)
+	if err != nil {
+		return
+	}
+	payload, _ := io.ReadAll(ping)
+	_ = writePacket(conn, append(appendVarInt(nil, slpPacketPing), payload...))
+}
+
+func TestVarInt(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		value int32
+		want  []byte
+	}{
+		{value: 0, want: []byte{0x00}},
+		{value: 127, want: []byte{0x7f}},
+		{value: 128, want: []byte{0x80, 0x01}},
+		{value: 25565, want: []byte{0xdd, 0xc7, 0x01}},
+		{value: -1, want: []byte{0xff, 0xff, 0xff, 0xff, 0x0f}},
+	}
+
+	for _, tc := range testCases {
+		got := appendVarInt(nil, tc.value)
+		if !bytes.Equal(got, tc.want) {
+			t.Errorf("%d: expected encoding `%x`, got `%x`", tc.value, tc.want, got)
+		}
+
+		value, err := readVarInt(bytes.NewReader(got))
+		if err != nil {
+			t.Errorf("%d: expected no error, got `%v`", tc.value, err)
+		}
+		if value != tc.value {
+			t.Errorf("expected value %d, got %d", tc.value, value)
+		}
+	}
+}
+
+func TestPing(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		status   string
+		wantMotd string
+		wantErr  error
+	}{
+		{
+			name: "plain text description",
+			status: `{
+				"version": {"name": "1.20.6", "protocol": 766},
+				"players": {"max": 20, "online": 1, "sample": [{"name": "player1", "id": "7b5c7df5-69c5-44d2-beab-8191f593e2e5"}]},
+				"description": "A Minecraft Server"
+			}`,
+			wantMotd: "A Minecraft Server",
+		},
+		{
+			name: "text component description",
+			status: `{
+				"version": {"name": "1.20.6", "protocol": 766},
+				"players": {"max": 20, "online": 1, "sample": [{"name": "player1", "id": "7b5c7df5-69c5-44d2-beab-8191f593e2e5"}]},
+				"description": {"text": "A ", "extra": [{"text": "Minecraft", "bold": true}, " Server"]}
+			}`,
+			wantMotd: "A Minecraft Server",
+		},
+		{
+			name:    "malformed status",
+			status:  `{"version": `,
+			wantErr: ErrMalformedPing,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			status, err := Ping(fakeSLPResponder(t, tc.status), PingTimeout)
+			if err != tc.wantErr {
+				t.Fatalf("expected error `%v`, got `%v`", tc.wantErr, err)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+
+			want := &api.ServerStatus{
+				Online:  true,
+				Motd:    ref(tc.wantMotd),
+				Latency: status.Latency,
+				Version: &api.ServerStatusVersion{Name: "1.20.6", Protocol: 766},
+				Players: &api.ServerStatusPlayers{
+					Max:    20,
+					Online: 1,
+					Sample: []api.PlayerInfo{{
+						Name: ref("player1"),
+						Uuid: ref(uuid.MustParse("7b5c7df5-69c5-44d2-beab-8191f593e2e5")),
+					}},
+				},
+			}
+			wantJSON, _ := json.Marshal(want)
+			gotJSON, _ := json.Marshal(status)
+			if !bytes.Equal(wantJSON, gotJSON) {
+				t.Fatalf("expected status `%s`, got `%s`", wantJSON, gotJSON)
+			}
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	host, port, _ := net.SplitHostPort(fakeSLPResponder(t, `{
+		"version": {"name": "1.20.6", "protocol": 766},
+		"players": {"max": 20, "online": 0},
+		"description": "A Minecraft Server"
+	}`))
+
+	server := JavaMinecraftServer{}
+	server.CreateProperties()
+	server.CreateArgs()
+	server.properties.ServerIP = ref(host)
+	portNum, _ := strconv.Atoi(port)
+	server.args.Port = &portNum
+
+	// the server isn't pinged while it's stopped
+	status := server.Status()
+	if status.State != api.Stopped || status.Online {
+		t.Fatalf("expected offline stopped server, got `%+v`", status)
+	}
+
+	server.state = ProcessRunning
+	status = server.Status()
+	if status.State != api.Running || !status.Online {
+		t.Fatalf("expected online running server, got `%+v`", status)
+	}
+	if *status.Motd != "A Minecraft Server" {
+		t.Fatalf("expected MOTD `A Minecraft Server`, got `%s`", *status.Motd)
+	}
+}
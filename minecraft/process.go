@@ -0,0 +1,127 @@
+package minecraft
+
+import (
+	"errors"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+// ProcessState describes where the Minecraft server process is in its
+// lifecycle.
+type ProcessState string
+
+const (
+	ProcessStopped  ProcessState = "stopped"
+	ProcessStarting ProcessState = "starting"
+	ProcessRunning  ProcessState = "running"
+	ProcessStopping ProcessState = "stopping"
+	ProcessCrashed  ProcessState = "crashed"
+)
+
+// DefaultStopTimeout is how long Stop waits for the Minecraft server to shut
+// down after sending the `stop` command before killing the process.
+const DefaultStopTimeout = 30 * time.Second
+
+var (
+	ErrServerRunning    = api.ErrServerRunning
+	ErrServerNotRunning = api.ErrServerNotRunning
+)
+
+// process supervises a single run of the Minecraft server process.
+type process struct {
+	cmd     *exec.Cmd
+	console *Console
+	onLine  func(line api.ConsoleLine)
+	onExit  func(err error)
+	done    chan struct{}
+}
+
+// startProcess spawns argv in dir with env and its standard streams attached
+// to console. The output of the process is not consumed until run is called.
+func startProcess(
+	dir string,
+	argv []string,
+	env []string,
+	console *Console,
+	onLine func(line api.ConsoleLine),
+	onExit func(err error),
+) (*process, error) {
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Dir = dir
+	cmd.Env = env
+
+	if err := console.Attach(cmd); err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		console.Detach()
+		return nil, err
+	}
+
+	return &process{
+		cmd:     cmd,
+		console: console,
+		onLine:  onLine,
+		onExit:  onExit,
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// run consumes the output of the process and waits for it to exit in the
+// background. onExit is called before the done channel is closed.
+func (p *process) run() {
+	go func() {
+		// all reads from the pipes must be complete before calling Wait
+		p.console.Pump(p.onLine)
+		err := p.cmd.Wait()
+		p.console.Detach()
+		if p.onExit != nil {
+			p.onExit(err)
+		}
+		close(p.done)
+	}()
+}
+
+// stop asks the Minecraft server to shut down through its console and kills
+// the process if it hasn't exited once timeout has elapsed.
+func (p *process) stop(timeout time.Duration) error {
+	if err := p.console.SendCommand("stop"); err != nil {
+		log.Println("error sending stop command to minecraft server:", err)
+		return p.kill()
+	}
+
+	select {
+	case <-p.done:
+		return nil
+	case <-time.After(timeout):
+		log.Printf("minecraft server did not stop after %v, killing it", timeout)
+		return p.kill()
+	}
+}
+
+func (p *process) kill() error {
+	if err := p.cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return err
+	}
+	<-p.done
+
+	return nil
+}
+
+func (p *process) pid() int {
+	return p.cmd.Process.Pid
+}
+
+// isDoneLine reports whether line is the message the Minecraft server logs
+// once it has finished loading, e.g.
+//
+//	[12:00:00] [Server thread/INFO]: Done (4.213s)! For help, type "help"
+func isDoneLine(line string) bool {
+	return strings.Contains(line, "Done (") && strings.Contains(line, "For help")
+}
@@ -0,0 +1,203 @@
+package minecraft
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+// fakeJava is a stand-in for `java -jar server.jar` that logs the same line
+// the Minecraft server does when it's done loading and shuts down when it
+// receives the `stop` command.
+const fakeJava = `#!/bin/sh
+echo '[12:00:00] [Server thread/INFO]: Done (0.001s)! For help, type "help"'
+while read line; do
+	case "$line" in
+		stop*) echo '[12:00:01] [Server thread/INFO]: Stopping the server'; exit 0;;
+	esac
+done
+`
+
+// stubbornJava never stops on its own.
+const stubbornJava = `#!/bin/sh
+echo '[12:00:00] [Server thread/INFO]: Done (0.001s)! For help, type "help"'
+while true; do read line; done
+`
+
+func newFakeJavaServer(t *testing.T, script string) *JavaMinecraftServer {
+	t.Helper()
+
+	binDir := t.TempDir()
+	if err := os.WriteFile(path.Join(binDir, "java"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir)
+
+	dataDir := t.TempDir()
+	server := &JavaMinecraftServer{
+		filepaths: &MinecraftServerConfigFilepaths{
+			Properties: path.Join(dataDir, "properties.json"),
+		},
+	}
+	server.CreateConfig()
+	server.CreateArgs()
+
+	// the server jar is already in the server directory
+	jar := []byte("fake server jar")
+	if err := os.WriteFile(path.Join(dataDir, JarName(server.config.Version)), jar, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sum := md5.Sum(jar)
+	server.versions = VersionMap{
+		server.config.Version: {Sum: hex.EncodeToString(sum[:])},
+	}
+
+	return server
+}
+
+func waitForState(t *testing.T, server *JavaMinecraftServer, want ProcessState) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if server.State() == want {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("expected state `%s`, got `%s`", want, server.State())
+}
+
+func TestStartAndStop(t *testing.T) {
+	server := newFakeJavaServer(t, fakeJava)
+
+	if state := server.State(); state != ProcessStopped {
+		t.Fatalf("expected state `%s`, got `%s`", ProcessStopped, state)
+	}
+	if err := server.Stop(); err != ErrServerNotRunning {
+		t.Fatalf("expected error `%v`, got `%v`", ErrServerNotRunning, err)
+	}
+
+	if err := server.Start(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if server.PID() == 0 {
+		t.Error("expected a non-zero PID")
+	}
+	if err := server.Start(); err != ErrServerRunning {
+		t.Fatalf("expected error `%v`, got `%v`", ErrServerRunning, err)
+	}
+
+	waitForState(t, server, ProcessRunning)
+
+	if err := server.Stop(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if state := server.State(); state != ProcessStopped {
+		t.Fatalf("expected state `%s`, got `%s`", ProcessStopped, state)
+	}
+	if server.PID() != 0 {
+		t.Errorf("expected PID 0, got %d", server.PID())
+	}
+}
+
+func TestStartValidatesConfig(t *testing.T) {
+	server := newFakeJavaServer(t, fakeJava)
+	server.CreateProperties()
+	server.properties.EnableRCON = ref(true)
+
+	if err := server.Start(); !errors.Is(err, ErrInvalidProperty) {
+		t.Fatalf("expected error `%v`, got `%v`", ErrInvalidProperty, err)
+	}
+	if state := server.State(); state != ProcessStopped {
+		t.Fatalf("expected state `%s`, got `%s`", ProcessStopped, state)
+	}
+}
+
+func TestStopKillsAfterTimeout(t *testing.T) {
+	server := newFakeJavaServer(t, stubbornJava)
+	server.stopTimeout = 100 * time.Millisecond
+
+	if err := server.Start(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	waitForState(t, server, ProcessRunning)
+
+	if err := server.Stop(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if state := server.State(); state != ProcessStopped {
+		t.Fatalf("expected state `%s`, got `%s`", ProcessStopped, state)
+	}
+}
+
+func TestRestart(t *testing.T) {
+	server := newFakeJavaServer(t, fakeJava)
+
+	if err := server.Restart(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	waitForState(t, server, ProcessRunning)
+	firstPID := server.PID()
+
+	if err := server.Restart(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	waitForState(t, server, ProcessRunning)
+	if server.PID() == firstPID {
+		t.Error("expected restarted process to have a different PID")
+	}
+
+	if err := server.Stop(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+}
+
+func TestCrashedState(t *testing.T) {
+	server := newFakeJavaServer(t, "#!/bin/sh\nexit 1\n")
+
+	if err := server.Start(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	waitForState(t, server, ProcessCrashed)
+}
+
+func TestStartDownloadsJar(t *testing.T) {
+	server := newFakeJavaServer(t, fakeJava)
+
+	jar := []byte("downloaded server jar")
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(jar)
+	}))
+	defer ts.Close()
+
+	sum := md5.Sum(jar)
+	server.versions[server.config.Version] = VersionInfo{
+		Link: ts.URL + "/server.jar",
+		Sum:  hex.EncodeToString(sum[:]),
+	}
+
+	if err := server.Start(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	waitForState(t, server, ProcessRunning)
+
+	got, err := os.ReadFile(path.Join(server.serverDir(), JarName(server.config.Version)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(jar) {
+		t.Fatalf("expected jar contents `%s`, got `%s`", jar, got)
+	}
+
+	if err := server.Stop(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+}
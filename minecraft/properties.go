@@ -1,12 +1,123 @@
 package minecraft
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
+	"log"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/raian621/go-mcsc/api"
 )
 
+var ErrInvalidProperty = api.ErrInvalidProperty
+
+// serverPropertyKey is a key of server.properties, the field of
+// api.ServerProperties it's set from, and the versions of the Minecraft server
+// that have it: since the first version with it, until the first version
+// without it. An empty since means the key is older than 1.8, and an empty
+// until that it hasn't been removed.
+type serverPropertyKey struct {
+	key, field   string
+	since, until string
+}
+
+// serverPropertyKeys are the keys of server.properties, in the order they're
+// written to new files.
+var serverPropertyKeys = []serverPropertyKey{
+	{"accepts-transfers", "AcceptTransfers", "1.20.5", ""},
+	{"allow-flight", "AllowFlight", "", ""},
+	{"allow-nether", "AllowNether", "", ""},
+	{"broadcast-console-to-ops", "BroadcastConsoleToOps", "1.14", ""},
+	{"broadcast-rcon-to-ops", "BroadcastRCONToOps", "1.14", ""},
+	{"difficulty", "Difficulty", "", ""},
+	{"enable-command-block", "EnableCommandBlock", "", ""},
+	{"enable-jmx-monitoring", "EnableJMXMonitoring", "1.16", ""},
+	{"enable-query", "EnableQuery", "", ""},
+	{"enable-rcon", "EnableRCON", "", ""},
+	{"enable-status", "EnableStatus", "1.16", ""},
+	{"enforce-secure-profile", "EnforceSecureProfile", "1.19", ""},
+	{"enforce-whitelist", "EnforceWhitelist", "1.13", ""},
+	{"entity-broadcast-range-percentage", "EntityBroadcastRangePercentage", "1.16", ""},
+	{"force-gamemode", "ForceGamemode", "", ""},
+	{"function-permission-level", "FunctionPermissionLevel", "1.14.4", ""},
+	{"gamemode", "Gamemode", "", ""},
+	{"generate-structures", "GenerateStructures", "", ""},
+	{"generator-settings", "GeneratorSettings", "", ""},
+	{"hardcore", "Hardcore", "", ""},
+	{"hide-online-players", "HideOnlinePlayers", "1.18", ""},
+	{"initial-disabled-packs", "InitialDisabledPacks", "1.19.3", ""},
+	{"initial-enabled-packs", "InitialEnabledPacks", "1.19.3", ""},
+	{"level-name", "LevelName", "", ""},
+	{"level-seed", "LevelSeed", "", ""},
+	{"level-type", "LevelType", "", ""},
+	{"log-ips", "LogIPs", "1.20.2", ""},
+	{"max-chained-neighbor-updates", "MaxChainedNeighborUpdates", "1.19", ""},
+	{"max-players", "MaxPlayers", "", ""},
+	{"max-tick-time", "MaxTickTime", "1.8", ""},
+	{"max-world-size", "MaxWorldSize", "1.8", ""},
+	{"motd", "MOTD", "", ""},
+	{"network-compression-threshold", "NetworkCompressionThreshold", "1.8", ""},
+	{"online-mode", "OnlineMode", "", ""},
+	{"op-permission-level", "OpPermissionLevel", "", ""},
+	{"player-idle-timeout", "PlayerIdleTimeout", "", ""},
+	{"prevent-proxy-connections", "PreventProxyConnections", "1.11", ""},
+	{"previews-chat", "PreviewsChat", "1.19", "1.19.3"},
+	{"pvp", "PVP", "", ""},
+	{"query.port", "QueryPort", "", ""},
+	{"rate-limit", "RateLimit", "1.16.2", ""},
+	{"rcon.password", "RCONPassword", "", ""},
+	{"rcon.port", "RCONPort", "", ""},
+	{"region-file-compression", "RegionFileCompression", "1.20.5", ""},
+	{"require-resource-pack", "RequireResourcePack", "1.17", ""},
+	{"resource-pack", "ResourcePack", "", ""},
+	{"resource-pack-id", "ResourcePackID", "1.20.3", ""},
+	{"resource-pack-prompt", "ResourcePackPrompt", "1.17", ""},
+	{"resource-pack-sha1", "ResourcePackSHA1", "1.9", ""},
+	{"server-ip", "ServerIP", "", ""},
+	{"server-port", "ServerPort", "", ""},
+	{"simulation-distance", "SimulationDistance", "1.18", ""},
+	{"snooper-enabled", "SnooperEnabled", "", "1.18"},
+	{"spawn-animals", "SpawnAnimals", "", ""},
+	{"spawn-monsters", "SpawnMonsters", "", ""},
+	{"spawn-npcs", "SpawnNPCs", "", ""},
+	{"spawn-protection", "SpawnProtection", "", ""},
+	{"sync-chunk-writes", "SyncChunkWrites", "1.16", ""},
+	{"text-filtering-config", "TextFilteringConfig", "1.16.4", ""},
+	{"use-native-transport", "UseNativeTransport", "1.8", ""},
+	{"view-distance", "ViewDistance", "", ""},
+	{"white-list", "Whitelist", "", ""},
+}
+
+// in reports whether version of the Minecraft server has the key. The
+// pre-releases and release candidates of a release are taken to have the keys
+// of the release. Snapshots and versions that can't be parsed are taken to be
+// newer than every release, see Version.Compare.
+func (k serverPropertyKey) in(version string) bool {
+	v := ParseVersion(version)
+	if len(k.since) > 0 && v.Compare(firstBuildOf(k.since)) < 0 {
+		return false
+	}
+
+	return len(k.until) == 0 || v.Compare(firstBuildOf(k.until)) < 0
+}
+
+// firstBuildOf returns the version before the first pre-release of release.
+func firstBuildOf(release string) Version {
+	v := ParseVersion(release)
+	v.Kind, v.Build = VersionPreRelease, 0
+
+	return v
+}
+
 func NewServerProperties() *api.ServerProperties {
 	return &api.ServerProperties{
 		AcceptTransfers:                ref(false),
@@ -34,7 +145,7 @@ func NewServerProperties() *api.ServerProperties {
 		InitialEnabledPacks:            ref("vanilla"),
 		LevelName:                      ref("world"),
 		LevelSeed:                      ref(""),
-		LevelType:                      ref("minecraft\\:normal"),
+		LevelType:                      ref("minecraft:normal"),
 		LogIPs:                         ref(true),
 		MaxChainedNeighborUpdates:      ref(1000000),
 		MaxPlayers:                     ref(20),
@@ -88,8 +199,60 @@ func (m *JavaMinecraftServer) Properties() *api.ServerProperties {
 }
 
 // UpdateProperties implements api.MinecraftServerInterface.
-func (m *JavaMinecraftServer) UpdateProperties(props api.ServerProperties) error {
-	panic("unimplemented")
+//
+// UpdateProperties sets the properties that are set in props, leaving the
+// others alone, if the resulting properties are valid. If the Minecraft
+// server is running, the changed properties it can change while running, like
+// the difficulty, are applied to it through its console; the others are
+// reported as pending a restart. Properties that fail to apply are logged and
+// reported as pending a restart as well, since they are written to the
+// server.properties file either way. Properties the configured version of the
+// Minecraft server doesn't have are reported as ignored instead.
+func (m *JavaMinecraftServer) UpdateProperties(props *api.ServerProperties) (*api.PropertiesUpdate, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.properties == nil {
+		return nil, ErrNilConfig
+	}
+
+	merged := mergeServerProperties(m.properties, props)
+	if err := ValidateProperties(merged, m.args); err != nil {
+		return nil, err
+	}
+
+	result := &api.PropertiesUpdate{
+		Changed:        []string{},
+		Applied:        []string{},
+		PendingRestart: []string{},
+		Ignored:        ignoredServerProperties(props, m.version()),
+	}
+	running := m.console.Attached()
+	changes := diffServerProperties(m.properties, props)
+	// the properties are updated before they're applied, since the server is
+	// unlocked while the Minecraft server applies them
+	m.properties = merged
+
+	for _, change := range changes {
+		result.Changed = append(result.Changed, change.name)
+		if !running || slices.Contains(result.Ignored, change.name) {
+			continue
+		}
+
+		if command, ok := liveProperties[change.field]; ok {
+			if cmd := command(change.value); cmd != "" {
+				err := m.execute(cmd)
+				if err == nil {
+					result.Applied = append(result.Applied, change.name)
+					continue
+				}
+				log.Printf("error applying property `%s`: %v", change.name, err)
+			}
+		}
+		result.PendingRestart = append(result.PendingRestart, change.name)
+	}
+
+	return result, nil
 }
 
 func (m *JavaMinecraftServer) CreateProperties() {
@@ -103,7 +266,16 @@ func (m *JavaMinecraftServer) LoadProperties(file io.Reader) error {
 	m.Lock()
 	defer m.Unlock()
 
-	return json.NewDecoder(file).Decode(m.properties)
+	if err := json.NewDecoder(file).Decode(m.properties); err != nil {
+		return err
+	}
+	// the server.properties template the properties used to be rendered with
+	// didn't escape values, so the level type was kept escaped
+	if m.properties.LevelType != nil {
+		m.properties.LevelType = ref(strings.ReplaceAll(*m.properties.LevelType, `\:`, ":"))
+	}
+
+	return nil
 }
 
 func (m *JavaMinecraftServer) SaveProperties(file io.Writer) error {
@@ -123,3 +295,327 @@ func (m *JavaMinecraftServer) SetProperties(properties *api.ServerProperties) {
 
 	m.properties = properties
 }
+
+// ValidateProperties returns an api.ValidationError listing the fields of
+// props the Minecraft server would reject or misbehave with when it's run with
+// args, which may override the server port. args may be nil. The query port may
+// be the same as the server port since queries are sent over UDP.
+func ValidateProperties(props *api.ServerProperties, args *api.ServerArguments) error {
+	if props == nil {
+		return ErrNilConfig
+	}
+
+	var errs fieldErrors
+
+	// the ranges of the ServerProperties schema in openapi.yml
+	for _, value := range []struct {
+		name     string
+		value    *int
+		min, max int
+	}{
+		{"entityBroadcastRangePercentage", props.EntityBroadcastRangePercentage, 10, 1000},
+		{"functionPermissionLevel", props.FunctionPermissionLevel, 1, 4},
+		{"maxChainedNeighborUpdates", props.MaxChainedNeighborUpdates, -1, math.MaxInt32},
+		{"maxPlayers", props.MaxPlayers, 1, math.MaxInt32},
+		{"maxTickTime", props.MaxTickTime, -1, math.MaxInt},
+		{"maxWorldSize", props.MaxWorldSize, 1, 29999984},
+		{"networkCompressionThreshold", props.NetworkCompressionThreshold, -1, math.MaxInt32},
+		{"opPermissionLevel", props.OpPermissionLevel, 0, 4},
+		{"playerIdleTimeout", props.PlayerIdleTimeout, 0, math.MaxInt32},
+		{"queryPort", props.QueryPort, 1, 65535},
+		{"rateLimit", props.RateLimit, 0, math.MaxInt32},
+		{"RCONPort", props.RCONPort, 1, 65535},
+		{"serverPort", props.ServerPort, 1, 65535},
+		{"simulationDistance", props.SimulationDistance, 3, 32},
+		{"spawnProtection", props.SpawnProtection, 0, math.MaxInt32},
+		{"viewDistance", props.ViewDistance, 3, 32},
+	} {
+		if value.value != nil && (*value.value < value.min || *value.value > value.max) {
+			errs.add(ErrInvalidProperty, value.name, "must be between %d and %d", value.min, value.max)
+		}
+	}
+
+	if props.Difficulty != nil {
+		switch *props.Difficulty {
+		case api.Peaceful, api.Easy, api.Medium, api.Hard:
+		default:
+			errs.add(ErrInvalidProperty, "difficulty", "`%s` is not a difficulty", *props.Difficulty)
+		}
+	}
+	if props.Gamemode != nil {
+		switch *props.Gamemode {
+		case api.Survival, api.Creative, api.Adventure, api.Spectator, api.Hardcore:
+		default:
+			errs.add(ErrInvalidProperty, "gamemode", "`%s` is not a game mode", *props.Gamemode)
+		}
+	}
+
+	// worlds outside the server directory couldn't be backed up, and could
+	// overwrite anything the server controller can write to
+	if props.LevelName != nil && !filepath.IsLocal(*props.LevelName) {
+		errs.add(ErrInvalidProperty, "levelName", "must be a directory inside the server directory")
+	}
+
+	if props.EnableRCON != nil && *props.EnableRCON {
+		if props.RCONPassword == nil || len(*props.RCONPassword) == 0 {
+			errs.add(ErrInvalidProperty, "RCONPassword", "must be set when enableRCON is set")
+		}
+		if rconOnServerPort(props, args) {
+			errs.add(ErrInvalidProperty, "RCONPort", "must differ from the port the server listens on (%d)", listenPort(props, args))
+		}
+	}
+
+	return errs.err()
+}
+
+// listenPort returns the port the Minecraft server with the given properties
+// and arguments listens on. The port argument overrides the server-port
+// property.
+func listenPort(props *api.ServerProperties, args *api.ServerArguments) int {
+	if args != nil && args.Port != nil {
+		return *args.Port
+	}
+	if props.ServerPort != nil {
+		return *props.ServerPort
+	}
+
+	return DefaultServerPort
+}
+
+// rconOnServerPort reports whether RCON is enabled in props on the port the
+// Minecraft server listens on.
+func rconOnServerPort(props *api.ServerProperties, args *api.ServerArguments) bool {
+	return props.EnableRCON != nil && *props.EnableRCON &&
+		props.RCONPort != nil && *props.RCONPort == listenPort(props, args)
+}
+
+// DecodeServerProperties sets the fields of props to the values of the keys
+// in file. Fields of keys that aren't in file are left alone.
+func DecodeServerProperties(file *PropertiesFile, props *api.ServerProperties) error {
+	v := reflect.ValueOf(props).Elem()
+	for _, k := range serverPropertyKeys {
+		value, ok := file.Get(k.key)
+		if !ok {
+			continue
+		}
+
+		field := v.FieldByName(k.field)
+		parsed := reflect.New(field.Type().Elem())
+		switch parsed.Elem().Kind() {
+		case reflect.Bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("%w: %s=%s", ErrInvalidProperty, k.key, value)
+			}
+			parsed.Elem().SetBool(b)
+		case reflect.Int:
+			i, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return fmt.Errorf("%w: %s=%s", ErrInvalidProperty, k.key, value)
+			}
+			parsed.Elem().SetInt(int64(i))
+		default:
+			parsed.Elem().SetString(value)
+		}
+		field.Set(parsed)
+	}
+
+	return nil
+}
+
+// EncodeServerProperties sets the keys in file to the fields of props that
+// are set, for version of the Minecraft server. Keys version doesn't have are
+// removed from file instead, since the Minecraft server ignores them. Other
+// keys in file, like ones added by plugins, are left alone.
+func EncodeServerProperties(props *api.ServerProperties, version string, file *PropertiesFile) {
+	v := reflect.ValueOf(props).Elem()
+	for _, k := range serverPropertyKeys {
+		if !k.in(version) {
+			file.Delete(k.key)
+			continue
+		}
+		field := v.FieldByName(k.field)
+		if field.IsNil() {
+			continue
+		}
+		file.Set(k.key, fmt.Sprint(field.Elem().Interface()))
+	}
+}
+
+// writeServerProperties writes the properties of the Minecraft server to its
+// server.properties file, for the configured version.
+func (m *JavaMinecraftServer) writeServerProperties() error {
+	m.Lock()
+	defer m.Unlock()
+
+	return saveServerProperties(m.properties, m.version(), m.serverPropertiesPath())
+}
+
+// version returns the configured version of the Minecraft server, or "" if
+// its config isn't loaded. m must be locked.
+func (m *JavaMinecraftServer) version() string {
+	if m.config == nil {
+		return ""
+	}
+
+	return m.config.Version
+}
+
+// saveServerProperties writes props to the server.properties file at
+// filepath for version of the Minecraft server, keeping its comments and the
+// keys the server controller doesn't know about.
+func saveServerProperties(props *api.ServerProperties, version, filepath string) error {
+	file := NewPropertiesFile()
+	if data, err := os.ReadFile(filepath); err == nil {
+		if file, err = ParseProperties(bytes.NewReader(data)); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	EncodeServerProperties(props, version, file)
+
+	var buf bytes.Buffer
+	if err := file.Encode(&buf); err != nil {
+		return err
+	}
+
+	return api.WriteFileAtomic(filepath, buf.Bytes(), configFileMode)
+}
+
+// importServerProperties creates the properties file of the Minecraft server
+// from its server.properties file, like one of a server that was set up
+// without the server controller, if it has one but no properties file yet.
+func (m *JavaMinecraftServer) importServerProperties() error {
+	if _, err := os.Stat(m.filepaths.Properties); !os.IsNotExist(err) {
+		return err
+	}
+	data, err := os.ReadFile(m.serverPropertiesPath())
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	file, err := ParseProperties(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	props := NewServerProperties()
+	if err := DecodeServerProperties(file, props); err != nil {
+		return err
+	}
+
+	m.Lock()
+	m.properties = props
+	m.Unlock()
+
+	return saveJSON(m.SaveProperties, m.filepaths.Properties)
+}
+
+// serverPropertiesPath returns the path of the server.properties file the
+// Minecraft server reads its properties from.
+func (m *JavaMinecraftServer) serverPropertiesPath() string {
+	return path.Join(m.serverDir(), "server.properties")
+}
+
+// liveProperties holds the functions returning the console command that
+// applies a new value of a property to the running Minecraft server, keyed by
+// the field of the property in api.ServerProperties. Properties that aren't
+// in it, and values the functions return "" for, only take effect once the
+// Minecraft server is restarted.
+var liveProperties = map[string]func(value string) string{
+	"Difficulty": func(value string) string {
+		// the Minecraft server calls the medium difficulty normal
+		if value == string(api.Medium) {
+			value = "normal"
+		}
+		return "/difficulty " + value
+	},
+	"Gamemode": func(value string) string {
+		// hardcore is a world setting rather than a game mode
+		if value == string(api.Hardcore) {
+			return ""
+		}
+		return "/defaultgamemode " + value
+	},
+	"Whitelist": func(value string) string {
+		if value == "true" {
+			return "/whitelist on"
+		}
+		return "/whitelist off"
+	},
+}
+
+// propertyChange is a server property set to a new value.
+type propertyChange struct {
+	// field is the name of the property's field in api.ServerProperties and
+	// name its name in JSON
+	field string
+	name  string
+	value string
+}
+
+// diffServerProperties returns the properties that are set in props to a
+// different value than in current, in the order of the fields of
+// api.ServerProperties.
+func diffServerProperties(current, props *api.ServerProperties) []propertyChange {
+	changes := make([]propertyChange, 0)
+	cv, pv := reflect.ValueOf(current).Elem(), reflect.ValueOf(props).Elem()
+	for i := 0; i < pv.NumField(); i++ {
+		field := pv.Field(i)
+		if field.IsNil() {
+			continue
+		}
+		value := fmt.Sprint(field.Elem().Interface())
+		if old := cv.Field(i); !old.IsNil() && fmt.Sprint(old.Elem().Interface()) == value {
+			continue
+		}
+
+		structField := pv.Type().Field(i)
+		changes = append(changes, propertyChange{
+			field: structField.Name,
+			name:  jsonName(structField),
+			value: value,
+		})
+	}
+
+	return changes
+}
+
+// ignoredServerProperties returns the JSON names of the properties set in
+// props that version of the Minecraft server doesn't have.
+func ignoredServerProperties(props *api.ServerProperties, version string) []string {
+	ignored := make([]string, 0)
+	v := reflect.ValueOf(props).Elem()
+	for _, k := range serverPropertyKeys {
+		if k.in(version) || v.FieldByName(k.field).IsNil() {
+			continue
+		}
+		field, _ := v.Type().FieldByName(k.field)
+		ignored = append(ignored, jsonName(field))
+	}
+
+	return ignored
+}
+
+// jsonName returns the name of field in JSON.
+func jsonName(field reflect.StructField) string {
+	return strings.Split(field.Tag.Get("json"), ",")[0]
+}
+
+// mergeServerProperties returns a copy of current with the properties that
+// are set in props set to their values in props.
+func mergeServerProperties(current, props *api.ServerProperties) *api.ServerProperties {
+	merged := *current
+	mv, pv := reflect.ValueOf(&merged).Elem(), reflect.ValueOf(props).Elem()
+	for i := 0; i < pv.NumField(); i++ {
+		if field := pv.Field(i); !field.IsNil() {
+			value := reflect.New(field.Type().Elem())
+			value.Elem().Set(field.Elem())
+			mv.Field(i).Set(value)
+		}
+	}
+
+	return &merged
+}
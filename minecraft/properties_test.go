@@ -1,26 +1,383 @@
 package minecraft_test
 
 import (
+	"bytes"
+	"errors"
+	"os"
+	"path"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/raian621/go-mcsc/api"
+	"github.com/raian621/go-mcsc/minecraft"
 )
 
 func TestSaveToFile(t *testing.T) {
-	// serverProps := minecraft.NewServerProperties()
-	// var buffer bytes.Buffer
-	// err := serverProps.Save(
-	// 	"templates/server.properties.tmpl",
-	// 	&buffer,
-	// )
-	// if err != nil {
-	// 	t.Error(err)
-	// }
-
-	// fixture, err := os.ReadFile("fixtures/server.properties")
-	// if err != nil {
-	// 	t.Error(err)
-	// }
-
-	// if string(fixture) != buffer.String() {
-	// 	t.Fail()
-	// }
+	t.Parallel()
+
+	file := minecraft.NewPropertiesFile()
+	minecraft.EncodeServerProperties(minecraft.NewServerProperties(), "1.20.6", file)
+	var buffer bytes.Buffer
+	if err := file.Encode(&buffer); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+
+	fixture, err := os.ReadFile("../fixtures/server.properties")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(fixture) != buffer.String() {
+		t.Fatalf("expected the default properties to match the fixture, got\n%s", buffer.String())
+	}
+
+	// and they're read back as they were
+	parsed, err := minecraft.ParseProperties(bytes.NewReader(fixture))
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	var props api.ServerProperties
+	if err := minecraft.DecodeServerProperties(parsed, &props); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	want := *minecraft.NewServerProperties()
+	// not written by the Minecraft versions the fixture is from
+	want.PreviewsChat, want.SnooperEnabled = nil, nil
+	if !reflect.DeepEqual(want, props) {
+		t.Fatalf("expected properties `%+v`, got `%+v`", want, props)
+	}
+}
+
+func TestEncodeServerPropertiesVersions(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		version     string
+		wantKeys    []string
+		wantMissing []string
+	}{
+		{
+			version:     "1.8.9",
+			wantKeys:    []string{"max-world-size", "snooper-enabled"},
+			wantMissing: []string{"accepts-transfers", "previews-chat", "region-file-compression", "simulation-distance"},
+		},
+		{
+			version:     "1.19.2",
+			wantKeys:    []string{"enforce-secure-profile", "previews-chat", "simulation-distance"},
+			wantMissing: []string{"initial-enabled-packs", "snooper-enabled"},
+		},
+		{
+			version:     "1.19.3",
+			wantKeys:    []string{"initial-enabled-packs"},
+			wantMissing: []string{"previews-chat"},
+		},
+		{
+			version:     "1.20.5-pre1",
+			wantKeys:    []string{"accepts-transfers", "region-file-compression"},
+			wantMissing: []string{"previews-chat", "snooper-enabled"},
+		},
+		{
+			version:     "24w14a",
+			wantKeys:    []string{"accepts-transfers"},
+			wantMissing: []string{"snooper-enabled"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.version, func(t *testing.T) {
+			t.Parallel()
+
+			// keys the version doesn't have are removed, and unknown keys kept
+			file, err := minecraft.ParseProperties(strings.NewReader("snooper-enabled=true\naccepts-transfers=false\nplugin-setting=1\n"))
+			if err != nil {
+				t.Fatalf("expected no error, got `%v`", err)
+			}
+			minecraft.EncodeServerProperties(minecraft.NewServerProperties(), tc.version, file)
+
+			for _, key := range append(tc.wantKeys, "plugin-setting") {
+				if _, ok := file.Get(key); !ok {
+					t.Errorf("expected `%s` to be written", key)
+				}
+			}
+			for _, key := range tc.wantMissing {
+				if _, ok := file.Get(key); ok {
+					t.Errorf("expected `%s` not to be written", key)
+				}
+			}
+		})
+	}
+
+	// every property is written for some version
+	keys := make(map[string]bool)
+	for _, version := range []string{"1.17", "1.19", "1.20.6"} {
+		file := minecraft.NewPropertiesFile()
+		minecraft.EncodeServerProperties(minecraft.NewServerProperties(), version, file)
+		for _, key := range file.Keys() {
+			keys[key] = true
+		}
+	}
+	if fields := reflect.TypeOf(api.ServerProperties{}).NumField(); len(keys) != fields {
+		t.Fatalf("expected %d properties to be written, got %d", fields, len(keys))
+	}
+}
+
+func TestDecodeServerProperties(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		file    string
+		want    api.ServerProperties
+		wantErr error
+	}{
+		{
+			name: "known keys",
+			file: "motd=Hello \\u00E9t\\u00E9\nmax-players = 50\npvp:false\nlevel-type=minecraft\\:flat\n",
+			want: api.ServerProperties{
+				MOTD:       ref("Hello été"),
+				MaxPlayers: ref(50),
+				PVP:        ref(false),
+				LevelType:  ref("minecraft:flat"),
+			},
+		},
+		{name: "unknown keys", file: "plugin-setting=1\n", want: api.ServerProperties{}},
+		{name: "invalid int", file: "max-players=lots\n", wantErr: minecraft.ErrInvalidProperty},
+		{name: "invalid bool", file: "pvp=maybe\n", wantErr: minecraft.ErrInvalidProperty},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := minecraft.ParseProperties(strings.NewReader(tc.file))
+			if err != nil {
+				t.Fatalf("expected no error, got `%v`", err)
+			}
+
+			var props api.ServerProperties
+			err = minecraft.DecodeServerProperties(file, &props)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error `%v`, got `%v`", tc.wantErr, err)
+			}
+			if tc.wantErr == nil && !reflect.DeepEqual(tc.want, props) {
+				t.Fatalf("expected properties `%+v`, got `%+v`", tc.want, props)
+			}
+		})
+	}
+}
+
+func TestImportServerProperties(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	versions := path.Join(t.TempDir(), "versions.json")
+	if err := os.WriteFile(versions, []byte(`{"1.20.6": {"link": ""}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	existing := "#Minecraft server properties\nmotd=Existing server\nmax-players=8\nplugin-setting=keep me\n"
+	if err := os.WriteFile(path.Join(dir, "server.properties"), []byte(existing), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := minecraft.NewJavaMinecraftServer(minecraft.ServerFilepaths(dir, versions))
+	if err := server.LoadConfigs(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	props := server.Properties()
+	if *props.MOTD != "Existing server" || *props.MaxPlayers != 8 || *props.ViewDistance != 10 {
+		t.Fatalf("expected the existing properties over the defaults, got `%+v`", *props)
+	}
+
+	data, err := os.ReadFile(path.Join(dir, "server.properties"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), existing) {
+		t.Fatalf("expected the existing file to be kept, got\n%s", data)
+	}
+	if !strings.Contains(string(data), "view-distance=10\n") {
+		t.Fatalf("expected the missing properties to be added, got\n%s", data)
+	}
+}
+
+func TestValidateProperties(t *testing.T) {
+	t.Parallel()
+
+	withDefaults := func(change func(props *api.ServerProperties)) *api.ServerProperties {
+		props := minecraft.NewServerProperties()
+		change(props)
+		return props
+	}
+
+	testCases := []struct {
+		name       string
+		props      *api.ServerProperties
+		args       *api.ServerArguments
+		wantErr    error
+		wantFields []string
+	}{
+		{name: "defaults", props: minecraft.NewServerProperties()},
+		{name: "nil properties", wantErr: minecraft.ErrNilConfig},
+		{name: "no properties set", props: &api.ServerProperties{}},
+		{
+			name:  "RCON",
+			props: withDefaults(func(p *api.ServerProperties) { p.EnableRCON, p.RCONPassword = ref(true), ref("hunter2") }),
+		},
+		{
+			name:  "query on the server port",
+			props: withDefaults(func(p *api.ServerProperties) { p.EnableQuery, p.QueryPort = ref(true), p.ServerPort }),
+		},
+		{
+			name:       "level outside the server directory",
+			props:      withDefaults(func(p *api.ServerProperties) { p.LevelName = ref("../world") }),
+			wantErr:    minecraft.ErrInvalidProperty,
+			wantFields: []string{"levelName"},
+		},
+		{
+			name:       "no players",
+			props:      withDefaults(func(p *api.ServerProperties) { p.MaxPlayers = ref(0) }),
+			wantErr:    minecraft.ErrInvalidProperty,
+			wantFields: []string{"maxPlayers"},
+		},
+		{
+			name: "highest ports",
+			props: withDefaults(func(p *api.ServerProperties) {
+				p.ServerPort, p.QueryPort, p.RCONPort = ref(65535), ref(65535), ref(65534)
+			}),
+		},
+		{
+			name:       "ports out of range",
+			props:      withDefaults(func(p *api.ServerProperties) { p.ServerPort, p.QueryPort = ref(0), ref(65536) }),
+			wantErr:    minecraft.ErrInvalidProperty,
+			wantFields: []string{"queryPort", "serverPort"},
+		},
+		{
+			name:       "operator permission level",
+			props:      withDefaults(func(p *api.ServerProperties) { p.OpPermissionLevel = ref(5) }),
+			wantErr:    minecraft.ErrInvalidProperty,
+			wantFields: []string{"opPermissionLevel"},
+		},
+		{
+			name:       "unknown difficulty",
+			props:      withDefaults(func(p *api.ServerProperties) { p.Difficulty = ref(api.ServerPropertiesDifficulty("normal")) }),
+			wantErr:    minecraft.ErrInvalidProperty,
+			wantFields: []string{"difficulty"},
+		},
+		{
+			name:       "RCON without a password on the server port",
+			props:      withDefaults(func(p *api.ServerProperties) { p.EnableRCON, p.RCONPort = ref(true), p.ServerPort }),
+			wantErr:    minecraft.ErrInvalidProperty,
+			wantFields: []string{"RCONPassword", "RCONPort"},
+		},
+		{
+			name: "RCON on the overridden server port",
+			props: withDefaults(func(p *api.ServerProperties) {
+				p.EnableRCON, p.RCONPassword, p.RCONPort = ref(true), ref("hunter2"), p.ServerPort
+			}),
+			args: &api.ServerArguments{Port: ref(25566)},
+		},
+		{
+			name: "RCON on the port argument",
+			props: withDefaults(func(p *api.ServerProperties) {
+				p.EnableRCON, p.RCONPassword, p.RCONPort = ref(true), ref("hunter2"), ref(25566)
+			}),
+			args:       &api.ServerArguments{Port: ref(25566)},
+			wantErr:    minecraft.ErrInvalidProperty,
+			wantFields: []string{"RCONPort"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := minecraft.ValidateProperties(tc.props, tc.args)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error `%v`, got `%v`", tc.wantErr, err)
+			}
+			if tc.wantFields == nil {
+				return
+			}
+
+			var validation api.ValidationError
+			if !errors.As(err, &validation) {
+				t.Fatalf("expected a validation error, got `%v`", err)
+			}
+			fields := make([]string, 0)
+			for _, field := range validation {
+				fields = append(fields, field.Field)
+			}
+			if !reflect.DeepEqual(tc.wantFields, fields) {
+				t.Fatalf("expected invalid fields `%v`, got `%v`", tc.wantFields, fields)
+			}
+		})
+	}
 }
+
+func TestUpdateProperties(t *testing.T) {
+	t.Parallel()
+
+	server := minecraft.NewJavaMinecraftServer(nil)
+	if _, err := server.UpdateProperties(&api.ServerProperties{}); !errors.Is(err, minecraft.ErrNilConfig) {
+		t.Fatalf("expected error `%v`, got `%v`", minecraft.ErrNilConfig, err)
+	}
+	server.CreateProperties()
+	server.CreateConfig()
+
+	testCases := []struct {
+		name  string
+		props api.ServerProperties
+		want  api.PropertiesUpdate
+	}{
+		{
+			name:  "no changes",
+			props: api.ServerProperties{MOTD: ref("A Minecraft Server"), ViewDistance: ref(10)},
+			want:  api.PropertiesUpdate{Changed: []string{}, Applied: []string{}, PendingRestart: []string{}, Ignored: []string{}},
+		},
+		{
+			// nothing is pending a restart while the server isn't running
+			name:  "changes",
+			props: api.ServerProperties{Difficulty: ref(api.Hard), ServerPort: ref(25566), ViewDistance: ref(10)},
+			want:  api.PropertiesUpdate{Changed: []string{"difficulty", "serverPort"}, Applied: []string{}, PendingRestart: []string{}, Ignored: []string{}},
+		},
+	}
+
+	// the test cases build on each other, so they aren't run in parallel
+	for _, tc := range testCases {
+		result, err := server.UpdateProperties(&tc.props)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got `%v`", tc.name, err)
+		}
+		if !reflect.DeepEqual(tc.want, *result) {
+			t.Errorf("%s: expected result `%+v`, got `%+v`", tc.name, tc.want, *result)
+		}
+	}
+
+	// invalid properties are rejected without changing any
+	invalid := api.ServerProperties{Difficulty: ref(api.Peaceful), EnableRCON: ref(true)}
+	if _, err := server.UpdateProperties(&invalid); !errors.Is(err, minecraft.ErrInvalidProperty) {
+		t.Fatalf("expected error `%v`, got `%v`", minecraft.ErrInvalidProperty, err)
+	}
+
+	// properties the version doesn't have are ignored
+	if err := server.LoadConfig(strings.NewReader(`{"version": "1.16.5"}`)); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	result, err := server.UpdateProperties(&api.ServerProperties{SimulationDistance: ref(8), SnooperEnabled: ref(false)})
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if want := []string{"simulationDistance"}; !reflect.DeepEqual(want, result.Ignored) {
+		t.Fatalf("expected ignored properties `%v`, got `%v`", want, result.Ignored)
+	}
+
+	props := server.Properties()
+	if *props.Difficulty != api.Hard || *props.ServerPort != 25566 || *props.MOTD != "A Minecraft Server" {
+		t.Fatalf("expected the properties to be updated, got `%+v`", *props)
+	}
+}
+
+func ref[T any](v T) *T { return &v }
@@ -0,0 +1,336 @@
+package minecraft
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var ErrMalformedProperties = errors.New("malformed properties file")
+
+// PropertiesFile is a Java .properties file, like server.properties.
+//
+// Comments, blank lines and the order of the keys are kept, and entries that
+// aren't changed are written back exactly as they were read, so a file
+// round-trips without losing anything, including keys the server controller
+// doesn't know about, like ones added by plugins or newer Minecraft versions.
+type PropertiesFile struct {
+	lines   []propertiesLine
+	newline string
+}
+
+// propertiesLine is a logical line of a properties file: an entry, a comment
+// or a blank line. Entries may span several natural lines.
+type propertiesLine struct {
+	raw   string
+	entry bool
+	key   string
+	value string
+}
+
+// NewPropertiesFile returns an empty properties file.
+func NewPropertiesFile() *PropertiesFile {
+	return &PropertiesFile{newline: "\n"}
+}
+
+// ParseProperties reads a properties file from r. Files are read as UTF-8 if
+// they're valid UTF-8, and as ISO 8859-1, the encoding Java reads properties
+// files with, otherwise.
+func ParseProperties(r io.Reader) (*PropertiesFile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	text := string(data)
+	if !utf8.Valid(data) {
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		text = string(runes)
+	}
+
+	p := NewPropertiesFile()
+	if strings.Contains(text, "\r\n") {
+		p.newline = "\r\n"
+	}
+
+	lines := splitNaturalLines(text)
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimLeft(line, " \t\f")
+		if len(trimmed) == 0 || trimmed[0] == '#' || trimmed[0] == '!' {
+			p.lines = append(p.lines, propertiesLine{raw: line})
+			continue
+		}
+
+		// lines ending with an unescaped backslash continue on the next line
+		raw, logical := line, trimmed
+		for continues(logical) && i+1 < len(lines) {
+			i++
+			raw += p.newline + lines[i]
+			logical = logical[:len(logical)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+		if continues(logical) {
+			logical = logical[:len(logical)-1]
+		}
+
+		key, value, err := parseEntry(logical)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrMalformedProperties, i+1, err)
+		}
+		p.lines = append(p.lines, propertiesLine{raw: raw, entry: true, key: key, value: value})
+	}
+
+	return p, nil
+}
+
+// Get returns the value of key, and whether the file has it.
+func (p *PropertiesFile) Get(key string) (string, bool) {
+	if i := p.index(key); i != -1 {
+		return p.lines[i].value, true
+	}
+
+	return "", false
+}
+
+// Set sets the value of key. Keys that aren't in the file yet are added at the
+// end of it.
+func (p *PropertiesFile) Set(key, value string) {
+	line := propertiesLine{
+		raw:   escapeProperty(key, true) + "=" + escapeProperty(value, false),
+		entry: true,
+		key:   key,
+		value: value,
+	}
+
+	i := p.index(key)
+	if i == -1 {
+		p.lines = append(p.lines, line)
+	} else if p.lines[i].value != value {
+		p.lines[i] = line
+	}
+}
+
+// Delete deletes key from the file.
+func (p *PropertiesFile) Delete(key string) {
+	if i := p.index(key); i != -1 {
+		p.lines = append(p.lines[:i], p.lines[i+1:]...)
+	}
+}
+
+// Keys returns the keys in the file, in the order they appear in.
+func (p *PropertiesFile) Keys() []string {
+	keys := make([]string, 0, len(p.lines))
+	for _, line := range p.lines {
+		if line.entry {
+			keys = append(keys, line.key)
+		}
+	}
+
+	return keys
+}
+
+// Encode writes the file to w.
+func (p *PropertiesFile) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, line := range p.lines {
+		if _, err := bw.WriteString(line.raw + p.newline); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// index returns the index of the last line with key, which is the one Java
+// uses, or -1 if there is none.
+func (p *PropertiesFile) index(key string) int {
+	for i := len(p.lines) - 1; i >= 0; i-- {
+		if p.lines[i].entry && p.lines[i].key == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// splitNaturalLines splits text into lines terminated by `\n`, `\r` or `\r\n`.
+func splitNaturalLines(text string) []string {
+	var lines []string
+	for len(text) > 0 {
+		i := strings.IndexAny(text, "\r\n")
+		if i == -1 {
+			lines = append(lines, text)
+			break
+		}
+		lines = append(lines, text[:i])
+		if text[i] == '\r' && i+1 < len(text) && text[i+1] == '\n' {
+			i++
+		}
+		text = text[i+1:]
+	}
+
+	return lines
+}
+
+// continues reports whether line ends with an odd number of backslashes,
+// which continues it on the next line.
+func continues(line string) bool {
+	backslashes := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		backslashes++
+	}
+
+	return backslashes%2 == 1
+}
+
+// parseEntry splits a logical line into its key and value the way Java does:
+// the key ends at the first unescaped `=`, `:` or whitespace, which may be
+// surrounded by whitespace.
+func parseEntry(line string) (string, string, error) {
+	keyEnd, valueStart := len(line), len(line)
+	hasSeparator, escaped := false, false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if !escaped && (c == '=' || c == ':') {
+			keyEnd, valueStart, hasSeparator = i, i+1, true
+			break
+		}
+		if !escaped && (c == ' ' || c == '\t' || c == '\f') {
+			keyEnd, valueStart = i, i+1
+			break
+		}
+		escaped = c == '\\' && !escaped
+	}
+	for valueStart < len(line) {
+		c := line[valueStart]
+		if c != ' ' && c != '\t' && c != '\f' {
+			if hasSeparator || (c != '=' && c != ':') {
+				break
+			}
+			hasSeparator = true
+		}
+		valueStart++
+	}
+
+	key, err := unescapeProperty(line[:keyEnd])
+	if err != nil {
+		return "", "", err
+	}
+	value, err := unescapeProperty(line[valueStart:])
+	if err != nil {
+		return "", "", err
+	}
+
+	return key, value, nil
+}
+
+// unescapeProperty replaces the escape sequences in s with the characters
+// they stand for.
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var b strings.Builder
+	// the first half of a surrogate pair waiting for its second half
+	var surrogate rune
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if surrogate != 0 && (c != '\\' || i+1 == len(s) || s[i+1] != 'u') {
+			b.WriteRune(utf8.RuneError)
+			surrogate = 0
+		}
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		if i == len(s) {
+			break
+		}
+
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+4 >= len(s) {
+				return "", fmt.Errorf("malformed \\uxxxx escape in `%s`", s)
+			}
+			code, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("malformed \\uxxxx escape in `%s`", s)
+			}
+			i += 4
+
+			r := rune(code)
+			switch {
+			case utf16.IsSurrogate(r) && surrogate == 0:
+				surrogate = r
+				continue
+			case surrogate != 0:
+				r = utf16.DecodeRune(surrogate, r)
+				surrogate = 0
+			}
+			b.WriteRune(r)
+		default:
+			// any other character stands for itself
+			b.WriteByte(s[i])
+		}
+	}
+	if surrogate != 0 {
+		b.WriteRune(utf8.RuneError)
+	}
+
+	return b.String(), nil
+}
+
+// escapeProperty escapes s to be written as a key, or as a value if key is
+// false, the way Java does. Characters outside of printable ASCII are written
+// as \uxxxx escapes, so the file reads the same in any encoding.
+func escapeProperty(s string, key bool) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch r {
+		case ' ':
+			if i == 0 || key {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(' ')
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '=', ':', '#', '!', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		default:
+			if r < 0x20 || r > 0x7e {
+				for _, unit := range utf16.Encode([]rune{r}) {
+					fmt.Fprintf(&b, `\u%04X`, unit)
+				}
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,142 @@
+package minecraft
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseProperties(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		file     string
+		want     map[string]string
+		wantKeys []string
+		wantErr  error
+	}{
+		{name: "equals", file: "a=1\nb = 2\n", want: map[string]string{"a": "1", "b": "2"}, wantKeys: []string{"a", "b"}},
+		{name: "colon and whitespace separators", file: "a:1\nb 2\nc\t = 3\n", want: map[string]string{"a": "1", "b": "2", "c": "3"}, wantKeys: []string{"a", "b", "c"}},
+		{name: "empty value", file: "a=\nb\n", want: map[string]string{"a": "", "b": ""}, wantKeys: []string{"a", "b"}},
+		{name: "comments and blank lines", file: "# comment\n! comment=1\n\n  \na=1\n", want: map[string]string{"a": "1"}, wantKeys: []string{"a"}},
+		{name: "escaped separators", file: "a\\=b=c\\:d\nkey\\ with\\ spaces=x\n", want: map[string]string{"a=b": "c:d", "key with spaces": "x"}, wantKeys: []string{"a=b", "key with spaces"}},
+		{name: "escapes", file: "a=tab\\tnew\\nline\\\\\n", want: map[string]string{"a": "tab\tnew\nline\\"}, wantKeys: []string{"a"}},
+		{name: "unicode escapes", file: "a=\\u00e9\\u4e16\\uD83D\\uDE00\n", want: map[string]string{"a": "é世😀"}, wantKeys: []string{"a"}},
+		{name: "UTF-8", file: "motd=été\n", want: map[string]string{"motd": "été"}, wantKeys: []string{"motd"}},
+		{name: "ISO 8859-1", file: "motd=\xe9t\xe9\n", want: map[string]string{"motd": "été"}, wantKeys: []string{"motd"}},
+		{name: "continued lines", file: "a=one \\\n    two \\\n three\nb=2\n", want: map[string]string{"a": "one two three", "b": "2"}, wantKeys: []string{"a", "b"}},
+		{name: "CRLF", file: "a=1\r\nb=2\r\n", want: map[string]string{"a": "1", "b": "2"}, wantKeys: []string{"a", "b"}},
+		{name: "last duplicate wins", file: "a=1\na=2\n", want: map[string]string{"a": "2"}, wantKeys: []string{"a", "a"}},
+		{name: "malformed unicode escape", file: "a=\\u00g1\n", wantErr: ErrMalformedProperties},
+		{name: "truncated unicode escape", file: "a=\\u00\n", wantErr: ErrMalformedProperties},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := ParseProperties(strings.NewReader(tc.file))
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error `%v`, got `%v`", tc.wantErr, err)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+
+			for key, want := range tc.want {
+				if got, ok := p.Get(key); !ok || got != want {
+					t.Errorf("expected `%s` to be `%q`, got `%q`", key, want, got)
+				}
+			}
+			if keys := p.Keys(); !reflect.DeepEqual(tc.wantKeys, keys) {
+				t.Errorf("expected keys `%v`, got `%v`", tc.wantKeys, keys)
+			}
+		})
+	}
+}
+
+func TestPropertiesRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	original := "#Minecraft server properties\n#Sat Jan 01 00:00:00 UTC 2000\n" +
+		"motd=\\u00A7aHello\\ world\n" +
+		"level-type   :   minecraft\\:normal\n" +
+		"\n" +
+		"! a plugin's settings\n" +
+		"plugin.setting=one \\\n    two\n"
+
+	p, err := ParseProperties(strings.NewReader(original))
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+
+	// unchanged files are written back as they were read
+	var buf bytes.Buffer
+	if err := p.Encode(&buf); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if buf.String() != original {
+		t.Fatalf("expected\n%s\ngot\n%s", original, buf.String())
+	}
+
+	// changed entries are escaped, and others stay where they are
+	p.Set("level-type", "minecraft:flat")
+	p.Set("motd", "§aHello world")
+	p.Set("level-name", " my world #1 ")
+	p.Delete("plugin.setting")
+	want := "#Minecraft server properties\n#Sat Jan 01 00:00:00 UTC 2000\n" +
+		"motd=\\u00A7aHello\\ world\n" +
+		"level-type=minecraft\\:flat\n" +
+		"\n" +
+		"! a plugin's settings\n" +
+		"level-name=\\ my world \\#1 \n"
+
+	buf.Reset()
+	if err := p.Encode(&buf); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if buf.String() != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, buf.String())
+	}
+
+	reparsed, err := ParseProperties(&buf)
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	for _, key := range []string{"motd", "level-type", "level-name"} {
+		got, _ := reparsed.Get(key)
+		if want, _ := p.Get(key); got != want {
+			t.Errorf("expected `%s` to be `%q`, got `%q`", key, want, got)
+		}
+	}
+}
+
+func TestEscapeProperty(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		s    string
+		key  bool
+		want string
+	}{
+		{s: "plain", want: "plain"},
+		{s: " leading and inner spaces", want: "\\ leading and inner spaces"},
+		{s: "key with spaces", key: true, want: "key\\ with\\ spaces"},
+		{s: "a=b:c#d!e\\f", want: "a\\=b\\:c\\#d\\!e\\\\f"},
+		{s: "tab\tnewline\n", want: "tab\\tnewline\\n"},
+		{s: "été 😀", want: "\\u00E9t\\u00E9 \\uD83D\\uDE00"},
+	}
+
+	for _, tc := range testCases {
+		if got := escapeProperty(tc.s, tc.key); got != tc.want {
+			t.Errorf("expected `%q` to be escaped to `%s`, got `%s`", tc.s, tc.want, got)
+		}
+		if got, err := unescapeProperty(tc.want); err != nil || got != tc.s {
+			t.Errorf("expected `%s` to be unescaped to `%q`, got `%q` with error `%v`", tc.want, tc.s, got, err)
+		}
+	}
+}
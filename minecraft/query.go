@@ -0,0 +1,290 @@
+package minecraft
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+// QueryTimeout is how long the query client waits on the Minecraft server.
+const QueryTimeout = 5 * time.Second
+
+// GameSpy4 Query packet types and paddings, see https://wiki.vg/Query
+const (
+	queryTypeHandshake byte = 0x09
+	queryTypeStat      byte = 0x00
+
+	// the full stat response starts with "splitnum\x00\x80\x00"
+	queryFullStatPadding = 11
+	// the player list of the full stat response starts with
+	// "\x01player_\x00\x00"
+	queryPlayersPadding = 10
+
+	// queryMaxPacketLength is the longest packet the query client accepts.
+	queryMaxPacketLength = 1 << 16
+)
+
+var queryMagic = []byte{0xfe, 0xfd}
+
+var (
+	ErrMalformedQuery = errors.New("malformed query response")
+	ErrQueryDisabled  = errors.New("query is not enabled in the server properties")
+)
+
+// QueryBasic asks the Minecraft server at addr for its basic stat using the
+// GameSpy4 Query protocol. The plugins and players of the returned status are
+// empty since the basic stat doesn't include them.
+func QueryBasic(addr string, timeout time.Duration) (*api.QueryStatus, error) {
+	conn, session, token, err := queryHandshake(addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	response, err := queryRequest(conn, queryTypeStat, session, token)
+	if err != nil {
+		return nil, err
+	}
+
+	r := bytes.NewBuffer(response)
+	status := &api.QueryStatus{Plugins: []string{}, Players: []string{}}
+
+	fields := make([]string, 5)
+	for i := range fields {
+		if fields[i], err = readCString(r); err != nil {
+			return nil, err
+		}
+	}
+	status.Motd, status.GameType, status.Map = fields[0], fields[1], fields[2]
+	if status.NumPlayers, err = strconv.Atoi(fields[3]); err != nil {
+		return nil, ErrMalformedQuery
+	}
+	if status.MaxPlayers, err = strconv.Atoi(fields[4]); err != nil {
+		return nil, ErrMalformedQuery
+	}
+
+	// the host port is the only little-endian field of the protocol
+	if r.Len() < 2 {
+		return nil, ErrMalformedQuery
+	}
+	status.HostPort = int(binary.LittleEndian.Uint16(r.Next(2)))
+	if status.HostIp, err = readCString(r); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// QueryFull asks the Minecraft server at addr for its full stat using the
+// GameSpy4 Query protocol.
+func QueryFull(addr string, timeout time.Duration) (*api.QueryStatus, error) {
+	conn, session, token, err := queryHandshake(addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// the full stat request is a basic stat request padded to 15 bytes
+	response, err := queryRequest(conn, queryTypeStat, session, append(token, 0, 0, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	r := bytes.NewBuffer(response)
+	if r.Len() < queryFullStatPadding {
+		return nil, ErrMalformedQuery
+	}
+	r.Next(queryFullStatPadding)
+
+	values := make(map[string]string)
+	for {
+		key, err := readCString(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(key) == 0 {
+			break
+		}
+		if values[key], err = readCString(r); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.Len() < queryPlayersPadding {
+		return nil, ErrMalformedQuery
+	}
+	r.Next(queryPlayersPadding)
+
+	players := make([]string, 0)
+	for {
+		player, err := readCString(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(player) == 0 {
+			break
+		}
+		players = append(players, player)
+	}
+
+	status := &api.QueryStatus{
+		Motd:     values["hostname"],
+		GameType: values["gametype"],
+		GameId:   ref(values["game_id"]),
+		Version:  ref(values["version"]),
+		Map:      values["map"],
+		HostIp:   values["hostip"],
+		Players:  players,
+	}
+	status.ServerMod, status.Plugins = parseQueryPlugins(values["plugins"])
+
+	for key, field := range map[string]*int{
+		"numplayers": &status.NumPlayers,
+		"maxplayers": &status.MaxPlayers,
+		"hostport":   &status.HostPort,
+	} {
+		if *field, err = strconv.Atoi(values[key]); err != nil {
+			return nil, ErrMalformedQuery
+		}
+	}
+
+	return status, nil
+}
+
+// parseQueryPlugins splits the plugins value of a full stat, formatted like
+// `CraftBukkit on Bukkit 1.20.6: WorldEdit 7.3.2; Essentials 2.20.1`, into the
+// server mod and its plugins. The vanilla Minecraft server reports no server
+// mod and no plugins.
+func parseQueryPlugins(value string) (*string, []string) {
+	plugins := make([]string, 0)
+	if len(value) == 0 {
+		return ref(""), plugins
+	}
+
+	serverMod, list, found := strings.Cut(value, ": ")
+	if !found {
+		return ref(value), plugins
+	}
+
+	for _, plugin := range strings.Split(list, "; ") {
+		if len(plugin) > 0 {
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	return ref(serverMod), plugins
+}
+
+// queryHandshake connects to the query port at addr and returns the session
+// ID and challenge token to use in the stat request.
+func queryHandshake(addr string, timeout time.Duration) (conn net.Conn, session []byte, token []byte, err error) {
+	conn, err = net.DialTimeout("udp", addr, timeout)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	// the Minecraft server ignores the upper 4 bits of every byte
+	session = binary.BigEndian.AppendUint32(nil, rand.Uint32()&0x0f0f0f0f)
+
+	response, err := queryRequest(conn, queryTypeHandshake, session, nil)
+	if err != nil {
+		conn.Close()
+		return nil, nil, nil, err
+	}
+
+	// the challenge token is sent as a null terminated decimal string but has
+	// to be sent back as a big-endian integer
+	tokenStr, err := readCString(bytes.NewBuffer(response))
+	if err != nil {
+		conn.Close()
+		return nil, nil, nil, err
+	}
+	tokenInt, err := strconv.ParseInt(tokenStr, 10, 32)
+	if err != nil {
+		conn.Close()
+		return nil, nil, nil, ErrMalformedQuery
+	}
+
+	return conn, session, binary.BigEndian.AppendUint32(nil, uint32(tokenInt)), nil
+}
+
+// queryRequest sends a request of the given type and returns the payload of
+// the response.
+func queryRequest(conn net.Conn, typ byte, session []byte, payload []byte) ([]byte, error) {
+	request := append([]byte{}, queryMagic...)
+	request = append(request, typ)
+	request = append(request, session...)
+	request = append(request, payload...)
+	if _, err := conn.Write(request); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, queryMaxPacketLength)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	response := buf[:n]
+
+	if len(response) < 5 || response[0] != typ || !bytes.Equal(response[1:5], session) {
+		return nil, ErrMalformedQuery
+	}
+
+	return response[5:], nil
+}
+
+// readCString reads a null terminated string.
+func readCString(r *bytes.Buffer) (string, error) {
+	s, err := r.ReadString(0)
+	if err != nil {
+		return "", ErrMalformedQuery
+	}
+
+	return s[:len(s)-1], nil
+}
+
+// Query implements api.MinecraftServerInterface.
+//
+// Query asks the Minecraft server for its full stat through its query port.
+func (m *JavaMinecraftServer) Query() (*api.QueryStatus, error) {
+	m.Lock()
+	if m.properties == nil {
+		m.Unlock()
+		return nil, ErrNilConfig
+	}
+	if m.properties.EnableQuery == nil || !*m.properties.EnableQuery {
+		m.Unlock()
+		return nil, ErrQueryDisabled
+	}
+	if m.process == nil && !m.console.Attached() {
+		m.Unlock()
+		return nil, ErrServerNotRunning
+	}
+	addr := queryAddress(m.properties)
+	m.Unlock()
+
+	return QueryFull(addr, QueryTimeout)
+}
+
+// queryAddress returns the address of the query port of the Minecraft server
+// with the given properties.
+func queryAddress(props *api.ServerProperties) string {
+	host := "127.0.0.1"
+	if props.ServerIP != nil && len(*props.ServerIP) > 0 {
+		host = *props.ServerIP
+	}
+	port := 25565
+	if props.QueryPort != nil {
+		port = *props.QueryPort
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
@@ -0,0 +1,187 @@
+package minecraft
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+const fakeQueryToken = 9513307
+
+// fakeQueryServer is an in-process stand-in for the query port of a
+// Minecraft server with a CraftBukkit server mod and two online players.
+func fakeQueryServer(t *testing.T) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if response := fakeQueryResponse(buf[:n]); response != nil {
+				_, _ = conn.WriteTo(response, addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func fakeQueryResponse(request []byte) []byte {
+	if len(request) < 7 || !bytes.Equal(request[:2], queryMagic) {
+		return nil
+	}
+	typ, session, payload := request[2], request[3:7], request[7:]
+
+	response := bytes.NewBuffer([]byte{typ})
+	response.Write(session)
+	cstring := func(s string) { response.WriteString(s + "\x00") }
+
+	if typ == queryTypeHandshake {
+		cstring(strconv.Itoa(fakeQueryToken))
+		return response.Bytes()
+	}
+
+	// stat requests without the right challenge token are ignored
+	if len(payload) < 4 || binary.BigEndian.Uint32(payload) != fakeQueryToken {
+		return nil
+	}
+
+	if len(payload) == 4 {
+		cstring("A Minecraft Server")
+		cstring("SMP")
+		cstring("world")
+		cstring("2")
+		cstring("20")
+		response.Write(binary.LittleEndian.AppendUint16(nil, 25565))
+		cstring("127.0.0.1")
+		return response.Bytes()
+	}
+
+	response.WriteString("splitnum\x00\x80\x00")
+	for _, kv := range [][2]string{
+		{"hostname", "A Minecraft Server"},
+		{"gametype", "SMP"},
+		{"game_id", "MINECRAFT"},
+		{"version", "1.20.6"},
+		{"plugins", "CraftBukkit on Bukkit 1.20.6-R0.1-SNAPSHOT: WorldEdit 7.3.2; Essentials 2.20.1"},
+		{"map", "world"},
+		{"numplayers", "2"},
+		{"maxplayers", "20"},
+		{"hostport", "25565"},
+		{"hostip", "127.0.0.1"},
+	} {
+		cstring(kv[0])
+		cstring(kv[1])
+	}
+	cstring("")
+	response.WriteString("\x01player_\x00\x00")
+	cstring("player1")
+	cstring("player2")
+	cstring("")
+
+	return response.Bytes()
+}
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+
+	addr := fakeQueryServer(t)
+
+	basic, err := QueryBasic(addr, QueryTimeout)
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	wantBasic := &api.QueryStatus{
+		Motd:       "A Minecraft Server",
+		GameType:   "SMP",
+		Map:        "world",
+		NumPlayers: 2,
+		MaxPlayers: 20,
+		HostPort:   25565,
+		HostIp:     "127.0.0.1",
+		Plugins:    []string{},
+		Players:    []string{},
+	}
+	if !reflect.DeepEqual(wantBasic, basic) {
+		t.Errorf("expected basic stat `%+v`, got `%+v`", wantBasic, basic)
+	}
+
+	full, err := QueryFull(addr, QueryTimeout)
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	wantFull := &api.QueryStatus{
+		Motd:       "A Minecraft Server",
+		GameType:   "SMP",
+		GameId:     ref("MINECRAFT"),
+		Version:    ref("1.20.6"),
+		ServerMod:  ref("CraftBukkit on Bukkit 1.20.6-R0.1-SNAPSHOT"),
+		Plugins:    []string{"WorldEdit 7.3.2", "Essentials 2.20.1"},
+		Map:        "world",
+		NumPlayers: 2,
+		MaxPlayers: 20,
+		HostPort:   25565,
+		HostIp:     "127.0.0.1",
+		Players:    []string{"player1", "player2"},
+	}
+	if !reflect.DeepEqual(wantFull, full) {
+		t.Errorf("expected full stat `%+v`, got `%+v`", wantFull, full)
+	}
+}
+
+func TestParseQueryPlugins(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		value         string
+		wantServerMod string
+		wantPlugins   []string
+	}{
+		{value: "", wantServerMod: "", wantPlugins: []string{}},
+		{value: "Paper on 1.20.6", wantServerMod: "Paper on 1.20.6", wantPlugins: []string{}},
+		{value: "Paper on 1.20.6: WorldEdit 7.3.2", wantServerMod: "Paper on 1.20.6", wantPlugins: []string{"WorldEdit 7.3.2"}},
+	}
+
+	for _, tc := range testCases {
+		serverMod, plugins := parseQueryPlugins(tc.value)
+		if *serverMod != tc.wantServerMod {
+			t.Errorf("expected server mod `%s`, got `%s`", tc.wantServerMod, *serverMod)
+		}
+		if !reflect.DeepEqual(tc.wantPlugins, plugins) {
+			t.Errorf("expected plugins `%v`, got `%v`", tc.wantPlugins, plugins)
+		}
+	}
+}
+
+func TestQueryDisabled(t *testing.T) {
+	t.Parallel()
+
+	server := JavaMinecraftServer{}
+	if _, err := server.Query(); err != ErrNilConfig {
+		t.Fatalf("expected error `%v`, got `%v`", ErrNilConfig, err)
+	}
+
+	server.CreateProperties()
+	if _, err := server.Query(); err != ErrQueryDisabled {
+		t.Fatalf("expected error `%v`, got `%v`", ErrQueryDisabled, err)
+	}
+
+	server.properties.EnableQuery = ref(true)
+	if _, err := server.Query(); err != ErrServerNotRunning {
+		t.Fatalf("expected error `%v`, got `%v`", ErrServerNotRunning, err)
+	}
+}
@@ -0,0 +1,278 @@
+package minecraft
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+// RCON packet types, see https://wiki.vg/RCON
+const (
+	rconTypeResponse int32 = 0
+	rconTypeCommand  int32 = 2
+	rconTypeLogin    int32 = 3
+)
+
+const (
+	// RCONTimeout is how long the RCON client waits on the Minecraft server
+	// when connecting, logging in and running commands.
+	RCONTimeout = 10 * time.Second
+
+	// rconMaxCommandLength is the longest command the Minecraft server accepts
+	// over RCON.
+	rconMaxCommandLength = 1446
+
+	// rconMaxPacketLength is the longest packet the RCON client accepts.
+	rconMaxPacketLength = 4096 + 10
+)
+
+var (
+	ErrRCONAuth           = errors.New("RCON authentication failed")
+	ErrRCONCommandTooLong = errors.New("command is too long to be sent over RCON")
+	ErrRCONDisabled       = errors.New("RCON is not enabled in the server properties")
+	ErrRCONMalformed      = errors.New("malformed RCON packet")
+)
+
+// RCONClient runs commands on a Minecraft server through its RCON port.
+type RCONClient struct {
+	mutex   sync.Mutex
+	conn    net.Conn
+	nextID  int32
+	timeout time.Duration
+}
+
+// DialRCON connects to the RCON port of a Minecraft server at addr and logs
+// in with password.
+func DialRCON(addr, password string, timeout time.Duration) (*RCONClient, error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &RCONClient{conn: conn, nextID: 1, timeout: timeout}
+	if err := c.login(password); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *RCONClient) login(password string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	_ = c.conn.SetDeadline(time.Now().Add(c.timeout))
+
+	id := c.id()
+	if err := writeRCONPacket(c.conn, id, rconTypeLogin, password); err != nil {
+		return err
+	}
+
+	replyID, _, _, err := readRCONPacket(c.conn)
+	if err != nil {
+		return err
+	}
+	// the server replies with an ID of -1 if the password is wrong
+	if replyID != id {
+		return ErrRCONAuth
+	}
+
+	return nil
+}
+
+// Command runs cmd on the Minecraft server and returns its response.
+func (c *RCONClient) Command(cmd string) (string, error) {
+	if len(cmd) > rconMaxCommandLength {
+		return "", ErrRCONCommandTooLong
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	_ = c.conn.SetDeadline(time.Now().Add(c.timeout))
+
+	id := c.id()
+	if err := writeRCONPacket(c.conn, id, rconTypeCommand, cmd); err != nil {
+		return "", err
+	}
+
+	// Long responses are split over several packets with nothing marking the
+	// last one, so an invalid request is sent right after the command. The
+	// server answers requests in order, so the response to the invalid
+	// request comes after the last packet of the command's response.
+	endID := c.id()
+	if err := writeRCONPacket(c.conn, endID, rconTypeResponse, ""); err != nil {
+		return "", err
+	}
+
+	var response bytes.Buffer
+	for {
+		replyID, _, body, err := readRCONPacket(c.conn)
+		if err != nil {
+			return "", err
+		}
+
+		switch replyID {
+		case id:
+			response.WriteString(body)
+		case endID:
+			return response.String(), nil
+		default:
+			return "", ErrRCONMalformed
+		}
+	}
+}
+
+// Close closes the connection to the Minecraft server.
+func (c *RCONClient) Close() error {
+	return c.conn.Close()
+}
+
+// id returns the next request ID. c must be locked.
+func (c *RCONClient) id() int32 {
+	id := c.nextID
+	c.nextID++
+	if c.nextID < 1 {
+		c.nextID = 1
+	}
+
+	return id
+}
+
+// writeRCONPacket writes a packet with the given request ID, type and body.
+// Packets are laid out as little-endian length, request ID and type fields
+// followed by the null terminated body and a null pad byte.
+func writeRCONPacket(w io.Writer, id, typ int32, body string) error {
+	packet := make([]byte, 0, 14+len(body))
+	packet = binary.LittleEndian.AppendUint32(packet, uint32(10+len(body)))
+	packet = binary.LittleEndian.AppendUint32(packet, uint32(id))
+	packet = binary.LittleEndian.AppendUint32(packet, uint32(typ))
+	packet = append(packet, body...)
+	packet = append(packet, 0, 0)
+
+	_, err := w.Write(packet)
+
+	return err
+}
+
+// readRCONPacket reads a packet written by writeRCONPacket.
+func readRCONPacket(r io.Reader) (id, typ int32, body string, err error) {
+	var length int32
+	if err = binary.Read(r, binary.LittleEndian, &length); err != nil {
+		return
+	}
+	if length < 10 || length > rconMaxPacketLength {
+		err = ErrRCONMalformed
+		return
+	}
+
+	packet := make([]byte, length)
+	if _, err = io.ReadFull(r, packet); err != nil {
+		return
+	}
+
+	id = int32(binary.LittleEndian.Uint32(packet[0:4]))
+	typ = int32(binary.LittleEndian.Uint32(packet[4:8]))
+	body = string(bytes.TrimRight(packet[8:], "\x00"))
+
+	return
+}
+
+// AttachRCON implements api.MinecraftServerInterface.
+//
+// AttachRCON attaches the console to a Minecraft server that is already
+// running, such as one that outlived a previous run of the server controller,
+// through its RCON port using the RCON settings of the server properties. The
+// server is reported as ProcessRunning until it is stopped.
+func (m *JavaMinecraftServer) AttachRCON() error {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.process != nil || m.console.Attached() {
+		return ErrServerRunning
+	}
+	if m.properties == nil {
+		return ErrNilConfig
+	}
+	if m.properties.EnableRCON == nil || !*m.properties.EnableRCON {
+		return ErrRCONDisabled
+	}
+
+	var password string
+	if m.properties.RCONPassword != nil {
+		password = *m.properties.RCONPassword
+	}
+
+	addr := rconAddress(m.properties)
+	client, err := DialRCON(addr, password, RCONTimeout)
+	if err != nil {
+		return err
+	}
+
+	if m.console == nil {
+		m.console = NewConsole()
+	}
+	console := m.console
+	console.AttachRCON(client, func(err error) { m.handleRCONLost(console, err) })
+	m.state = ProcessRunning
+
+	log.Println("attached to minecraft server over RCON at", addr)
+
+	return nil
+}
+
+// isConnectionLost reports whether err means the connection to the Minecraft
+// server was closed or broke down, rather than that a command was rejected.
+func isConnectionLost(err error) bool {
+	var netErr net.Error
+
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &netErr)
+}
+
+// handleRCONLost updates the state of the Minecraft server once the RCON
+// connection to it through console is lost. A closed connection means the
+// server most likely stopped, anything else that it crashed or hangs.
+func (m *JavaMinecraftServer) handleRCONLost(console *Console, err error) {
+	m.Lock()
+	defer m.Unlock()
+
+	// the server was stopped, started or attached again in the meantime
+	if m.console != console || m.process != nil || console.Attached() || m.state != ProcessRunning {
+		return
+	}
+
+	m.players.reset()
+	if errors.Is(err, io.EOF) || errors.Is(err, syscall.ECONNRESET) {
+		log.Println("minecraft server attached over RCON closed the connection")
+		m.state = ProcessStopped
+		return
+	}
+
+	log.Println("lost the RCON connection to the minecraft server:", err)
+	m.state = ProcessCrashed
+}
+
+// rconAddress returns the address of the RCON port of the Minecraft server
+// with the given properties.
+func rconAddress(props *api.ServerProperties) string {
+	host := "127.0.0.1"
+	if props.ServerIP != nil && len(*props.ServerIP) > 0 {
+		host = *props.ServerIP
+	}
+	port := 25575
+	if props.RCONPort != nil {
+		port = *props.RCONPort
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
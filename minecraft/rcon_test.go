@@ -0,0 +1,229 @@
+package minecraft
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+const fakeRCONPassword = "hunter2"
+
+// fakeRCONHangUp is the response that makes fakeRCONServer close the
+// connection instead of responding, like a Minecraft server that went down.
+const fakeRCONHangUp = "\x00hang up"
+
+// fakeRCONServer is an in-process stand-in for the RCON port of a Minecraft
+// server. It responds to commands the way handle does, splitting responses
+// into packets of at most 4096 bytes like the Minecraft server does.
+func fakeRCONServer(t *testing.T, handle func(cmd string) string) (host string, port int) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeRCON(conn, handle)
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+
+	return addr.IP.String(), addr.Port
+}
+
+func serveFakeRCON(conn net.Conn, handle func(cmd string) string) {
+	defer conn.Close()
+
+	authenticated := false
+	for {
+		id, typ, body, err := readRCONPacket(conn)
+		if err != nil {
+			return
+		}
+
+		switch {
+		case typ == rconTypeLogin:
+			authenticated = body == fakeRCONPassword
+			if !authenticated {
+				id = -1
+			}
+			_ = writeRCONPacket(conn, id, rconTypeCommand, "")
+		case typ == rconTypeCommand && authenticated:
+			response := handle(body)
+			if response == fakeRCONHangUp {
+				return
+			}
+			for len(response) > 4096 {
+				_ = writeRCONPacket(conn, id, rconTypeResponse, response[:4096])
+				response = response[4096:]
+			}
+			_ = writeRCONPacket(conn, id, rconTypeResponse, response)
+		default:
+			_ = writeRCONPacket(conn, id, rconTypeResponse, "Unknown request "+strconv.FormatInt(int64(typ), 16))
+		}
+	}
+}
+
+func TestRCONClient(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("a", 10000)
+	host, port := fakeRCONServer(t, func(cmd string) string {
+		switch cmd {
+		case "seed":
+			return "Seed: [42]"
+		case "long":
+			return long
+		}
+		return "Unknown or incomplete command, see below for error"
+	})
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+
+	if _, err := DialRCON(addr, "wrong", RCONTimeout); err != ErrRCONAuth {
+		t.Fatalf("expected error `%v`, got `%v`", ErrRCONAuth, err)
+	}
+
+	client, err := DialRCON(addr, fakeRCONPassword, RCONTimeout)
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	defer client.Close()
+
+	testCases := []struct {
+		cmd  string
+		want string
+	}{
+		{cmd: "seed", want: "Seed: [42]"},
+		{cmd: "long", want: long},
+		{cmd: "foo", want: "Unknown or incomplete command, see below for error"},
+	}
+
+	for _, tc := range testCases {
+		got, err := client.Command(tc.cmd)
+		if err != nil {
+			t.Errorf("%s: expected no error, got `%v`", tc.cmd, err)
+		}
+		if got != tc.want {
+			t.Errorf("%s: expected response of length %d, got %d", tc.cmd, len(tc.want), len(got))
+		}
+	}
+
+	if _, err := client.Command(strings.Repeat("a", rconMaxCommandLength+1)); err != ErrRCONCommandTooLong {
+		t.Errorf("expected error `%v`, got `%v`", ErrRCONCommandTooLong, err)
+	}
+}
+
+func TestAttachRCON(t *testing.T) {
+	t.Parallel()
+
+	commands := make(chan string, 10)
+	host, port := fakeRCONServer(t, func(cmd string) string {
+		commands <- cmd
+		switch {
+		case strings.HasPrefix(cmd, "whitelist add "):
+			return "Added " + strings.TrimPrefix(cmd, "whitelist add ") + " to the whitelist"
+		case cmd == "stop":
+			return "Stopping the server"
+		}
+		return ""
+	})
+
+	server := JavaMinecraftServer{
+		allowlist: &api.Allowlist{},
+		console:   NewConsole(),
+	}
+	if err := server.AttachRCON(); err != ErrNilConfig {
+		t.Fatalf("expected error `%v`, got `%v`", ErrNilConfig, err)
+	}
+
+	server.CreateProperties()
+	if err := server.AttachRCON(); err != ErrRCONDisabled {
+		t.Fatalf("expected error `%v`, got `%v`", ErrRCONDisabled, err)
+	}
+
+	server.properties.EnableRCON = ref(true)
+	server.properties.ServerIP = ref(host)
+	server.properties.RCONPort = ref(port)
+	server.properties.RCONPassword = ref(fakeRCONPassword)
+	if err := server.AttachRCON(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if state := server.State(); state != ProcessRunning {
+		t.Fatalf("expected state `%s`, got `%s`", ProcessRunning, state)
+	}
+	if err := server.Start(); err != ErrServerRunning {
+		t.Fatalf("expected error `%v`, got `%v`", ErrServerRunning, err)
+	}
+
+	lines, cancel := server.SubscribeConsole()
+	defer cancel()
+
+	if err := server.AllowPlayer(&api.PlayerInfo{Name: ref("player1")}); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if cmd := <-commands; cmd != "whitelist add player1" {
+		t.Fatalf("expected command `whitelist add player1`, got `%s`", cmd)
+	}
+	if len(*server.allowlist) != 1 {
+		t.Fatalf("expected allowlist to be length 1, got %d", len(*server.allowlist))
+	}
+	if line := receiveLine(t, lines); line.Stream != api.Rcon || line.Text != "Added player1 to the whitelist" {
+		t.Fatalf("expected RCON response to be written to the console, got `%v`", line)
+	}
+
+	if err := server.Stop(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if cmd := <-commands; cmd != "stop" {
+		t.Fatalf("expected command `stop`, got `%s`", cmd)
+	}
+	if state := server.State(); state != ProcessStopped {
+		t.Fatalf("expected state `%s`, got `%s`", ProcessStopped, state)
+	}
+	if server.console.Attached() {
+		t.Fatal("expected console to be detached")
+	}
+}
+
+func TestRCONConnectionLost(t *testing.T) {
+	t.Parallel()
+
+	host, port := fakeRCONServer(t, func(cmd string) string { return fakeRCONHangUp })
+
+	server := JavaMinecraftServer{console: NewConsole()}
+	server.CreateProperties()
+	server.properties.EnableRCON = ref(true)
+	server.properties.ServerIP = ref(host)
+	server.properties.RCONPort = ref(port)
+	server.properties.RCONPassword = ref(fakeRCONPassword)
+	if err := server.AttachRCON(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+
+	if err := server.SendCommand("list"); err == nil {
+		t.Fatal("expected an error sending a command over a closed connection")
+	}
+	if server.console.Attached() {
+		t.Fatal("expected console to be detached")
+	}
+	waitForState(t, &server, ProcessStopped)
+
+	// the server can be attached to again
+	if err := server.AttachRCON(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if state := server.State(); state != ProcessRunning {
+		t.Fatalf("expected state `%s`, got `%s`", ProcessRunning, state)
+	}
+}
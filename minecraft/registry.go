@@ -0,0 +1,616 @@
+package minecraft
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+const (
+	// registryFile is the file in the registry directory the registered
+	// Minecraft servers are saved to.
+	registryFile = "servers.json"
+	// jarsDirName is the directory in the registry directory the server jars
+	// every Minecraft server runs are cached in. It can't be a server ID.
+	jarsDirName = "jars"
+
+	DefaultServerPort = 25565
+	DefaultRCONPort   = 25575
+)
+
+var serverIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// ServerFilepaths returns the filepaths of the configuration files of a
+// Minecraft server with its data in dir. The versions file is shared by every
+// Minecraft server.
+func ServerFilepaths(dir, versions string) *MinecraftServerConfigFilepaths {
+	return &MinecraftServerConfigFilepaths{
+		Allowlist:      path.Join(dir, "whitelist.json"),
+		Args:           path.Join(dir, "args.json"),
+		BackupSchedule: path.Join(dir, "backup-schedule.json"),
+		BannedIPs:      path.Join(dir, "banned-ips.json"),
+		BannedPlayers:  path.Join(dir, "banned-players.json"),
+		Config:         path.Join(dir, "config.json"),
+		Ops:            path.Join(dir, "ops.json"),
+		Properties:     path.Join(dir, "properties.json"),
+		Versions:       versions,
+	}
+}
+
+// registeredServer is a Minecraft server in a ServerRegistry.
+type registeredServer struct {
+	ID  string `json:"id"`
+	Dir string `json:"dir"`
+
+	server       *JavaMinecraftServer
+	stopWatching func()
+	stopBackups  func()
+}
+
+// ServerRegistry manages named Minecraft servers, each with its own data
+// directory. The registered servers are saved to the registry directory, which
+// is also where the data directories of new servers are created.
+type ServerRegistry struct {
+	dir             string
+	versions        string
+	manifestBaseURL string
+	java            *JavaRegistry
+	jars            *JarCache
+	servers         map[string]*registeredServer
+
+	mutex sync.Mutex
+}
+
+func NewServerRegistry(dir, versions string) *ServerRegistry {
+	return &ServerRegistry{
+		dir:      dir,
+		versions: versions,
+		jars:     NewJarCache(path.Join(dir, jarsDirName)),
+		servers:  make(map[string]*registeredServer),
+	}
+}
+
+// Load loads the Minecraft servers saved in the registry directory.
+func (r *ServerRegistry) Load() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := os.MkdirAll(r.dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	data, err := readJSON(path.Join(r.dir, registryFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var entries []registeredServer
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		rs, err := r.open(entry.ID, entry.Dir)
+		if err != nil {
+			return fmt.Errorf("loading minecraft server %s: %w", entry.ID, err)
+		}
+		r.servers[entry.ID] = rs
+	}
+
+	return nil
+}
+
+// Adopt registers the Minecraft server with its data in dir, which is left
+// where it is.
+func (r *ServerRegistry) Adopt(id, dir string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.checkNewID(id); err != nil {
+		return err
+	}
+
+	rs, err := r.open(id, dir)
+	if err != nil {
+		return err
+	}
+	if err := r.register(rs); err != nil {
+		rs.close()
+		return err
+	}
+
+	return nil
+}
+
+// Servers implements api.ServerRegistryInterface.
+func (r *ServerRegistry) Servers() []api.ServerInstance {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	instances := make([]api.ServerInstance, 0, len(r.servers))
+	for _, rs := range r.servers {
+		instances = append(instances, rs.instance())
+	}
+	slices.SortFunc(instances, func(a, b api.ServerInstance) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	return instances
+}
+
+// Instance implements api.ServerRegistryInterface.
+func (r *ServerRegistry) Instance(id string) (*api.ServerInstance, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	rs, ok := r.servers[id]
+	if !ok {
+		return nil, api.ErrServerNotFound
+	}
+
+	return ref(rs.instance()), nil
+}
+
+// Server implements api.ServerRegistryInterface.
+func (r *ServerRegistry) Server(id string) (api.MinecraftServerInterface, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	rs, ok := r.servers[id]
+	if !ok {
+		return nil, api.ErrServerNotFound
+	}
+
+	return rs.server, nil
+}
+
+// CreateServer implements api.ServerRegistryInterface.
+//
+// CreateServer creates the data directory of the Minecraft server in the
+// registry directory with the default configuration, the requested version
+// and ports no other Minecraft server uses.
+func (r *ServerRegistry) CreateServer(req api.CreateServerRequest) (*api.ServerInstance, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.checkNewID(req.Id); err != nil {
+		return nil, err
+	}
+	serverPort, rconPort, err := r.allocatePorts(req.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := path.Join(r.dir, req.Id)
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("%w: %s already exists", api.ErrServerExists, dir)
+	}
+
+	rs, err := r.open(req.Id, dir)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+
+	if req.Version != nil {
+		if err := rs.server.SetVersion(*req.Version); err != nil {
+			rs.discard()
+			return nil, fmt.Errorf("%w: %w", api.ErrInvalidServer, err)
+		}
+	} else if err := rs.server.SetVersion(VersionLatest); err != nil {
+		// the version catalog is empty, so there's no version to create the
+		// server with
+		rs.discard()
+		return nil, fmt.Errorf("%w: no latest version to create the server with: %w", ErrCatalogRefresh, err)
+	}
+
+	if err := rs.setPorts(serverPort, rconPort); err != nil {
+		rs.discard()
+		return nil, err
+	}
+
+	if err := r.register(rs); err != nil {
+		rs.discard()
+		return nil, err
+	}
+	log.Printf("created minecraft server %s in %s", req.Id, dir)
+
+	return ref(rs.instance()), nil
+}
+
+// CloneServer implements api.ServerRegistryInterface.
+//
+// CloneServer copies the data directory of a stopped Minecraft server,
+// including its worlds, and gives the copy ports no other Minecraft server
+// uses.
+func (r *ServerRegistry) CloneServer(id string, req api.CloneServerRequest) (*api.ServerInstance, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	src, ok := r.servers[id]
+	if !ok {
+		return nil, api.ErrServerNotFound
+	}
+	if src.inUse() {
+		return nil, fmt.Errorf("%w: %w", api.ErrServerInUse, ErrServerRunning)
+	}
+	if err := r.checkNewID(req.Id); err != nil {
+		return nil, err
+	}
+	serverPort, rconPort, err := r.allocatePorts(req.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := path.Join(r.dir, req.Id)
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("%w: %s already exists", api.ErrServerExists, dir)
+	}
+
+	if err := copyDir(src.Dir, dir); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+
+	rs, err := r.open(req.Id, dir)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+	if err := rs.setPorts(serverPort, rconPort); err != nil {
+		rs.discard()
+		return nil, err
+	}
+
+	if err := r.register(rs); err != nil {
+		rs.discard()
+		return nil, err
+	}
+	log.Printf("cloned minecraft server %s to %s in %s", id, req.Id, dir)
+
+	return ref(rs.instance()), nil
+}
+
+// DeleteServer implements api.ServerRegistryInterface.
+//
+// DeleteServer deletes a stopped Minecraft server along with its data
+// directory.
+func (r *ServerRegistry) DeleteServer(id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	rs, ok := r.servers[id]
+	if !ok {
+		return api.ErrServerNotFound
+	}
+	if rs.inUse() {
+		return fmt.Errorf("%w: %w", api.ErrServerInUse, ErrServerRunning)
+	}
+
+	delete(r.servers, id)
+	if err := r.save(); err != nil {
+		return err
+	}
+	rs.close()
+	log.Printf("deleted minecraft server %s", id)
+
+	return os.RemoveAll(rs.Dir)
+}
+
+// SetManifestBaseURL sets the manifest base URL of every Minecraft server.
+func (r *ServerRegistry) SetManifestBaseURL(baseURL string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.manifestBaseURL = baseURL
+	for _, rs := range r.servers {
+		rs.server.SetManifestBaseURL(baseURL)
+	}
+}
+
+// SetJavaDirs sets the directories searched for Java runtimes, which are
+// shared by every Minecraft server.
+func (r *ServerRegistry) SetJavaDirs(dirs []string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.java = NewJavaRegistry(dirs...)
+	for _, rs := range r.servers {
+		rs.server.Lock()
+		rs.server.java = r.java
+		rs.server.Unlock()
+	}
+}
+
+// JavaRuntimes returns the Java runtimes found on the host.
+func (r *ServerRegistry) JavaRuntimes() []api.JavaRuntime {
+	r.mutex.Lock()
+	java := r.java
+	r.mutex.Unlock()
+
+	if java == nil {
+		return nil
+	}
+
+	return java.Runtimes()
+}
+
+// RefreshVersions refreshes the available versions of every Minecraft server
+// from the version manifest, saves them to the versions file and returns them.
+func (r *ServerRegistry) RefreshVersions(ctx context.Context) ([]string, error) {
+	r.mutex.Lock()
+	catalog := NewCatalog(r.manifestBaseURL)
+	r.mutex.Unlock()
+
+	known := make(VersionMap)
+	if data, err := readJSON(r.versions); err == nil {
+		if err := known.Load(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Println("refreshing version catalog from", catalog.BaseURL)
+	versions, err := catalog.Refresh(ctx, known)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("version catalog refreshed, %d versions available", len(versions))
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, rs := range r.servers {
+		rs.server.Lock()
+		rs.server.versions = versions
+		rs.server.Unlock()
+	}
+
+	if err := saveVersions(r.versions, versions); err != nil {
+		return nil, err
+	}
+
+	return versions.Versions(), nil
+}
+
+// checkNewID returns an error if id can't be used for a new Minecraft server.
+// r must be locked.
+func (r *ServerRegistry) checkNewID(id string) error {
+	if !serverIDPattern.MatchString(id) {
+		return fmt.Errorf("%w: `%s`", api.ErrInvalidServerID, id)
+	}
+	if id == jarsDirName {
+		return fmt.Errorf("%w: `%s` is reserved", api.ErrInvalidServerID, id)
+	}
+	if _, ok := r.servers[id]; ok {
+		return fmt.Errorf("%w: %s", api.ErrServerExists, id)
+	}
+
+	return nil
+}
+
+// allocatePorts returns the requested server port, or the lowest one no other
+// Minecraft server uses, and the lowest RCON port no other Minecraft server
+// uses. r must be locked.
+func (r *ServerRegistry) allocatePorts(requested *int) (serverPort, rconPort int, err error) {
+	used := make(map[int]bool)
+	for _, rs := range r.servers {
+		props := rs.server.Properties()
+		if props == nil {
+			continue
+		}
+		for _, port := range []*int{props.ServerPort, props.QueryPort, props.RCONPort} {
+			if port != nil {
+				used[*port] = true
+			}
+		}
+	}
+
+	if requested != nil {
+		if *requested < 1 || *requested > 65535 {
+			return 0, 0, fmt.Errorf("%w: port %d", api.ErrInvalidServer, *requested)
+		}
+		if used[*requested] {
+			return 0, 0, fmt.Errorf("%w: %d", api.ErrPortInUse, *requested)
+		}
+		serverPort = *requested
+	} else {
+		serverPort = DefaultServerPort
+		for used[serverPort] {
+			serverPort++
+		}
+	}
+	used[serverPort] = true
+
+	rconPort = DefaultRCONPort
+	for used[rconPort] {
+		rconPort++
+	}
+
+	return serverPort, rconPort, nil
+}
+
+// open creates the Minecraft server of a registered server and loads its
+// configuration from dir, creating the default configuration if there is
+// none. r must be locked.
+func (r *ServerRegistry) open(id, dir string) (*registeredServer, error) {
+	if err := CreateServerFolder(dir); err != nil {
+		return nil, err
+	}
+
+	server := &JavaMinecraftServer{
+		console:         NewConsole(),
+		filepaths:       ServerFilepaths(dir, r.versions),
+		manifestBaseURL: r.manifestBaseURL,
+		java:            r.java,
+		jars:            r.jars,
+	}
+	if err := server.LoadConfigs(); err != nil {
+		return nil, err
+	}
+
+	return &registeredServer{
+		ID:           id,
+		Dir:          dir,
+		server:       server,
+		stopWatching: server.WatchConfigs(DefaultConfigWatchInterval),
+		stopBackups:  server.ScheduleBackups(),
+	}, nil
+}
+
+// register adds the Minecraft server rs to the registry and saves it. If it
+// can't be saved, rs is left out of the registry. r must be locked.
+func (r *ServerRegistry) register(rs *registeredServer) error {
+	r.servers[rs.ID] = rs
+	if err := r.save(); err != nil {
+		delete(r.servers, rs.ID)
+		return err
+	}
+
+	return nil
+}
+
+// save saves the registered servers to the registry file. r must be locked.
+func (r *ServerRegistry) save() error {
+	entries := make([]registeredServer, 0, len(r.servers))
+	for _, rs := range r.servers {
+		entries = append(entries, *rs)
+	}
+	slices.SortFunc(entries, func(a, b registeredServer) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return saveJSON(func(file io.Writer) error {
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}, path.Join(r.dir, registryFile))
+}
+
+// close stops watching the configuration files of the Minecraft server and
+// scheduling its backups.
+func (rs *registeredServer) close() {
+	rs.stopWatching()
+	rs.stopBackups()
+}
+
+// discard closes a Minecraft server that couldn't be created and deletes its
+// data directory.
+func (rs *registeredServer) discard() {
+	rs.close()
+	_ = os.RemoveAll(rs.Dir)
+}
+
+func (rs *registeredServer) instance() api.ServerInstance {
+	instance := api.ServerInstance{
+		Id:    rs.ID,
+		State: api.ServerStatusState(rs.server.State()),
+	}
+	if config := rs.server.Config(); config != nil {
+		instance.Version = config.Version
+	}
+	if props := rs.server.Properties(); props != nil {
+		if props.ServerPort != nil {
+			instance.ServerPort = *props.ServerPort
+		}
+		if props.RCONPort != nil {
+			instance.RconPort = *props.RCONPort
+		}
+	}
+
+	return instance
+}
+
+// inUse reports whether the Minecraft server is running, whether the server
+// controller launched it or attached to it.
+func (rs *registeredServer) inUse() bool {
+	state := rs.server.State()
+	return (state != ProcessStopped && state != ProcessCrashed) || rs.server.console.Attached()
+}
+
+// setPorts sets the ports of the Minecraft server and saves its
+// configuration, including server.properties, which the Minecraft server
+// reads its ports from.
+func (rs *registeredServer) setPorts(serverPort, rconPort int) error {
+	props := rs.server.Properties()
+	if props == nil {
+		return ErrNilConfig
+	}
+	props.ServerPort = ref(serverPort)
+	props.QueryPort = ref(serverPort)
+	props.RCONPort = ref(rconPort)
+	rs.server.SetProperties(props)
+
+	return rs.server.SaveConfigs()
+}
+
+// copyDir copies the directory src to dst, which must not exist.
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, name)
+		if err != nil {
+			return err
+		}
+		// backups aren't part of the server, and could be large
+		if d.IsDir() && rel == backupsDirName {
+			return filepath.SkipDir
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return os.Mkdir(target, info.Mode().Perm())
+		}
+		if !info.Mode().IsRegular() {
+			// e.g. the sockets of a running server
+			return nil
+		}
+
+		return copyFile(name, target, info.Mode().Perm())
+	})
+}
+
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer closeFile(in)
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		closeFile(out)
+		return err
+	}
+
+	return out.Close()
+}
+
+var _ api.ServerRegistryInterface = (*ServerRegistry)(nil)
@@ -0,0 +1,306 @@
+package minecraft
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+func newTestRegistry(t *testing.T, dir string) *ServerRegistry {
+	t.Helper()
+
+	versions := path.Join(t.TempDir(), "versions.json")
+	if err := os.WriteFile(versions, []byte(`{"1.20.4": {"link": ""}, "1.20.6": {"link": ""}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := NewServerRegistry(dir, versions)
+	if err := registry.Load(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+
+	return registry
+}
+
+func TestServerRegistry(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	registry := newTestRegistry(t, dir)
+
+	survival, err := registry.CreateServer(api.CreateServerRequest{Id: "survival"})
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	want := api.ServerInstance{Id: "survival", Version: "1.20.6", State: api.Stopped, ServerPort: 25565, RconPort: 25575}
+	if !reflect.DeepEqual(want, *survival) {
+		t.Fatalf("expected server `%+v`, got `%+v`", want, *survival)
+	}
+
+	creative, err := registry.CreateServer(api.CreateServerRequest{Id: "creative", Version: ref("1.20.4")})
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	want = api.ServerInstance{Id: "creative", Version: "1.20.4", State: api.Stopped, ServerPort: 25566, RconPort: 25576}
+	if !reflect.DeepEqual(want, *creative) {
+		t.Fatalf("expected server `%+v`, got `%+v`", want, *creative)
+	}
+
+	testCases := []struct {
+		name    string
+		req     api.CreateServerRequest
+		wantErr error
+	}{
+		{name: "existing ID", req: api.CreateServerRequest{Id: "survival"}, wantErr: api.ErrServerExists},
+		{name: "invalid ID", req: api.CreateServerRequest{Id: "../survival"}, wantErr: api.ErrInvalidServerID},
+		{name: "empty ID", req: api.CreateServerRequest{Id: ""}, wantErr: api.ErrInvalidServerID},
+		{name: "jar cache ID", req: api.CreateServerRequest{Id: jarsDirName}, wantErr: api.ErrInvalidServerID},
+		{name: "port in use", req: api.CreateServerRequest{Id: "lobby", Port: ref(25566)}, wantErr: api.ErrPortInUse},
+		{name: "RCON port in use", req: api.CreateServerRequest{Id: "lobby", Port: ref(25575)}, wantErr: api.ErrPortInUse},
+		{name: "unsupported version", req: api.CreateServerRequest{Id: "lobby", Version: ref("1.99")}, wantErr: api.ErrInvalidServer},
+	}
+
+	for _, tc := range testCases {
+		if _, err := registry.CreateServer(tc.req); !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: expected error `%v`, got `%v`", tc.name, tc.wantErr, err)
+		}
+	}
+	if _, err := os.Stat(path.Join(dir, "lobby")); !os.IsNotExist(err) {
+		t.Error("expected failed creations to leave nothing behind")
+	}
+
+	// clones get a copy of the data directory with their own ports
+	if err := os.WriteFile(path.Join(dir, "survival", "level.dat"), []byte("world"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(path.Join(dir, "survival", backupsDirName), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	clone, err := registry.CloneServer("survival", api.CloneServerRequest{Id: "survival-2"})
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	want = api.ServerInstance{Id: "survival-2", Version: "1.20.6", State: api.Stopped, ServerPort: 25567, RconPort: 25577}
+	if !reflect.DeepEqual(want, *clone) {
+		t.Fatalf("expected server `%+v`, got `%+v`", want, *clone)
+	}
+	if data, err := os.ReadFile(path.Join(dir, "survival-2", "level.dat")); err != nil || string(data) != "world" {
+		t.Fatalf("expected the world to be copied, got `%s` with error `%v`", data, err)
+	}
+	if _, err := os.Stat(path.Join(dir, "survival-2", backupsDirName)); !os.IsNotExist(err) {
+		t.Fatalf("expected backups not to be copied, got error `%v`", err)
+	}
+	if _, err := registry.CloneServer("lobby", api.CloneServerRequest{Id: "lobby-2"}); !errors.Is(err, api.ErrServerNotFound) {
+		t.Fatalf("expected error `%v`, got `%v`", api.ErrServerNotFound, err)
+	}
+
+	// servers are loaded again with their configuration
+	reloaded := newTestRegistry(t, dir)
+	if got, want := reloaded.Servers(), registry.Servers(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected servers `%+v`, got `%+v`", want, got)
+	}
+
+	if err := reloaded.DeleteServer("creative"); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if _, err := os.Stat(path.Join(dir, "creative")); !os.IsNotExist(err) {
+		t.Fatal("expected the data directory to be deleted")
+	}
+	if _, err := reloaded.Server("creative"); !errors.Is(err, api.ErrServerNotFound) {
+		t.Fatalf("expected error `%v`, got `%v`", api.ErrServerNotFound, err)
+	}
+	if err := reloaded.DeleteServer("creative"); !errors.Is(err, api.ErrServerNotFound) {
+		t.Fatalf("expected error `%v`, got `%v`", api.ErrServerNotFound, err)
+	}
+
+	// the ports of deleted servers are reused
+	lobby, err := reloaded.CreateServer(api.CreateServerRequest{Id: "lobby"})
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if lobby.ServerPort != 25566 || lobby.RconPort != 25576 {
+		t.Fatalf("expected ports 25566 and 25576, got %d and %d", lobby.ServerPort, lobby.RconPort)
+	}
+}
+
+func TestServerRegistryInUse(t *testing.T) {
+	server := newFakeJavaServer(t, fakeJava)
+
+	registry := newTestRegistry(t, t.TempDir())
+	registry.servers["survival"] = &registeredServer{
+		ID:     "survival",
+		Dir:    server.serverDir(),
+		server: server,
+	}
+
+	if err := server.Start(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	defer func() { _ = server.Stop() }()
+
+	if err := registry.DeleteServer("survival"); !errors.Is(err, api.ErrServerInUse) {
+		t.Fatalf("expected error `%v`, got `%v`", api.ErrServerInUse, err)
+	}
+	if _, err := registry.CloneServer("survival", api.CloneServerRequest{Id: "survival-2"}); !errors.Is(err, api.ErrServerInUse) {
+		t.Fatalf("expected error `%v`, got `%v`", api.ErrServerInUse, err)
+	}
+}
+
+func TestServerRegistryAdopt(t *testing.T) {
+	t.Parallel()
+
+	legacyDir := path.Join(t.TempDir(), "server-data")
+	registry := newTestRegistry(t, t.TempDir())
+
+	if err := registry.Adopt("default", legacyDir); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if _, err := os.Stat(path.Join(legacyDir, "config.json")); err != nil {
+		t.Fatalf("expected the configuration to be in the adopted directory, got `%v`", err)
+	}
+	if err := registry.Adopt("default", legacyDir); !errors.Is(err, api.ErrServerExists) {
+		t.Fatalf("expected error `%v`, got `%v`", api.ErrServerExists, err)
+	}
+}
+
+func TestServerRegistryCreateFailure(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	registry := newTestRegistry(t, dir)
+
+	// the registry file can't be written over a directory
+	if err := os.Mkdir(path.Join(dir, registryFile), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	if instance, err := registry.CreateServer(api.CreateServerRequest{Id: "survival"}); err == nil || instance != nil {
+		t.Fatalf("expected an error and no server, got `%v` and error `%v`", instance, err)
+	}
+	if servers := registry.Servers(); len(servers) != 0 {
+		t.Fatalf("expected no servers, got `%+v`", servers)
+	}
+	if _, err := os.Stat(path.Join(dir, "survival")); !os.IsNotExist(err) {
+		t.Fatalf("expected the data directory to be deleted, got error `%v`", err)
+	}
+}
+
+func TestServerRegistryServerProperties(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	registry := newTestRegistry(t, dir)
+
+	for _, id := range []string{"survival", "creative"} {
+		if _, err := registry.CreateServer(api.CreateServerRequest{Id: id}); err != nil {
+			t.Fatalf("expected no error, got `%v`", err)
+		}
+	}
+	if _, err := registry.CloneServer("survival", api.CloneServerRequest{Id: "survival-2"}); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+
+	// the Minecraft server reads its ports from server.properties
+	for _, tc := range []struct {
+		id                   string
+		serverPort, rconPort int
+	}{
+		{id: "survival", serverPort: 25565, rconPort: 25575},
+		{id: "creative", serverPort: 25566, rconPort: 25576},
+		{id: "survival-2", serverPort: 25567, rconPort: 25577},
+	} {
+		data, err := os.ReadFile(path.Join(dir, tc.id, "server.properties"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{
+			fmt.Sprintf("server-port=%d\n", tc.serverPort),
+			fmt.Sprintf("query.port=%d\n", tc.serverPort),
+			fmt.Sprintf("rcon.port=%d\n", tc.rconPort),
+		} {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("%s: expected server.properties to contain `%s`, got\n%s", tc.id, strings.TrimSpace(want), data)
+			}
+		}
+	}
+}
+
+func TestServerRegistryCreateWithoutLatestVersion(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	versions := path.Join(t.TempDir(), "versions.json")
+	if err := os.WriteFile(versions, []byte(`{}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	registry := NewServerRegistry(dir, versions)
+	if err := registry.Load(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+
+	if _, err := registry.CreateServer(api.CreateServerRequest{Id: "survival"}); !errors.Is(err, ErrCatalogRefresh) {
+		t.Fatalf("expected error `%v`, got `%v`", ErrCatalogRefresh, err)
+	}
+	if servers := registry.Servers(); len(servers) != 0 {
+		t.Fatalf("expected no servers, got `%+v`", servers)
+	}
+	if _, err := os.Stat(path.Join(dir, "survival")); !os.IsNotExist(err) {
+		t.Fatalf("expected the data directory to be deleted, got error `%v`", err)
+	}
+}
+
+func TestServerRegistryRefreshVersions(t *testing.T) {
+	t.Parallel()
+
+	var versionRequests atomic.Int32
+	ts := fakeManifestMirror(t, &versionRequests)
+
+	registry := newTestRegistry(t, t.TempDir())
+	for _, id := range []string{"survival", "creative"} {
+		if _, err := registry.CreateServer(api.CreateServerRequest{Id: id}); err != nil {
+			t.Fatalf("expected no error, got `%v`", err)
+		}
+	}
+	registry.SetManifestBaseURL(ts.URL + "/mirror")
+
+	versions, err := registry.RefreshVersions(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if !slices.Contains(versions, "24w14a") {
+		t.Fatalf("expected the refreshed versions to include `24w14a`, got `%v`", versions)
+	}
+
+	// every Minecraft server shares the refreshed versions
+	for _, id := range []string{"survival", "creative"} {
+		msi, err := registry.Server(id)
+		if err != nil {
+			t.Fatalf("expected no error, got `%v`", err)
+		}
+		if got := *msi.VersionsOfType(api.Snapshot); !reflect.DeepEqual([]string{"24w14a"}, got) {
+			t.Fatalf("%s: expected snapshots `[24w14a]`, got `%v`", id, got)
+		}
+	}
+
+	data, err := readJSON(registry.versions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved VersionMap
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if _, ok := saved["24w14a"]; !ok {
+		t.Fatalf("expected the refreshed versions to be saved, got `%v`", saved)
+	}
+}
@@ -0,0 +1,368 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+var ErrInvalidSchedule = api.ErrInvalidSchedule
+
+// playerJoinedPattern and playerLeftPattern match the messages the Minecraft
+// server logs when players join and leave. Chat messages can't match them,
+// since they start with the name of the player in angle brackets.
+var (
+	playerJoinedPattern = regexp.MustCompile(`^\w{1,16} joined the game$`)
+	playerLeftPattern   = regexp.MustCompile(`^\w{1,16} left the game$`)
+)
+
+func NewBackupSchedule() *api.BackupSchedule {
+	return &api.BackupSchedule{Enabled: false}
+}
+
+// ValidateBackupSchedule returns an api.ValidationError listing the fields of
+// schedule that are invalid.
+func ValidateBackupSchedule(schedule *api.BackupSchedule) error {
+	if schedule == nil {
+		return ErrNilConfig
+	}
+
+	var errs fieldErrors
+
+	hasCron := schedule.Cron != nil && len(*schedule.Cron) > 0
+	if hasCron {
+		if _, err := parseCron(*schedule.Cron); err != nil {
+			errs.add(ErrInvalidSchedule, "cron", "%v", err)
+		}
+	}
+	if schedule.IntervalMinutes != nil {
+		if *schedule.IntervalMinutes < 1 {
+			errs.add(ErrInvalidSchedule, "intervalMinutes", "must be positive")
+		}
+		if hasCron {
+			errs.add(ErrInvalidSchedule, "intervalMinutes", "must not be set along with cron")
+		}
+	}
+	if schedule.Enabled && !hasCron && schedule.IntervalMinutes == nil {
+		errs.add(ErrInvalidSchedule, "cron", "or intervalMinutes must be set while the schedule is enabled")
+	}
+
+	if r := schedule.Retention; r != nil {
+		for _, count := range []struct {
+			name  string
+			value int
+		}{
+			{"retention.hourly", r.Hourly},
+			{"retention.daily", r.Daily},
+			{"retention.weekly", r.Weekly},
+		} {
+			if count.value < 0 {
+				errs.add(ErrInvalidSchedule, count.name, "must not be negative")
+			}
+		}
+	}
+
+	return errs.err()
+}
+
+// BackupSchedule implements api.MinecraftServerInterface.
+func (m *JavaMinecraftServer) BackupSchedule() *api.BackupSchedule {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.backupSchedule == nil {
+		return nil
+	}
+
+	scheduleCpy := *m.backupSchedule
+
+	return &scheduleCpy
+}
+
+func (m *JavaMinecraftServer) CreateBackupSchedule() {
+	m.Lock()
+	defer m.Unlock()
+
+	m.backupSchedule = NewBackupSchedule()
+	m.backupScheduleUpdated()
+}
+
+func (m *JavaMinecraftServer) LoadBackupSchedule(file io.Reader) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.backupSchedule == nil {
+		return ErrNilConfig
+	}
+
+	if err := json.NewDecoder(file).Decode(m.backupSchedule); err != nil {
+		return err
+	}
+	m.backupScheduleUpdated()
+
+	return ValidateBackupSchedule(m.backupSchedule)
+}
+
+func (m *JavaMinecraftServer) SaveBackupSchedule(file io.Writer) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.backupSchedule == nil {
+		return ErrNilConfig
+	}
+
+	return json.NewEncoder(file).Encode(m.backupSchedule)
+}
+
+// SetBackupSchedule implements api.MinecraftServerInterface.
+func (m *JavaMinecraftServer) SetBackupSchedule(schedule *api.BackupSchedule) error {
+	if err := ValidateBackupSchedule(schedule); err != nil {
+		return err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.backupSchedule = schedule
+	m.backupScheduleUpdated()
+
+	return nil
+}
+
+// backupScheduleUpdated wakes up the backup scheduler, if it's running, so it
+// picks up the new schedule. m must be locked.
+func (m *JavaMinecraftServer) backupScheduleUpdated() {
+	select {
+	case m.backupScheduleChanged <- struct{}{}:
+	default:
+	}
+}
+
+// ScheduleBackups backs up the worlds of the Minecraft server on its backup
+// schedule, pruning the backups that fall out of its retention policy after
+// each backup. Changes to the schedule take effect right away.
+//
+// ScheduleBackups returns a function that stops scheduling backups, which
+// returns once a scheduled backup in progress has finished.
+func (m *JavaMinecraftServer) ScheduleBackups() (stop func()) {
+	m.Lock()
+	if m.backupScheduleChanged == nil {
+		m.backupScheduleChanged = make(chan struct{}, 1)
+	}
+	changed := m.backupScheduleChanged
+	m.Unlock()
+
+	done, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		last := time.Now()
+		for {
+			schedule := m.BackupSchedule()
+
+			var timer *time.Timer
+			var fire <-chan time.Time
+			if next := nextBackup(schedule, last, time.Now()); !next.IsZero() {
+				timer = time.NewTimer(time.Until(next))
+				fire = timer.C
+			}
+
+			select {
+			case <-done:
+				stopTimer(timer)
+				return
+			case <-changed:
+				stopTimer(timer)
+			case <-fire:
+				m.runScheduledBackup(schedule)
+				last = time.Now()
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+		<-stopped
+	}
+}
+
+func stopTimer(timer *time.Timer) {
+	if timer != nil {
+		timer.Stop()
+	}
+}
+
+// nextBackup returns the time the next backup on schedule is due, given that
+// the last one ended at last, or the zero time if there is none.
+func nextBackup(schedule *api.BackupSchedule, last, now time.Time) time.Time {
+	if schedule == nil || !schedule.Enabled {
+		return time.Time{}
+	}
+
+	if schedule.Cron != nil && len(*schedule.Cron) > 0 {
+		cron, err := parseCron(*schedule.Cron)
+		if err != nil {
+			return time.Time{}
+		}
+		return cron.next(now)
+	}
+	if schedule.IntervalMinutes != nil && *schedule.IntervalMinutes > 0 {
+		return last.Add(time.Duration(*schedule.IntervalMinutes) * time.Minute)
+	}
+
+	return time.Time{}
+}
+
+// runScheduledBackup creates a backup on schedule and prunes the backups that
+// fall out of its retention policy.
+func (m *JavaMinecraftServer) runScheduledBackup(schedule *api.BackupSchedule) {
+	if schedule.SkipIfNoPlayers != nil && *schedule.SkipIfNoPlayers {
+		backups, err := m.Backups()
+		if err != nil {
+			log.Println("scheduled backup failed:", err)
+			return
+		}
+		if n := len(*backups); n > 0 && !m.playersSince((*backups)[n-1].Time) {
+			log.Println("skipping scheduled backup, no players have joined since the last backup")
+			return
+		}
+	}
+
+	backup, err := m.CreateBackup()
+	if err != nil {
+		log.Println("scheduled backup failed:", err)
+		return
+	}
+	log.Printf("created scheduled backup %s", backup.Id)
+
+	if schedule.Retention != nil {
+		if err := m.pruneBackups(*schedule.Retention); err != nil {
+			log.Println("failed to prune backups:", err)
+		}
+	}
+}
+
+// playersSince reports whether players have been on the Minecraft server since
+// t. Since players joining aren't logged over RCON, it always reports that
+// they have been if the console is attached over RCON.
+func (m *JavaMinecraftServer) playersSince(t time.Time) bool {
+	m.Lock()
+	console := m.console
+	m.Unlock()
+
+	return m.players.since(t) || console.AttachedOverRCON()
+}
+
+// playerActivity keeps track of the players on a Minecraft server from its
+// console output. It has a lock of its own, since the console output is
+// handled while the server is locked to wait for responses to commands.
+type playerActivity struct {
+	online   int
+	lastJoin time.Time
+
+	mutex sync.Mutex
+}
+
+// track counts the player that joined or left in message, if any.
+func (a *playerActivity) track(message string) {
+	joined := playerJoinedPattern.MatchString(message)
+	if !joined && !playerLeftPattern.MatchString(message) {
+		return
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if joined {
+		a.online++
+		a.lastJoin = time.Now()
+	} else if a.online > 0 {
+		a.online--
+	}
+}
+
+// since reports whether players are online or have joined since t.
+func (a *playerActivity) since(t time.Time) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.online > 0 || a.lastJoin.After(t)
+}
+
+// reset forgets the players online, once the Minecraft server has stopped.
+func (a *playerActivity) reset() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.online = 0
+}
+
+// pruneBackups deletes the backups that fall out of retention.
+func (m *JavaMinecraftServer) pruneBackups(retention api.BackupRetention) error {
+	if retention.Hourly == 0 && retention.Daily == 0 && retention.Weekly == 0 {
+		return nil
+	}
+
+	backups, err := m.Backups()
+	if err != nil {
+		return err
+	}
+
+	keep := retainedBackups(*backups, retention, time.Local)
+	for _, backup := range *backups {
+		if keep[backup.Id] {
+			continue
+		}
+		if err := m.DeleteBackup(backup.Id); err != nil && !errors.Is(err, ErrBackupNotFound) {
+			return err
+		}
+		log.Printf("pruned backup %s", backup.Id)
+	}
+
+	return nil
+}
+
+// retainedBackups returns the IDs of the backups, sorted oldest first, that
+// retention keeps: the newest backup of each of the last retention.Hourly
+// hours, retention.Daily days and retention.Weekly weeks in loc that have
+// backups.
+func retainedBackups(backups api.BackupList, retention api.BackupRetention, loc *time.Location) map[string]bool {
+	keep := make(map[string]bool)
+
+	for _, period := range []struct {
+		count int
+		start func(t time.Time) time.Time
+	}{
+		{retention.Hourly, func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+		}},
+		{retention.Daily, func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		}},
+		{retention.Weekly, func(t time.Time) time.Time {
+			// weeks start on Monday
+			return time.Date(t.Year(), t.Month(), t.Day()-(int(t.Weekday())+6)%7, 0, 0, 0, 0, loc)
+		}},
+	} {
+		kept := 0
+		var last time.Time
+		for i := len(backups) - 1; i >= 0 && kept < period.count; i-- {
+			start := period.start(backups[i].Time.In(loc))
+			if kept > 0 && start.Equal(last) {
+				continue
+			}
+			keep[backups[i].Id] = true
+			last = start
+			kept++
+		}
+	}
+
+	return keep
+}
@@ -0,0 +1,298 @@
+package minecraft
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+func TestValidateBackupSchedule(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		schedule   *api.BackupSchedule
+		wantErr    error
+		wantFields []string
+	}{
+		{name: "default", schedule: NewBackupSchedule()},
+		{name: "nil schedule", wantErr: ErrNilConfig},
+		{name: "cron", schedule: &api.BackupSchedule{Enabled: true, Cron: ref("0 */6 * * *")}},
+		{name: "interval", schedule: &api.BackupSchedule{Enabled: true, IntervalMinutes: ref(30)}},
+		{name: "disabled without a schedule", schedule: &api.BackupSchedule{Retention: &api.BackupRetention{Daily: 7}}},
+		{
+			name:       "enabled without a schedule",
+			schedule:   &api.BackupSchedule{Enabled: true},
+			wantErr:    ErrInvalidSchedule,
+			wantFields: []string{"cron"},
+		},
+		{
+			name:       "invalid cron",
+			schedule:   &api.BackupSchedule{Enabled: true, Cron: ref("every hour")},
+			wantErr:    ErrInvalidSchedule,
+			wantFields: []string{"cron"},
+		},
+		{
+			name:       "cron and interval",
+			schedule:   &api.BackupSchedule{Enabled: true, Cron: ref("@daily"), IntervalMinutes: ref(0)},
+			wantErr:    ErrInvalidSchedule,
+			wantFields: []string{"intervalMinutes", "intervalMinutes"},
+		},
+		{
+			name:       "negative retention",
+			schedule:   &api.BackupSchedule{Retention: &api.BackupRetention{Hourly: -1, Weekly: -1}},
+			wantErr:    ErrInvalidSchedule,
+			wantFields: []string{"retention.hourly", "retention.weekly"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateBackupSchedule(tc.schedule)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error `%v`, got `%v`", tc.wantErr, err)
+			}
+
+			var verr api.ValidationError
+			errors.As(err, &verr)
+			fields := make([]string, 0, len(verr))
+			for _, f := range verr {
+				fields = append(fields, f.Field)
+			}
+			if len(tc.wantFields) > 0 && !reflect.DeepEqual(tc.wantFields, fields) {
+				t.Fatalf("expected invalid fields `%v`, got `%v`", tc.wantFields, fields)
+			}
+		})
+	}
+}
+
+func TestNextBackup(t *testing.T) {
+	t.Parallel()
+
+	last := time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 10, 14, 10, 17, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		schedule *api.BackupSchedule
+		want     time.Time
+	}{
+		{name: "no schedule"},
+		{name: "disabled", schedule: &api.BackupSchedule{Cron: ref("@hourly")}},
+		{name: "cron", schedule: &api.BackupSchedule{Enabled: true, Cron: ref("@hourly")}, want: time.Date(2026, 10, 14, 11, 0, 0, 0, time.UTC)},
+		{name: "interval", schedule: &api.BackupSchedule{Enabled: true, IntervalMinutes: ref(30)}, want: time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		if got := nextBackup(tc.schedule, last, now); !got.Equal(tc.want) {
+			t.Errorf("%s: expected the next backup at %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestRetainedBackups(t *testing.T) {
+	t.Parallel()
+
+	times := []time.Time{
+		time.Date(2026, 9, 23, 12, 0, 0, 0, time.UTC), // Wednesday two weeks before
+		time.Date(2026, 10, 4, 23, 0, 0, 0, time.UTC), // Sunday the week before
+		time.Date(2026, 10, 6, 9, 0, 0, 0, time.UTC),  // Tuesday
+		time.Date(2026, 10, 7, 8, 0, 0, 0, time.UTC),  // Wednesday
+		time.Date(2026, 10, 7, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 7, 9, 30, 0, 0, time.UTC),
+		time.Date(2026, 10, 7, 10, 0, 0, 0, time.UTC),
+	}
+	backups := make(api.BackupList, 0, len(times))
+	for _, created := range times {
+		backups = append(backups, api.Backup{Id: created.Format(backupIDFormat), Time: created})
+	}
+
+	testCases := []struct {
+		name      string
+		retention api.BackupRetention
+		want      []int
+	}{
+		{name: "nothing", want: []int{}},
+		{name: "hourly", retention: api.BackupRetention{Hourly: 3}, want: []int{3, 5, 6}},
+		{name: "daily", retention: api.BackupRetention{Daily: 3}, want: []int{1, 2, 6}},
+		{name: "weekly", retention: api.BackupRetention{Weekly: 2}, want: []int{1, 6}},
+		{name: "more than there are", retention: api.BackupRetention{Weekly: 10}, want: []int{0, 1, 6}},
+		{name: "grandfather-father-son", retention: api.BackupRetention{Hourly: 2, Daily: 2, Weekly: 3}, want: []int{0, 1, 2, 5, 6}},
+	}
+
+	for _, tc := range testCases {
+		keep := retainedBackups(backups, tc.retention, time.UTC)
+
+		got := make([]int, 0, len(keep))
+		for i, backup := range backups {
+			if keep[backup.Id] {
+				got = append(got, i)
+			}
+		}
+		if !reflect.DeepEqual(tc.want, got) {
+			t.Errorf("%s: expected backups `%v` to be kept, got `%v`", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestPlayerActivity(t *testing.T) {
+	t.Parallel()
+
+	var players playerActivity
+	before := time.Now()
+
+	for _, message := range []string{
+		"<steve> alex joined the game",
+		"[Not Secure] <steve> alex joined the game",
+		"Starting minecraft server version 1.20.6",
+	} {
+		players.track(message)
+	}
+	if players.since(before) {
+		t.Fatal("expected chat messages not to count as players joining")
+	}
+
+	players.track("alex joined the game")
+	joined := time.Now()
+	if !players.since(joined) {
+		t.Fatal("expected players to be online")
+	}
+	players.track("alex left the game")
+	if players.since(joined) || !players.since(before) {
+		t.Fatal("expected players to have joined since before, but not since they left")
+	}
+
+	players.track("alex joined the game")
+	players.reset()
+	if players.since(time.Now()) {
+		t.Fatal("expected no players to be online once reset")
+	}
+}
+
+func TestRunScheduledBackup(t *testing.T) {
+	t.Parallel()
+
+	serverDir := t.TempDir()
+	createWorlds(t, serverDir, "world")
+	server := JavaMinecraftServer{
+		filepaths: &MinecraftServerConfigFilepaths{Properties: filepath.Join(serverDir, "properties.json")},
+	}
+	schedule := &api.BackupSchedule{Enabled: true, IntervalMinutes: ref(60), SkipIfNoPlayers: ref(true)}
+
+	count := func() int {
+		backups, err := server.Backups()
+		if err != nil {
+			t.Fatalf("expected no error, got `%v`", err)
+		}
+		return len(*backups)
+	}
+
+	// the first backup is never skipped, and later ones are until a player
+	// joins
+	server.runScheduledBackup(schedule)
+	server.runScheduledBackup(schedule)
+	if n := count(); n != 1 {
+		t.Fatalf("expected 1 backup, got %d", n)
+	}
+	server.players.track("steve joined the game")
+	server.runScheduledBackup(schedule)
+	if n := count(); n != 2 {
+		t.Fatalf("expected 2 backups, got %d", n)
+	}
+	// players still online count too
+	server.runScheduledBackup(schedule)
+	if n := count(); n != 3 {
+		t.Fatalf("expected 3 backups, got %d", n)
+	}
+}
+
+func TestPruneBackups(t *testing.T) {
+	t.Parallel()
+
+	serverDir := t.TempDir()
+	createWorlds(t, serverDir, "world")
+	server := JavaMinecraftServer{
+		filepaths: &MinecraftServerConfigFilepaths{Properties: filepath.Join(serverDir, "properties.json")},
+	}
+	backup, err := server.CreateBackup()
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+
+	// older backups, exactly a week apart so that they're in different weeks
+	// in every time zone
+	backupsDir := filepath.Join(serverDir, backupsDirName)
+	data, err := os.ReadFile(filepath.Join(backupsDir, backup.Id+backupExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{backup.Id}
+	for weeks := 1; weeks <= 3; weeks++ {
+		id := backup.Time.AddDate(0, 0, -7*weeks).Format(backupIDFormat)
+		if err := os.WriteFile(filepath.Join(backupsDir, id+backupExt), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if weeks == 1 {
+			want = append(want, id)
+		}
+	}
+
+	if err := server.pruneBackups(api.BackupRetention{}); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if backups, _ := server.Backups(); len(*backups) != 4 {
+		t.Fatalf("expected no backups to be pruned without a retention policy, got `%v`", *backups)
+	}
+
+	if err := server.pruneBackups(api.BackupRetention{Weekly: 2}); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	backups, err := server.Backups()
+	if err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	got := make([]string, 0, len(*backups))
+	for _, backup := range *backups {
+		got = append(got, backup.Id)
+	}
+	sort.Strings(want)
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected backups `%v`, got `%v`", want, got)
+	}
+}
+
+func TestScheduleBackups(t *testing.T) {
+	t.Parallel()
+
+	server := JavaMinecraftServer{}
+	server.CreateBackupSchedule()
+
+	stop := server.ScheduleBackups()
+	// the scheduler picks up changes without blocking whoever makes them
+	for i := 0; i < 3; i++ {
+		if err := server.SetBackupSchedule(&api.BackupSchedule{Enabled: true, Cron: ref("@yearly")}); err != nil {
+			t.Fatalf("expected no error, got `%v`", err)
+		}
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the scheduler to stop")
+	}
+}
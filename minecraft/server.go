@@ -1,46 +1,64 @@
 package minecraft
 
 import (
+	"context"
 	"errors"
 	"io"
+	"log"
 	"os"
 	"path"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/raian621/go-mcsc/api"
 )
 
 var (
-	ErrFilepathsNotProvided error = errors.New("filepaths for configuration files not provided")
-	ErrNilConfig            error = errors.New("config object was not initialized")
+	ErrFilepathsNotProvided error = api.ErrFilepathsNotProvided
+	ErrNilConfig            error = api.ErrNilConfig
 )
 
 type MinecraftServer struct {
-	allowlist     *api.Allowlist
-	args          *api.ServerArguments
-	bannedIPs     *api.BannedIPList
-	bannedPlayers *api.BannedPlayerList
-	config        *api.MinecraftServerConfig
-	filepaths     *MinecraftServerConfigFilepaths
-	ops           *api.ServerOperatorList
-	properties    *api.ServerProperties
-	console       *Console
-	// process      *exec.Cmd
-	versions *[]string
+	allowlist       *api.Allowlist
+	args            *api.ServerArguments
+	bannedIPs       *api.BannedIPList
+	bannedPlayers   *api.BannedPlayerList
+	config          *api.MinecraftServerConfig
+	filepaths       *MinecraftServerConfigFilepaths
+	ops             *api.ServerOperatorList
+	properties      *api.ServerProperties
+	console         *Console
+	process         *process
+	state           ProcessState
+	stopTimeout     time.Duration
+	versions        VersionMap
+	jars            *JarCache
+	java            *JavaRegistry
+	backups         *BackupManager
+	backupSchedule  *api.BackupSchedule
+	manifestBaseURL string
+	// subscribers to changes of the configuration files, see WatchConfigs
+	configSubscribers map[chan api.ConfigEvent]struct{}
+	// wakes up the backup scheduler when the schedule changes, see
+	// ScheduleBackups
+	backupScheduleChanged chan struct{}
+	// the players on the Minecraft server, tracked from its console output
+	players playerActivity
 
 	mutex sync.Mutex
 }
 
 type MinecraftServerConfigFilepaths struct {
-	Allowlist          string
-	Args               string
-	BannedPlayers      string
-	BannedIPs          string
-	Config             string
-	Ops                string
-	Properties         string
-	PropertiesTemplate string
-	Versions           string
+	Allowlist      string
+	Args           string
+	BackupSchedule string
+	BannedPlayers  string
+	BannedIPs      string
+	Config         string
+	Ops            string
+	Properties     string
+	Versions       string
 }
 
 type JavaMinecraftServer MinecraftServer
@@ -72,11 +90,17 @@ func (m *JavaMinecraftServer) LoadConfigs() error {
 			SaveFn:   m.SaveArgs,
 			Filepath: m.filepaths.Args,
 		},
+		{
+			LoadFn:   m.LoadBackupSchedule,
+			CreateFn: m.CreateBackupSchedule,
+			SaveFn:   m.SaveBackupSchedule,
+			Filepath: m.filepaths.BackupSchedule,
+		},
 		{
 			LoadFn:   m.LoadBannedIPs,
 			CreateFn: m.CreateBannedIPs,
 			SaveFn:   m.SaveBannedIPs,
-			Filepath: m.filepaths.Allowlist,
+			Filepath: m.filepaths.BannedIPs,
 		},
 		{
 			LoadFn:   m.LoadBannedPlayers,
@@ -105,242 +129,278 @@ func (m *JavaMinecraftServer) LoadConfigs() error {
 		{
 			LoadFn:   m.LoadVersions,
 			CreateFn: m.CreateVersions,
-			SaveFn:   func(file io.Writer) error { return nil },
+			SaveFn:   m.SaveVersions,
 			Filepath: m.filepaths.Versions,
 		},
 	}
 
+	if err := m.importServerProperties(); err != nil {
+		return err
+	}
 	for _, ld := range loadData {
 		if err := loadJSON(ld.LoadFn, ld.SaveFn, ld.CreateFn, ld.Filepath); err != nil {
 			return err
 		}
 	}
 
-	return saveServerPropertiesTemplate(
-		m.properties,
-		m.filepaths.PropertiesTemplate,
-		path.Join(path.Dir(m.filepaths.Properties), "server.properties"),
-	)
+	return m.writeServerProperties()
+}
+
+// SaveConfigs implements api.MinecraftServerInterface.
+//
+// SaveConfigs saves every configuration file to its filepath and writes the
+// server properties to the server.properties file again.
+func (m *JavaMinecraftServer) SaveConfigs() error {
+	if m.filepaths == nil {
+		return ErrFilepathsNotProvided
+	}
+
+	saveData := []struct {
+		SaveFn   func(file io.Writer) error
+		Filepath string
+	}{
+		{SaveFn: m.SaveAllowlist, Filepath: m.filepaths.Allowlist},
+		{SaveFn: m.SaveArgs, Filepath: m.filepaths.Args},
+		{SaveFn: m.SaveBackupSchedule, Filepath: m.filepaths.BackupSchedule},
+		{SaveFn: m.SaveBannedIPs, Filepath: m.filepaths.BannedIPs},
+		{SaveFn: m.SaveBannedPlayers, Filepath: m.filepaths.BannedPlayers},
+		{SaveFn: m.SaveConfig, Filepath: m.filepaths.Config},
+		{SaveFn: m.SaveOperators, Filepath: m.filepaths.Ops},
+		{SaveFn: m.SaveProperties, Filepath: m.filepaths.Properties},
+	}
+
+	for _, sd := range saveData {
+		if err := saveJSON(sd.SaveFn, sd.Filepath); err != nil {
+			return err
+		}
+	}
+
+	return m.writeServerProperties()
 }
 
 // Restart implements api.MinecraftServerInterface.
+//
+// Restart stops the Minecraft server process if it is running and starts it
+// again with the current configuration.
 func (m *JavaMinecraftServer) Restart() error {
-	panic("unimplemented")
+	if err := m.Stop(); err != nil && !errors.Is(err, ErrServerNotRunning) {
+		return err
+	}
+
+	return m.Start()
 }
 
 // Start implements api.MinecraftServerInterface.
+//
+// Start makes sure the server jar of the configured version is present,
+// downloading it if it isn't, picks a Java runtime compatible with the version
+// unless a Java path is configured, then launches the Minecraft server process
+// in the server data directory and attaches a Console to it. Start returns once
+// the process has been spawned; the server is reported as ProcessStarting
+// until it logs that it is done loading.
 func (m *JavaMinecraftServer) Start() error {
+	m.Lock()
+	if err := m.checkStartable(); err != nil {
+		m.Unlock()
+		return err
+	}
+	version := m.config.Version
+	m.Unlock()
+
+	// the lock isn't held while the jar is downloaded since it can take a while
+	if err := m.ensureJar(context.Background(), version); err != nil {
+		return err
+	}
+	java, err := m.javaPath(version)
+	if err != nil {
+		return err
+	}
+
 	m.Lock()
 	defer m.Unlock()
+
+	// the server may have been started while the jar was being downloaded
+	if err := m.checkStartable(); err != nil {
+		return err
+	}
+
+	argv := BuildStringArgs(version, m.args)
+	argv[0] = java
+	log.Println("starting minecraft server process:", strings.Join(argv, " "))
+
+	if m.console == nil {
+		m.console = NewConsole()
+	}
+
+	var p *process
+	p, err = startProcess(
+		m.serverDir(),
+		argv,
+		BuildEnv(m.args),
+		m.console,
+		func(line api.ConsoleLine) { m.handleOutput(p, line) },
+		func(err error) { m.handleExit(p, err) },
+	)
+	if err != nil {
+		m.state = ProcessCrashed
+		return err
+	}
+
+	m.process = p
+	m.state = ProcessStarting
+	p.run()
+
+	log.Println("minecraft server process started with PID", p.pid())
+
 	return nil
-	// version := serverConfig.LockAndGetData().(*ServerConfig).Version
-	// serverConfig.Unlock()
-	// if err := downloadServerVersion("server-data", version); err != nil {
-	// 	return err
-	// }
-
-	// if serverCmd != nil && serverCmd.Process.Signal(syscall.Signal(0)) == nil {
-	// 	return nil
-	// }
-
-	// if err := stopExistingMinecraftServers(); err != nil {
-	// 	return err
-	// }
-
-	// args := serverArgs.LockAndGetData().(*ServerArgs)
-	// argsStr := BuildStringArgs(args)
-	// log.Println("args", argsStr)
-	// serverArgs.Unlock()
-	// serverCmd = exec.Command(argsStr[0], argsStr[1:]...)
-
-	// go func() {
-	// 	log.Println("starting minecraft server process...")
-
-	// 	oldDir, err := os.Getwd()
-	// 	if err != nil {
-	// 		log.Println(err)
-	// 		return
-	// 	}
-	// 	if err := os.Chdir("server-data"); err != nil {
-	// 		log.Println(err)
-	// 		return
-	// 	}
-
-	// 	ServerConsole = new(ConsoleMonitor)
-
-	// 	ServerConsole.Stdin, err = serverCmd.StdinPipe()
-	// 	if err != nil {
-	// 		log.Fatalln("error creating stdin pipe for minecraft server:", err)
-	// 	}
-	// 	ServerConsole.Stdout, err = serverCmd.StdoutPipe()
-	// 	if err != nil {
-	// 		log.Fatalln("error creating stdout pipe for minecraft server:", err)
-	// 	}
-	// 	ServerConsole.Stderr, err = serverCmd.StderrPipe()
-	// 	if err != nil {
-	// 		log.Fatalln("error creating stderr pipe for minecraft server:", err)
-	// 	}
-
-	// 	if err := serverCmd.Start(); err != nil {
-	// 		log.Println("error starting server:", err)
-	// 		serverCmd = nil
-	// 		return
-	// 	}
-	// 	if err := os.Chdir(oldDir); err != nil {
-	// 		log.Println(err)
-	// 		return
-	// 	}
-
-	// 	log.Println("minecraft server process started")
-	// }()
-
-	// return nil
 }
 
-// Start implements api.MinecraftServerInterface.
+// checkStartable returns an error if the Minecraft server can't be started.
+// m must be locked.
+func (m *JavaMinecraftServer) checkStartable() error {
+	if m.process != nil || m.console.Attached() {
+		return ErrServerRunning
+	}
+	if m.filepaths == nil {
+		return ErrFilepathsNotProvided
+	}
+	if m.config == nil || m.args == nil {
+		return ErrNilConfig
+	}
+	if err := ValidateArgs(m.args); err != nil {
+		return err
+	}
+	// the Minecraft server falls back to its defaults without properties
+	if m.properties != nil {
+		return ValidateProperties(m.properties, m.args)
+	}
+
+	return nil
+}
+
+// Stop implements api.MinecraftServerInterface.
+//
+// Stop sends the `stop` command to the Minecraft server console and waits for
+// the process to exit. If the process is still running after the stop timeout
+// has elapsed, it is killed.
+//
+// A Minecraft server attached over RCON is sent the `stop` command and
+// detached from the console without waiting for it to exit.
 func (m *JavaMinecraftServer) Stop() error {
-	panic("unimplemented")
+	m.Lock()
+	p := m.process
+	if p == nil && m.console.AttachedOverRCON() {
+		defer m.Unlock()
+		return m.stopRCON()
+	}
+	if p == nil {
+		m.Unlock()
+		return ErrServerNotRunning
+	}
+	m.state = ProcessStopping
+	timeout := m.stopTimeout
+	if timeout <= 0 {
+		timeout = DefaultStopTimeout
+	}
+	m.Unlock()
+
+	return p.stop(timeout)
+}
+
+// stopRCON stops the Minecraft server attached over RCON. m must be locked.
+func (m *JavaMinecraftServer) stopRCON() error {
+	err := m.console.SendCommand("stop")
+	if errors.Is(err, io.EOF) {
+		// the server may close the connection before responding
+		err = nil
+	}
+	m.console.Detach()
+	m.state = ProcessStopped
+
+	log.Println("stopped minecraft server attached over RCON")
+
+	return err
+}
+
+// State returns the current state of the Minecraft server process.
+func (m *JavaMinecraftServer) State() ProcessState {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.state == "" {
+		return ProcessStopped
+	}
+
+	return m.state
+}
+
+// PID returns the process ID of the Minecraft server process, or 0 if the
+// process is not running.
+func (m *JavaMinecraftServer) PID() int {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.process == nil {
+		return 0
+	}
+
+	return m.process.pid()
+}
+
+func (m *JavaMinecraftServer) handleOutput(p *process, line api.ConsoleLine) {
+	if line.Stream != api.Stdout {
+		return
+	}
+	m.players.track(consoleMessage(line.Text))
+	if !isDoneLine(line.Text) {
+		return
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	if m.process == p && m.state == ProcessStarting {
+		m.state = ProcessRunning
+		log.Println("minecraft server is done loading")
+	}
+}
+
+func (m *JavaMinecraftServer) handleExit(p *process, err error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.process != p {
+		return
+	}
+
+	m.process = nil
+	m.players.reset()
+
+	if m.state != ProcessStopping && err != nil {
+		log.Println("minecraft server process crashed:", err)
+		m.state = ProcessCrashed
+		return
+	}
+
+	log.Println("minecraft server process stopped")
+	m.state = ProcessStopped
+}
+
+// serverDir returns the directory the Minecraft server process runs in.
+func (m *JavaMinecraftServer) serverDir() string {
+	return path.Dir(m.filepaths.Properties)
 }
 
 func NewJavaMinecraftServer(filepaths *MinecraftServerConfigFilepaths) api.MinecraftServerInterface {
-	jms := JavaMinecraftServer{filepaths: filepaths}
+	jms := JavaMinecraftServer{
+		console:   NewConsole(),
+		filepaths: filepaths,
+	}
 	return &jms
 }
 
 var _ api.MinecraftServerInterface = (*JavaMinecraftServer)(nil)
 
-// func StartMinecraftServer() error {
-// 	version := serverConfig.LockAndGetData().(*ServerConfig).Version
-// 	serverConfig.Unlock()
-// 	if err := downloadServerVersion("server-data", version); err != nil {
-// 		return err
-// 	}
-
-// 	if serverCmd != nil && serverCmd.Process.Signal(syscall.Signal(0)) == nil {
-// 		return nil
-// 	}
-
-// 	if err := stopExistingMinecraftServers(); err != nil {
-// 		return err
-// 	}
-
-// 	args := serverArgs.LockAndGetData().(*ServerArgs)
-// 	argsStr := BuildStringArgs(args)
-// 	log.Println("args", argsStr)
-// 	serverArgs.Unlock()
-// 	serverCmd = exec.Command(argsStr[0], argsStr[1:]...)
-
-// 	go func() {
-// 		log.Println("starting minecraft server process...")
-
-// 		oldDir, err := os.Getwd()
-// 		if err != nil {
-// 			log.Println(err)
-// 			return
-// 		}
-// 		if err := os.Chdir("server-data"); err != nil {
-// 			log.Println(err)
-// 			return
-// 		}
-
-// 		ServerConsole = new(ConsoleMonitor)
-
-// 		ServerConsole.Stdin, err = serverCmd.StdinPipe()
-// 		if err != nil {
-// 			log.Fatalln("error creating stdin pipe for minecraft server:", err)
-// 		}
-// 		ServerConsole.Stdout, err = serverCmd.StdoutPipe()
-// 		if err != nil {
-// 			log.Fatalln("error creating stdout pipe for minecraft server:", err)
-// 		}
-// 		ServerConsole.Stderr, err = serverCmd.StderrPipe()
-// 		if err != nil {
-// 			log.Fatalln("error creating stderr pipe for minecraft server:", err)
-// 		}
-
-// 		if err := serverCmd.Start(); err != nil {
-// 			log.Println("error starting server:", err)
-// 			serverCmd = nil
-// 			return
-// 		}
-// 		if err := os.Chdir(oldDir); err != nil {
-// 			log.Println(err)
-// 			return
-// 		}
-
-// 		log.Println("minecraft server process started")
-// 	}()
-
-// 	return nil
-// }
-
-// func StopMinecraftServer() {
-// 	if serverCmd != nil && serverCmd.Process.Signal(syscall.Signal(0)) == nil {
-// 		if ServerConsole != nil {
-// 			if !ServerConsole.TryLock() {
-
-// 				return
-// 			}
-
-// 			if _, err := fmt.Fprintln(ServerConsole.Stdin, "/stop"); err != nil {
-// 				log.Println("error sending stop command to server:", err)
-// 			}
-
-// 			ServerConsole.Stdin.Close()
-// 			ServerConsole.Stdout.Close()
-// 			ServerConsole.Stderr.Close()
-
-// 			ServerConsole.Unlock()
-// 			ServerConsole = nil
-// 		}
-
-// 		serverCmd = nil
-// 	}
-// }
-
-// func stopExistingMinecraftServers() error {
-// 	cmd := exec.Command("ps", "-ao", "args,pid")
-// 	outBytes, err := cmd.Output()
-// 	if err != nil {
-// 		return err
-// 	}
-// 	// if there's no output
-// 	if len(outBytes) == 0 {
-// 		return nil
-// 	}
-
-// 	outStr := string(outBytes)
-// 	commandData := strings.Split(outStr, "\n")[1:]
-// 	pattern, err := regexp.Compile(`^java .*-jar server.jar.*`)
-// 	if err != nil {
-// 		log.Fatalln(err)
-// 	}
-// 	for _, cd := range commandData {
-// 		if pattern.MatchString(cd) {
-// 			split := strings.Split(cd, " ")
-// 			pidStr := split[len(split)-1]
-// 			pid, err := strconv.ParseInt(pidStr, 10, 64)
-
-// 			if err != nil {
-// 				log.Println(err)
-// 				continue
-// 			}
-// 			log.Println("killing minecraft server process with PID", pid)
-// 			process, err := os.FindProcess(int(pid))
-// 			if err != nil {
-// 				log.Println(err)
-// 				continue
-// 			}
-
-// 			if err := process.Kill(); err != nil {
-// 				log.Println(err)
-// 			}
-// 		}
-// 	}
-
-// 	return nil
-// }
-
 func CreateServerFolder(filepath string) error {
 	if err := os.Mkdir(filepath, os.ModePerm); !errors.Is(err, os.ErrExist) && err != nil {
 		return err
@@ -0,0 +1,61 @@
+package minecraft
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/raian621/go-mcsc/api"
+)
+
+func TestSaveConfigs(t *testing.T) {
+	t.Parallel()
+
+	if err := (&JavaMinecraftServer{}).SaveConfigs(); err != ErrFilepathsNotProvided {
+		t.Fatalf("expected error `%v`, got `%v`", ErrFilepathsNotProvided, err)
+	}
+
+	dir := t.TempDir()
+	versions := path.Join(t.TempDir(), "versions.json")
+	if err := os.WriteFile(versions, []byte(`{"1.20.6": {"link": ""}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	filepaths := ServerFilepaths(dir, versions)
+
+	server := NewJavaMinecraftServer(filepaths)
+	if err := server.LoadConfigs(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+
+	op := api.ServerOperator{Name: "steve", Uuid: uuid.New(), Level: 4}
+	if err := server.Op(&op); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	player := api.PlayerInfo{Name: ref("alex")}
+	if err := server.AllowPlayer(&player); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	schedule := api.BackupSchedule{Enabled: true, Cron: ref("@daily"), Retention: &api.BackupRetention{Daily: 7}}
+	if err := server.SetBackupSchedule(&schedule); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if err := server.SaveConfigs(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+
+	reloaded := NewJavaMinecraftServer(filepaths)
+	if err := reloaded.LoadConfigs(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if want, got := (api.ServerOperatorList{op}), *reloaded.Ops(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected operators `%+v`, got `%+v`", want, got)
+	}
+	if want, got := (api.Allowlist{player}), *reloaded.Allowlist(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected allowlist `%+v`, got `%+v`", want, got)
+	}
+	if got := *reloaded.BackupSchedule(); !reflect.DeepEqual(schedule, got) {
+		t.Fatalf("expected backup schedule `%+v`, got `%+v`", schedule, got)
+	}
+}
@@ -1,106 +1,72 @@
 package minecraft
 
 import (
-	"io"
+	"fmt"
 	"log"
 	"os"
-	"text/template"
+	"strings"
 
+	"github.com/google/uuid"
 	"github.com/raian621/go-mcsc/api"
 )
 
-// func downloadServerVersion(filepath, version string) error {
-// 	file, err := os.Open("minecraft/data/server-download-links.json")
-// 	defer func() {
-// 		if err := file.Close(); err != nil {
-// 			log.Println(err)
-// 		}
-// 	}()
-
-// 	var versionsMap VersionMap
-// 	if err != nil {
-// 		return err
-// 	}
-
-// 	if err := versionsMap.Load(file); err != nil {
-// 		return err
-// 	}
-
-// 	var (
-// 		info VersionInfo
-// 		ok   bool
-// 	)
-// 	if info, ok = versionsMap[version]; !ok {
-// 		return errors.New("unsupported version")
-// 	}
-
-// 	serverJarPath := path.Join(filepath, fmt.Sprintf("server-%s.jar", version))
-// 	serverJarBytes, err := os.ReadFile(serverJarPath)
-// 	if err != nil {
-// 		if err := exec.Command("wget", info.Link, "-O", serverJarPath).Run(); err != nil {
-// 			return err
-// 		}
-// 		serverJarBytes, err = os.ReadFile(serverJarPath)
-// 		if err != nil {
-// 			return err
-// 		}
-// 	}
-
-// 	// verify downloaded file using checksum
-// 	md5Bytes := md5.Sum(serverJarBytes)
-// 	if info.Sum == string(md5Bytes[:]) {
-// 		return nil
-// 	}
-
-// 	return nil
-// }
-
 func closeFile(file *os.File) {
 	if err := file.Close(); err != nil {
 		log.Println("unexpected error closing file:", err)
 	}
 }
 
-func saveServerPropertiesTemplate(props *api.ServerProperties, tmplFilepath, propertiesFilepath string) error {
-	tmpl, err := template.New("server.properties.tmpl").Parse(tmplFilepath)
-	if err != nil {
-		return err
+// samePlayer reports whether a and b refer to the same player. Every
+// identifier the two players both have, their UUID and their name, must match,
+// and they must have at least one of them in common.
+func samePlayer(a, b api.PlayerInfo) bool {
+	compared := false
+
+	if a.Uuid != nil && b.Uuid != nil && *a.Uuid != uuid.Nil && *b.Uuid != uuid.Nil {
+		if *a.Uuid != *b.Uuid {
+			return false
+		}
+		compared = true
 	}
+	if a.Name != nil && b.Name != nil && len(*a.Name) > 0 && len(*b.Name) > 0 {
+		// Minecraft player names are case insensitive
+		if !strings.EqualFold(*a.Name, *b.Name) {
+			return false
+		}
+		compared = true
+	}
+
+	return compared
+}
 
-	file, err := os.Create(propertiesFilepath)
-	if err != nil {
-		return err
+// playerName returns the name used to refer to p in Minecraft server commands.
+func playerName(p api.PlayerInfo) string {
+	if p.Name != nil && len(*p.Name) > 0 {
+		return *p.Name
 	}
-	defer closeFile(file)
-	if err := tmpl.Execute(file, *props); err != nil {
-		return err
+	if p.Uuid != nil {
+		return p.Uuid.String()
 	}
 
-	return nil
+	return ""
 }
 
-func loadJSON(
-	loadFn func(file io.Reader) error,
-	saveFn func(file io.Writer) error,
-	createFn func(),
-	filepath string,
-) error {
-	file, err := os.Open(filepath)
-	if os.IsNotExist(err) {
-		file, err := os.Create(filepath)
-		if err != nil {
-			return err
-		}
-		defer closeFile(file)
-		createFn()
-		err = saveFn(file)
-		if err != nil {
-			return err
-		}
+// fieldErrors collects the invalid fields of a configuration.
+type fieldErrors api.ValidationError
+
+// add adds field as invalid with err, for the reason format describes.
+func (e *fieldErrors) add(err error, field, format string, a ...any) {
+	*e = append(*e, api.FieldError{Err: err, Field: field, Reason: fmt.Sprintf(format, a...)})
+}
+
+// err returns an api.ValidationError listing the invalid fields, or nil if
+// there are none.
+func (e fieldErrors) err() error {
+	if len(e) == 0 {
 		return nil
 	}
-	defer closeFile(file)
-	return loadFn(file)
+
+	return api.ValidationError(e)
 }
 
 func ref[T any](v T) *T { return &v }
@@ -1,17 +1,199 @@
 package minecraft
 
 import (
+	"cmp"
 	"encoding/json"
-	"errors"
 	"io"
+	"regexp"
 	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raian621/go-mcsc/api"
+)
+
+// Aliases SetVersion accepts for the newest versions in the catalog.
+const (
+	VersionLatest         = "latest"
+	VersionLatestSnapshot = "latest-snapshot"
+)
+
+var ErrVersionUnsupported = api.ErrVersionUnsupported
+
+// VersionKind is the kind of a Minecraft version, going by its name.
+type VersionKind int
+
+const (
+	// VersionUnknown is any version name that can't be parsed, like
+	// `b1.7.3` or April Fools' snapshots.
+	VersionUnknown VersionKind = iota
+	// VersionRelease is a release, like `1.20.6`.
+	VersionRelease
+	// VersionPreRelease is a pre-release, like `1.20.5-pre1` or
+	// `1.14 Pre-Release 2`.
+	VersionPreRelease
+	// VersionReleaseCandidate is a release candidate, like `1.20.5-rc1`.
+	VersionReleaseCandidate
+	// VersionSnapshot is a weekly snapshot, like `24w14a`.
+	VersionSnapshot
+)
+
+var (
+	releasePattern    = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?$`)
+	preReleasePattern = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?(?:-pre| Pre-Release )(\d+)$`)
+	candidatePattern  = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?-rc(\d+)$`)
+	snapshotPattern   = regexp.MustCompile(`^(\d{2})w(\d{2})([a-z])$`)
 )
 
-var ErrVersionUnsupported = errors.New("unsupported server version passed")
+// Version is a parsed Minecraft version name.
+type Version struct {
+	Name string
+	Kind VersionKind
+
+	// Major, Minor and Patch are the release a release, pre-release or
+	// release candidate is for, and Build is the number of the pre-release
+	// or release candidate.
+	Major, Minor, Patch, Build int
+
+	// Year, Week and Letter identify a weekly snapshot.
+	Year, Week int
+	Letter     byte
+}
+
+// ParseVersion parses a Minecraft version name. Names that can't be parsed
+// are returned as a Version of kind VersionUnknown.
+func ParseVersion(name string) Version {
+	v := Version{Name: name}
+
+	atoi := func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	release := func(match []string) {
+		v.Major, v.Minor, v.Patch = atoi(match[1]), atoi(match[2]), atoi(match[3])
+	}
+
+	if match := releasePattern.FindStringSubmatch(name); match != nil {
+		v.Kind = VersionRelease
+		release(match)
+	} else if match := preReleasePattern.FindStringSubmatch(name); match != nil {
+		v.Kind = VersionPreRelease
+		release(match)
+		v.Build = atoi(match[4])
+	} else if match := candidatePattern.FindStringSubmatch(name); match != nil {
+		v.Kind = VersionReleaseCandidate
+		release(match)
+		v.Build = atoi(match[4])
+	} else if match := snapshotPattern.FindStringSubmatch(name); match != nil {
+		v.Kind = VersionSnapshot
+		v.Year, v.Week, v.Letter = atoi(match[1]), atoi(match[2]), match[3][0]
+	}
+
+	return v
+}
+
+// group orders the kinds of versions relative to each other: releases along
+// with their pre-releases and release candidates, then weekly snapshots, then
+// everything else.
+func (v Version) group() int {
+	switch v.Kind {
+	case VersionRelease, VersionPreRelease, VersionReleaseCandidate:
+		return 0
+	case VersionSnapshot:
+		return 1
+	}
+
+	return 2
+}
+
+// stage orders a release after its release candidates, which come after its
+// pre-releases.
+func (v Version) stage() int {
+	switch v.Kind {
+	case VersionPreRelease:
+		return 0
+	case VersionReleaseCandidate:
+		return 1
+	}
+
+	return 2
+}
+
+// Compare returns -1 if v is older than w, 1 if v is newer than w and 0 if
+// they are the same version. Releases are ordered semantically with their
+// pre-releases and release candidates coming before them, and weekly
+// snapshots are ordered by when they came out. Since which release a weekly
+// snapshot leads up to can't be told from its name, weekly snapshots are
+// ordered after all releases, followed by versions that can't be parsed in
+// lexical order.
+func (v Version) Compare(w Version) int {
+	if c := cmp.Compare(v.group(), w.group()); c != 0 {
+		return c
+	}
+
+	switch v.group() {
+	case 0:
+		for _, c := range []int{
+			cmp.Compare(v.Major, w.Major),
+			cmp.Compare(v.Minor, w.Minor),
+			cmp.Compare(v.Patch, w.Patch),
+			cmp.Compare(v.stage(), w.stage()),
+			cmp.Compare(v.Build, w.Build),
+		} {
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	case 1:
+		for _, c := range []int{
+			cmp.Compare(v.Year, w.Year),
+			cmp.Compare(v.Week, w.Week),
+			cmp.Compare(v.Letter, w.Letter),
+		} {
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+
+	return strings.Compare(v.Name, w.Name)
+}
+
+// CompareVersions compares two Minecraft version names, see Version.Compare.
+func CompareVersions(a, b string) int {
+	return ParseVersion(a).Compare(ParseVersion(b))
+}
 
 type VersionInfo struct {
-	Link string `json:"link"`
-	Sum  string `json:"sum"` // md5 sum
+	Link        string          `json:"link"`
+	Sum         string          `json:"sum,omitempty"` // md5 sum
+	SHA1        string          `json:"sha1,omitempty"`
+	Type        api.ReleaseType `json:"type,omitempty"`
+	ReleaseTime *time.Time      `json:"releaseTime,omitempty"`
+	JavaVersion int             `json:"javaVersion,omitempty"` // required Java major version
+}
+
+// checksum returns the checksum the server jar is verified with.
+func (v VersionInfo) checksum() string {
+	if len(v.SHA1) > 0 {
+		return v.SHA1
+	}
+
+	return v.Sum
+}
+
+// releaseType returns the release type of the version. Versions without one
+// are from before the catalog tracked release types, when it only had
+// releases.
+func (v VersionInfo) releaseType() api.ReleaseType {
+	if len(v.Type) == 0 {
+		return api.Release
+	}
+
+	return v.Type
 }
 
 type VersionMap map[string]VersionInfo
@@ -22,7 +204,77 @@ func (v VersionMap) Versions() []string {
 		versions = append(versions, version)
 	}
 
-	slices.Sort(versions)
+	slices.SortFunc(versions, CompareVersions)
+	return versions
+}
+
+// LatestRelease returns the newest release in v, or false if there are no
+// releases.
+func (v VersionMap) LatestRelease() (string, bool) {
+	latest, found := "", false
+	for version, info := range v {
+		if info.releaseType() != api.Release || ParseVersion(version).Kind != VersionRelease {
+			continue
+		}
+		if !found || CompareVersions(version, latest) > 0 {
+			latest, found = version, true
+		}
+	}
+
+	return latest, found
+}
+
+// LatestSnapshot returns the newest snapshot in v, including pre-releases and
+// release candidates, or false if there are no snapshots. Snapshots are
+// compared by release time if the catalog has it, since which release a
+// weekly snapshot leads up to can't be told from its name.
+func (v VersionMap) LatestSnapshot() (string, bool) {
+	latest, found := "", false
+	for version, info := range v {
+		if info.releaseType() != api.Snapshot {
+			continue
+		}
+		if !found || v.newer(version, latest) {
+			latest, found = version, true
+		}
+	}
+
+	return latest, found
+}
+
+// newer reports whether version a came out after version b.
+func (v VersionMap) newer(a, b string) bool {
+	infoA, infoB := v[a], v[b]
+	if infoA.ReleaseTime != nil && infoB.ReleaseTime != nil {
+		return infoA.ReleaseTime.After(*infoB.ReleaseTime)
+	}
+
+	return CompareVersions(a, b) > 0
+}
+
+// resolve resolves the version aliases accepted by SetVersion.
+func (v VersionMap) resolve(version string) (string, bool) {
+	switch version {
+	case VersionLatest:
+		return v.LatestRelease()
+	case VersionLatestSnapshot:
+		return v.LatestSnapshot()
+	}
+
+	_, ok := v[version]
+
+	return version, ok
+}
+
+// OfType returns the versions in v with the given release type.
+func (v VersionMap) OfType(releaseType api.ReleaseType) VersionMap {
+	versions := make(VersionMap)
+	for version, info := range v {
+		if info.releaseType() == releaseType {
+			versions[version] = info
+		}
+	}
+
 	return versions
 }
 
@@ -31,20 +283,20 @@ func (v *VersionMap) Load(file io.Reader) error {
 }
 
 // SetVersion implements api.MinecraftServerInterface.
+//
+// SetVersion also accepts VersionLatest and VersionLatestSnapshot, which are
+// resolved to the newest release or snapshot in the catalog when SetVersion is
+// called.
 func (m *JavaMinecraftServer) SetVersion(version string) error {
-	if m.versions == nil {
-		return ErrNilConfig
-	}
+	m.Lock()
+	defer m.Unlock()
 
-	supported := false
-	for _, supportedVersion := range *m.versions {
-		if version == supportedVersion {
-			supported = true
-			break
-		}
+	if m.versions == nil || m.config == nil {
+		return ErrNilConfig
 	}
 
-	if !supported {
+	version, ok := m.versions.resolve(version)
+	if !ok {
 		return ErrVersionUnsupported
 	}
 
@@ -62,32 +314,45 @@ func (m *JavaMinecraftServer) Versions() *[]string {
 		return nil
 	}
 
-	versionsCpy := make([]string, len(*m.versions))
-	copy(versionsCpy, *m.versions)
+	return ref(m.versions.Versions())
+}
 
-	return &versionsCpy
+// VersionsOfType implements api.MinecraftServerInterface.
+func (m *JavaMinecraftServer) VersionsOfType(releaseType api.ReleaseType) *[]string {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.versions == nil {
+		return nil
+	}
+
+	return ref(m.versions.OfType(releaseType).Versions())
 }
 
 func (m *JavaMinecraftServer) CreateVersions() {
 	m.Lock()
 	defer m.Unlock()
 
-	m.versions = ref(make([]string, 0))
+	m.versions = make(VersionMap)
 }
 
-func (m *JavaMinecraftServer) LoadVersions(file io.Reader) error {
+func (m *JavaMinecraftServer) SaveVersions(file io.Writer) error {
 	m.Lock()
 	defer m.Unlock()
 
-	m.versions = new([]string)
+	return encodeVersions(file, m.versions)
+}
+
+func (m *JavaMinecraftServer) LoadVersions(file io.Reader) error {
+	m.Lock()
+	defer m.Unlock()
 
-	versionsMap := make(VersionMap, 0)
+	versionsMap := make(VersionMap)
 	if err := versionsMap.Load(file); err != nil {
 		return err
 	}
 
-	// TODO: do this in place
-	*m.versions = versionsMap.Versions()
+	m.versions = versionsMap
 
 	return nil
 }
@@ -5,23 +5,26 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/raian621/go-mcsc/api"
 )
 
 func TestGetVersion(t *testing.T) {
 	t.Parallel()
 
 	var expected = []string{
-		"1.1", "1.10", "1.10.1", "1.10.2", "1.11", "1.11.1", "1.11.2", "1.12",
-		"1.12.1", "1.12.2", "1.13", "1.13.1", "1.13.2", "1.14", "1.14.1", "1.14.2",
-		"1.14.3", "1.14.4", "1.15", "1.15.1", "1.15.2", "1.16", "1.16.1", "1.16.2",
-		"1.16.3", "1.16.4", "1.16.5", "1.17", "1.17.1", "1.18", "1.18.1", "1.18.2",
-		"1.19", "1.19.1", "1.19.2", "1.19.3", "1.19.4", "1.2.5", "1.20", "1.20.1",
-		"1.20.2", "1.20.3", "1.20.4", "1.20.5", "1.20.6", "1.3", "1.3.1", "1.3.2",
-		"1.4", "1.4.1", "1.4.2", "1.4.3", "1.4.4", "1.4.5", "1.4.6", "1.4.7", "1.5",
-		"1.5.1", "1.5.2", "1.6", "1.6.1", "1.6.2", "1.6.3", "1.6.4", "1.7", "1.7.1",
-		"1.7.10", "1.7.2", "1.7.3", "1.7.4", "1.7.5", "1.7.6", "1.7.7", "1.7.8",
-		"1.7.9", "1.8", "1.8.1", "1.8.2", "1.8.3", "1.8.4", "1.8.5", "1.8.6", "1.8.7",
-		"1.8.8", "1.8.9", "1.9", "1.9.1", "1.9.2", "1.9.3", "1.9.4",
+		"1.1", "1.2.5", "1.3", "1.3.1", "1.3.2", "1.4", "1.4.1", "1.4.2", "1.4.3",
+		"1.4.4", "1.4.5", "1.4.6", "1.4.7", "1.5", "1.5.1", "1.5.2", "1.6", "1.6.1",
+		"1.6.2", "1.6.3", "1.6.4", "1.7", "1.7.1", "1.7.2", "1.7.3", "1.7.4", "1.7.5",
+		"1.7.6", "1.7.7", "1.7.8", "1.7.9", "1.7.10", "1.8", "1.8.1", "1.8.2", "1.8.3",
+		"1.8.4", "1.8.5", "1.8.6", "1.8.7", "1.8.8", "1.8.9", "1.9", "1.9.1", "1.9.2",
+		"1.9.3", "1.9.4", "1.10", "1.10.1", "1.10.2", "1.11", "1.11.1", "1.11.2",
+		"1.12", "1.12.1", "1.12.2", "1.13", "1.13.1", "1.13.2", "1.14", "1.14.1",
+		"1.14.2", "1.14.3", "1.14.4", "1.15", "1.15.1", "1.15.2", "1.16", "1.16.1",
+		"1.16.2", "1.16.3", "1.16.4", "1.16.5", "1.17", "1.17.1", "1.18", "1.18.1",
+		"1.18.2", "1.19", "1.19.1", "1.19.2", "1.19.3", "1.19.4", "1.20", "1.20.1",
+		"1.20.2", "1.20.3", "1.20.4", "1.20.5", "1.20.6",
 	}
 
 	server := JavaMinecraftServer{}
@@ -71,3 +74,134 @@ func TestSetVersion(t *testing.T) {
 		t.Fatalf("expected error `%v`, got `%v`", ErrVersionUnsupported, err)
 	}
 }
+
+func TestCompareVersions(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.2.5", b: "1.10", want: -1},
+		{a: "1.20", b: "1.20.0", want: 0},
+		{a: "1.20.5", b: "1.20.5", want: 0},
+		{a: "1.20.5-pre1", b: "1.20.5-pre2", want: -1},
+		{a: "1.20.5-pre4", b: "1.20.5-rc1", want: -1},
+		{a: "1.20.5-rc3", b: "1.20.5", want: -1},
+		{a: "1.20.5-pre1", b: "1.20.4", want: 1},
+		{a: "1.14 Pre-Release 2", b: "1.14-pre3", want: -1},
+		{a: "1.14 Pre-Release 5", b: "1.14", want: -1},
+		{a: "24w14a", b: "24w13a", want: 1},
+		{a: "24w14a", b: "24w14b", want: -1},
+		{a: "23w51b", b: "24w03a", want: -1},
+		{a: "24w14a", b: "1.20.6", want: 1},
+		{a: "b1.7.3", b: "24w14a", want: 1},
+		{a: "a1.0.4", b: "b1.7.3", want: -1},
+	}
+
+	for _, tc := range testCases {
+		if got := CompareVersions(tc.a, tc.b); got != tc.want {
+			t.Errorf("CompareVersions(%q, %q): expected %d, got %d", tc.a, tc.b, tc.want, got)
+		}
+		if got := CompareVersions(tc.b, tc.a); got != -tc.want {
+			t.Errorf("CompareVersions(%q, %q): expected %d, got %d", tc.b, tc.a, -tc.want, got)
+		}
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		want Version
+	}{
+		{name: "1.20.6", want: Version{Name: "1.20.6", Kind: VersionRelease, Major: 1, Minor: 20, Patch: 6}},
+		{name: "1.20", want: Version{Name: "1.20", Kind: VersionRelease, Major: 1, Minor: 20}},
+		{name: "1.20.5-pre2", want: Version{Name: "1.20.5-pre2", Kind: VersionPreRelease, Major: 1, Minor: 20, Patch: 5, Build: 2}},
+		{name: "1.14 Pre-Release 3", want: Version{Name: "1.14 Pre-Release 3", Kind: VersionPreRelease, Major: 1, Minor: 14, Build: 3}},
+		{name: "1.20.5-rc1", want: Version{Name: "1.20.5-rc1", Kind: VersionReleaseCandidate, Major: 1, Minor: 20, Patch: 5, Build: 1}},
+		{name: "24w14a", want: Version{Name: "24w14a", Kind: VersionSnapshot, Year: 24, Week: 14, Letter: 'a'}},
+		{name: "24w14potato", want: Version{Name: "24w14potato", Kind: VersionUnknown}},
+		{name: "rd-132211", want: Version{Name: "rd-132211", Kind: VersionUnknown}},
+	}
+
+	for _, tc := range testCases {
+		if got := ParseVersion(tc.name); got != tc.want {
+			t.Errorf("expected `%+v`, got `%+v`", tc.want, got)
+		}
+	}
+}
+
+func TestLatestVersions(t *testing.T) {
+	t.Parallel()
+
+	versions := VersionMap{
+		"1.2.5":       {},
+		"1.10":        {},
+		"1.20.4":      {Type: api.Release},
+		"1.20.5-pre1": {Type: api.Snapshot, ReleaseTime: ref(time.Date(2024, 4, 4, 0, 0, 0, 0, time.UTC))},
+		"1.20.5-rc1":  {Type: api.Snapshot, ReleaseTime: ref(time.Date(2024, 4, 16, 0, 0, 0, 0, time.UTC))},
+		"24w14a":      {Type: api.Snapshot, ReleaseTime: ref(time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC))},
+		"b1.7.3":      {Type: api.OldBeta},
+	}
+
+	if got, ok := versions.LatestRelease(); !ok || got != "1.20.4" {
+		t.Errorf("expected latest release `1.20.4`, got `%s`", got)
+	}
+	if got, ok := versions.LatestSnapshot(); !ok || got != "1.20.5-rc1" {
+		t.Errorf("expected latest snapshot `1.20.5-rc1`, got `%s`", got)
+	}
+
+	// without release times snapshots are ordered by name
+	versions["24w14a"] = VersionInfo{Type: api.Snapshot}
+	if got, ok := versions.LatestSnapshot(); !ok || got != "24w14a" {
+		t.Errorf("expected latest snapshot `24w14a`, got `%s`", got)
+	}
+
+	if _, ok := (VersionMap{"b1.7.3": {Type: api.OldBeta}}).LatestRelease(); ok {
+		t.Error("expected no latest release")
+	}
+	if _, ok := (VersionMap{"1.20.4": {}}).LatestSnapshot(); ok {
+		t.Error("expected no latest snapshot")
+	}
+}
+
+func TestSetVersionLatest(t *testing.T) {
+	t.Parallel()
+
+	server := JavaMinecraftServer{}
+	server.CreateConfig()
+
+	err := server.LoadVersions(bytes.NewBuffer([]byte(`{
+		"1.9.4": {"link": ""},
+		"1.20.6": {"link": ""},
+		"1.10": {"link": ""},
+		"24w14a": {"link": "", "type": "snapshot"}
+	}`)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		version string
+		want    string
+	}{
+		{version: VersionLatest, want: "1.20.6"},
+		{version: VersionLatestSnapshot, want: "24w14a"},
+	}
+
+	for _, tc := range testCases {
+		if err := server.SetVersion(tc.version); err != nil {
+			t.Fatalf("expected no error, got `%v`", err)
+		}
+		if server.config.Version != tc.want {
+			t.Fatalf("expected version `%s`, got `%s`", tc.want, server.config.Version)
+		}
+	}
+
+	server.CreateVersions()
+	if err := server.SetVersion(VersionLatest); err != ErrVersionUnsupported {
+		t.Fatalf("expected error `%v`, got `%v`", ErrVersionUnsupported, err)
+	}
+}
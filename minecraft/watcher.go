@@ -0,0 +1,294 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raian621/go-mcsc/api"
+)
+
+const (
+	// DefaultConfigWatchInterval is how often the configuration files the
+	// Minecraft server rewrites itself are checked for changes.
+	DefaultConfigWatchInterval = 2 * time.Second
+	// configSubscriberBuffer is how many events a config event subscriber can
+	// fall behind before events are dropped for it.
+	configSubscriberBuffer = 64
+)
+
+// watchedFile is a configuration file that the Minecraft server rewrites
+// itself, like ops.json when an operator runs `/op` in game.
+type watchedFile struct {
+	file api.ConfigEventFile
+	path string
+	// reload replaces the in-memory copy of the file with data and returns
+	// its entries. The server must be locked.
+	reload func(data []byte) (map[string]configEntry, error)
+
+	modTime time.Time
+	size    int64
+	entries map[string]configEntry
+}
+
+// configEntry is an entry of a watched file, like a banned player.
+type configEntry struct {
+	event api.ConfigEvent
+	raw   string
+}
+
+// WatchConfigs watches the allowlist, operator and ban files of the Minecraft
+// server for changes, checking them every interval. Changed files are loaded
+// again, replacing the in-memory copies, and every entry added, removed or
+// changed is published to the config event subscribers. This includes changes
+// made through the server controller, once they're saved.
+//
+// Files are polled rather than watched with inotify, since the Minecraft
+// server doesn't write them often. WatchConfigs returns a function that stops
+// watching, which returns once the files are no longer checked.
+func (m *JavaMinecraftServer) WatchConfigs(interval time.Duration) (stop func()) {
+	if m.filepaths == nil {
+		return func() {}
+	}
+
+	files := []*watchedFile{
+		{file: api.Whitelist, path: m.filepaths.Allowlist, reload: m.reloadAllowlist},
+		{file: api.Ops, path: m.filepaths.Ops, reload: m.reloadOps},
+		{file: api.BannedPlayers, path: m.filepaths.BannedPlayers, reload: m.reloadBannedPlayers},
+		{file: api.BannedIps, path: m.filepaths.BannedIPs, reload: m.reloadBannedIPs},
+	}
+	// the first check only records the files as they are
+	for _, f := range files {
+		m.checkConfigFile(f)
+	}
+
+	done, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				for _, f := range files {
+					m.publishConfigEvents(m.checkConfigFile(f))
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+		<-stopped
+	}
+}
+
+// SubscribeConfigEvents implements api.MinecraftServerInterface.
+//
+// SubscribeConfigEvents returns a channel that receives the changes to the
+// watched configuration files from now on, and a function that cancels the
+// subscription and closes the channel. Events are dropped for subscribers
+// that fall too far behind.
+func (m *JavaMinecraftServer) SubscribeConfigEvents() (<-chan api.ConfigEvent, func()) {
+	ch := make(chan api.ConfigEvent, configSubscriberBuffer)
+
+	m.Lock()
+	if m.configSubscribers == nil {
+		m.configSubscribers = make(map[chan api.ConfigEvent]struct{})
+	}
+	m.configSubscribers[ch] = struct{}{}
+	m.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			m.Lock()
+			defer m.Unlock()
+
+			delete(m.configSubscribers, ch)
+			close(ch)
+		})
+	}
+
+	return ch, cancel
+}
+
+// publishConfigEvents sends events to the config event subscribers.
+func (m *JavaMinecraftServer) publishConfigEvents(events []api.ConfigEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	for ch := range m.configSubscribers {
+		for _, event := range events {
+			select {
+			case ch <- event:
+			default:
+				// the subscriber fell behind, drop the event
+			}
+		}
+	}
+}
+
+// checkConfigFile loads f again if it changed since it was last checked and
+// returns the changes to its entries. Files that aren't valid JSON, like ones
+// the Minecraft server is in the middle of writing, are checked again next
+// time.
+func (m *JavaMinecraftServer) checkConfigFile(f *watchedFile) []api.ConfigEvent {
+	info, err := os.Stat(f.path)
+	if err != nil || (info.ModTime().Equal(f.modTime) && info.Size() == f.size) {
+		return nil
+	}
+	data, err := os.ReadFile(f.path)
+	if err != nil || !json.Valid(data) {
+		return nil
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	entries, err := f.reload(data)
+	if err != nil {
+		return nil
+	}
+
+	var events []api.ConfigEvent
+	if f.entries != nil {
+		events = diffConfigEntries(f.file, f.entries, entries)
+	}
+	f.modTime, f.size, f.entries = info.ModTime(), info.Size(), entries
+
+	return events
+}
+
+// diffConfigEntries returns the events that turn the entries before of file
+// into the entries after.
+func diffConfigEntries(file api.ConfigEventFile, before, after map[string]configEntry) []api.ConfigEvent {
+	now := time.Now().UTC()
+	var events []api.ConfigEvent
+	add := func(change api.ConfigEventChange, entry configEntry) {
+		event := entry.event
+		event.Time, event.File, event.Change = now, file, change
+		events = append(events, event)
+	}
+
+	keys := make([]string, 0, len(before)+len(after))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		old, hadEntry := before[key]
+		entry, hasEntry := after[key]
+		switch {
+		case !hadEntry:
+			add(api.Added, entry)
+		case !hasEntry:
+			add(api.Removed, old)
+		case old.raw != entry.raw:
+			add(api.Changed, entry)
+		}
+	}
+
+	return events
+}
+
+// configEntries returns the entries of list, described by describe.
+func configEntries[T any](list []T, describe func(T) api.ConfigEvent) map[string]configEntry {
+	entries := make(map[string]configEntry, len(list))
+	for _, v := range list {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			continue
+		}
+		event := describe(v)
+		entries[configEntryKey(event)] = configEntry{event: event, raw: string(raw)}
+	}
+
+	return entries
+}
+
+// configEntryKey returns what identifies the entry described by event in its
+// file: the IP address, or the player's UUID or name.
+func configEntryKey(event api.ConfigEvent) string {
+	if event.Ip != nil {
+		return "ip:" + *event.Ip
+	}
+	if p := event.Player; p != nil {
+		if p.Uuid != nil && *p.Uuid != uuid.Nil {
+			return "uuid:" + p.Uuid.String()
+		}
+		if p.Name != nil {
+			// Minecraft player names are case insensitive
+			return "name:" + strings.ToLower(*p.Name)
+		}
+	}
+
+	return ""
+}
+
+func (m *JavaMinecraftServer) reloadAllowlist(data []byte) (map[string]configEntry, error) {
+	var allowlist api.Allowlist
+	if err := json.Unmarshal(data, &allowlist); err != nil {
+		return nil, err
+	}
+	m.allowlist = &allowlist
+
+	return configEntries(allowlist, func(p api.PlayerInfo) api.ConfigEvent {
+		return api.ConfigEvent{Player: &p}
+	}), nil
+}
+
+func (m *JavaMinecraftServer) reloadOps(data []byte) (map[string]configEntry, error) {
+	var ops api.ServerOperatorList
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return nil, err
+	}
+	m.ops = &ops
+
+	return configEntries(ops, func(op api.ServerOperator) api.ConfigEvent {
+		return api.ConfigEvent{Player: &api.PlayerInfo{Name: &op.Name, Uuid: &op.Uuid}}
+	}), nil
+}
+
+func (m *JavaMinecraftServer) reloadBannedPlayers(data []byte) (map[string]configEntry, error) {
+	var bannedPlayers api.BannedPlayerList
+	if err := json.Unmarshal(data, &bannedPlayers); err != nil {
+		return nil, err
+	}
+	m.bannedPlayers = &bannedPlayers
+
+	return configEntries(bannedPlayers, func(p api.BannedPlayer) api.ConfigEvent {
+		return api.ConfigEvent{Player: &api.PlayerInfo{Name: p.Name, Uuid: &p.Uuid}}
+	}), nil
+}
+
+func (m *JavaMinecraftServer) reloadBannedIPs(data []byte) (map[string]configEntry, error) {
+	var bannedIPs api.BannedIPList
+	if err := json.Unmarshal(data, &bannedIPs); err != nil {
+		return nil, err
+	}
+	m.bannedIPs = &bannedIPs
+
+	return configEntries(bannedIPs, func(ip api.BannedIP) api.ConfigEvent {
+		return api.ConfigEvent{Ip: &ip.Ip}
+	}), nil
+}
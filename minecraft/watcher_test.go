@@ -0,0 +1,103 @@
+package minecraft
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raian621/go-mcsc/api"
+)
+
+func TestWatchConfigs(t *testing.T) {
+	t.Parallel()
+
+	versions := path.Join(t.TempDir(), "versions.json")
+	if err := os.WriteFile(versions, []byte(`{"1.20.6": {"link": ""}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	filepaths := ServerFilepaths(t.TempDir(), versions)
+
+	server := NewJavaMinecraftServer(filepaths).(*JavaMinecraftServer)
+	if err := server.LoadConfigs(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	events, cancel := server.SubscribeConfigEvents()
+	defer cancel()
+	stop := server.WatchConfigs(10 * time.Millisecond)
+	defer stop()
+
+	steve := uuid.MustParse("5f8eb73b-25be-4c5a-a50f-d27d65e30ca0")
+	wantEvent := func(name string, file api.ConfigEventFile, change api.ConfigEventChange) api.ConfigEvent {
+		t.Helper()
+
+		select {
+		case event := <-events:
+			if event.File != file || event.Change != change {
+				t.Fatalf("%s: expected %s %s event, got `%+v`", name, file, change, event)
+			}
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: timed out waiting for %s %s event", name, file, change)
+		}
+
+		return api.ConfigEvent{}
+	}
+	writeFile := func(filepath, data string) {
+		t.Helper()
+
+		if err := os.WriteFile(filepath, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// an operator runs `/op steve` in game
+	writeFile(filepaths.Ops, `[{"uuid": "`+steve.String()+`", "name": "steve", "level": 4, "bypassesPlayerLimit": false}]`)
+	event := wantEvent("op", api.Ops, api.Added)
+	if event.Player == nil || *event.Player.Name != "steve" || *event.Player.Uuid != steve {
+		t.Fatalf("expected steve to be the player, got `%+v`", event.Player)
+	}
+	want := api.ServerOperatorList{{Name: "steve", Uuid: steve, Level: 4}}
+	if got := *server.Ops(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected operators `%+v`, got `%+v`", want, got)
+	}
+
+	writeFile(filepaths.Ops, `[{"uuid": "`+steve.String()+`", "name": "steve", "level": 2, "bypassesPlayerLimit": false}]`)
+	wantEvent("op level", api.Ops, api.Changed)
+
+	// files the Minecraft server is in the middle of writing are skipped
+	writeFile(filepaths.Ops, `[{"uuid": "`+steve.String())
+	writeFile(filepaths.BannedIPs, `[{"ip": "192.0.2.1", "created": "", "source": "Server", "expires": "forever", "reason": ""}]`)
+	event = wantEvent("ban IP", api.BannedIps, api.Added)
+	if event.Ip == nil || *event.Ip != "192.0.2.1" {
+		t.Fatalf("expected IP `192.0.2.1`, got `%v`", event.Ip)
+	}
+	if got := *server.Ops(); len(got) != 1 || got[0].Level != 2 {
+		t.Fatalf("expected steve to stay an operator, got `%+v`", got)
+	}
+
+	writeFile(filepaths.Ops, `[]`)
+	wantEvent("deop", api.Ops, api.Removed)
+
+	// changes made through the server controller are published once saved
+	if err := server.AllowPlayer(&api.PlayerInfo{Name: ref("alex")}); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	if err := server.SaveConfigs(); err != nil {
+		t.Fatalf("expected no error, got `%v`", err)
+	}
+	event = wantEvent("allow", api.Whitelist, api.Added)
+	if event.Player == nil || *event.Player.Name != "alex" {
+		t.Fatalf("expected alex to be the player, got `%+v`", event.Player)
+	}
+
+	stop()
+	writeFile(filepaths.Ops, `[{"uuid": "`+steve.String()+`", "name": "steve", "level": 4, "bypassesPlayerLimit": false}]`)
+	select {
+	case event := <-events:
+		t.Fatalf("expected no event after watching stopped, got `%+v`", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}